@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/techies/streamify/internal/app"
@@ -50,6 +51,13 @@ func bootstrap() error {
 func main() {
 	_ = godotenv.Load()
 
+	if len(os.Args) > 1 && os.Args[1] == "create-owner" {
+		if err := createOwner(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if err := bootstrap(); err != nil {
 		log.Fatal(err)
 	}
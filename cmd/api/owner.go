@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/service"
+)
+
+// createOwner implements the create-owner subcommand, used once at install time:
+//
+//	OWNER_PASSWORD=... streamify create-owner -email owner@example.com -username owner
+//
+// An existing account with that email is promoted instead when the password matches.
+func createOwner(args []string) error {
+	fs := flag.NewFlagSet("create-owner", flag.ContinueOnError)
+	email := fs.String("email", "", "owner email address (required)")
+	username := fs.String("username", "", "username, required when the account doesn't exist yet")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Read from the environment so the password doesn't end up in shell history or ps output
+	password := os.Getenv("OWNER_PASSWORD")
+	if *email == "" || password == "" {
+		fs.Usage()
+		return errors.New("create-owner: -email and OWNER_PASSWORD are required")
+	}
+
+	appCfg, err := app.New()
+	if err != nil {
+		return err
+	}
+	defer appCfg.Close()
+
+	user, appErr := service.NewUserService(appCfg.DB, appCfg).BootstrapOwner(context.Background(), service.BootstrapOwnerParams{
+		Email:    *email,
+		Username: *username,
+		Password: password,
+	})
+	if appErr != nil {
+		if appErr.Err != nil {
+			return fmt.Errorf("create-owner: %s: %w", appErr.Message, appErr.Err)
+		}
+		return fmt.Errorf("create-owner: %s", appErr.Message)
+	}
+
+	fmt.Printf("Owner ready: %s <%s> (%s)\n", user.Username, user.Email, user.ID)
+	return nil
+}
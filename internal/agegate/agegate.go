@@ -0,0 +1,89 @@
+package agegate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlockedCode is the error code returned when explicit content is blocked for the viewer
+const BlockedCode = "explicit_content_blocked"
+
+// Rules holds the age limits, per jurisdiction where they differ from the defaults.
+// Jurisdictions are ISO 3166-1 alpha-2 country codes as stored on the profile.
+type Rules struct {
+	// AdultAge is the age from which explicit content may be shown
+	AdultAge int
+	// MinimumAge is the age required to create an account
+	MinimumAge int
+
+	adultAgeByCountry   map[string]int
+	minimumAgeByCountry map[string]int
+}
+
+// NewRules builds the rules. Overrides use the "CC=age,CC=age" format, e.g. "KR=19".
+func NewRules(adultAge, minimumAge int, adultOverrides, minimumOverrides []string) (*Rules, error) {
+	r := &Rules{AdultAge: adultAge, MinimumAge: minimumAge}
+
+	var err error
+	if r.adultAgeByCountry, err = parseOverrides(adultOverrides); err != nil {
+		return nil, fmt.Errorf("age of majority: %w", err)
+	}
+	if r.minimumAgeByCountry, err = parseOverrides(minimumOverrides); err != nil {
+		return nil, fmt.Errorf("minimum age: %w", err)
+	}
+	return r, nil
+}
+
+// Age returns the age in completed years on now. People born on 29 February
+// turn a year older on 1 March in non-leap years.
+func Age(birthDate, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// IsAdult reports whether someone born on birthDate is an adult in country
+func (r *Rules) IsAdult(birthDate time.Time, country string, now time.Time) bool {
+	return Age(birthDate, now) >= lookup(r.adultAgeByCountry, country, r.AdultAge)
+}
+
+// CanRegister reports whether someone born on birthDate is old enough to sign up in country
+func (r *Rules) CanRegister(birthDate time.Time, country string, now time.Time) bool {
+	return Age(birthDate, now) >= lookup(r.minimumAgeByCountry, country, r.MinimumAge)
+}
+
+// ExplicitAllowed decides whether explicit content may be shown. Without a birth
+// date the viewer is treated as a minor; adults can opt in to filtering.
+func (r *Rules) ExplicitAllowed(birthDate *time.Time, country string, filterExplicit bool, now time.Time) bool {
+	if filterExplicit || birthDate == nil {
+		return false
+	}
+	return r.IsAdult(*birthDate, country, now)
+}
+
+func lookup(ages map[string]int, country string, fallback int) int {
+	if age, ok := ages[strings.ToUpper(country)]; ok {
+		return age
+	}
+	return fallback
+}
+
+func parseOverrides(entries []string) (map[string]int, error) {
+	ages := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		country, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid override %q, expected CC=age", entry)
+		}
+		age, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || age < 0 {
+			return nil, fmt.Errorf("invalid age in %q", entry)
+		}
+		ages[strings.ToUpper(strings.TrimSpace(country))] = age
+	}
+	return ages, nil
+}
@@ -0,0 +1,65 @@
+package agegate
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAge(t *testing.T) {
+	cases := []struct {
+		birth, now time.Time
+		want       int
+	}{
+		{date(2000, 6, 15), date(2018, 6, 14), 17},
+		{date(2000, 6, 15), date(2018, 6, 15), 18},
+		{date(2004, 2, 29), date(2022, 2, 28), 17},
+		{date(2004, 2, 29), date(2022, 3, 1), 18},
+	}
+	for _, c := range cases {
+		if got := Age(c.birth, c.now); got != c.want {
+			t.Errorf("Age(%s, %s) = %d, want %d", c.birth.Format(time.DateOnly), c.now.Format(time.DateOnly), got, c.want)
+		}
+	}
+}
+
+func TestRules_Jurisdiction(t *testing.T) {
+	rules, err := NewRules(18, 13, []string{"KR=19"}, []string{"de=16"})
+	if err != nil {
+		t.Fatalf("NewRules returned error: %v", err)
+	}
+	now := date(2026, 1, 1)
+	birth := date(2007, 6, 1) // 18
+
+	if !rules.IsAdult(birth, "US", now) {
+		t.Fatal("expected 18 year old to be an adult by default")
+	}
+	if rules.IsAdult(birth, "kr", now) {
+		t.Fatal("expected 18 year old to be a minor in KR")
+	}
+	if rules.CanRegister(date(2011, 6, 1), "DE", now) {
+		t.Fatal("expected 14 year old to be too young to register in DE")
+	}
+	if !rules.CanRegister(date(2011, 6, 1), "FR", now) {
+		t.Fatal("expected 14 year old to be able to register by default")
+	}
+}
+
+func TestRules_ExplicitAllowed(t *testing.T) {
+	rules, _ := NewRules(18, 13, nil, nil)
+	now := date(2026, 1, 1)
+	adult := date(1990, 1, 1)
+
+	if rules.ExplicitAllowed(nil, "", false, now) {
+		t.Fatal("expected unknown birth date to be filtered")
+	}
+	if !rules.ExplicitAllowed(&adult, "", false, now) {
+		t.Fatal("expected adult to be allowed explicit content")
+	}
+	if rules.ExplicitAllowed(&adult, "", true, now) {
+		t.Fatal("expected adult who opted in to filtering to be filtered")
+	}
+}
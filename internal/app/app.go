@@ -9,8 +9,18 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+	"github.com/techies/streamify/internal/agegate"
+	"github.com/techies/streamify/internal/challenge"
 	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/imaging"
+	"github.com/techies/streamify/internal/mailer"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/password"
+	"github.com/techies/streamify/internal/registration"
+	"github.com/techies/streamify/internal/sms"
+	"github.com/techies/streamify/internal/storage"
 	"github.com/techies/streamify/internal/utils"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type AppConfig struct {
@@ -20,6 +30,27 @@ type AppConfig struct {
 	JWTSecret      string
 	FrontendURL    string
 	AllowedOrigins []string
+	Mailer         mailer.Mailer
+	SMS            sms.SMSSender
+	Registration   *registration.Policy
+	Challenge      *challenge.Guard
+	Passwords      *password.Hasher
+	PasswordPolicy *password.Policy
+	AgeRules       *agegate.Rules
+	Cursors        *pagination.Codec
+	Storage        storage.Storage
+	ImageLimits    imaging.Limits
+
+	ReservedUsernames      []string
+	UsernameChangeCooldown time.Duration
+	UsernameHoldPeriod     time.Duration
+}
+
+// defaultReservedUsernames can't be registered or changed to; extend with RESERVED_USERNAMES
+var defaultReservedUsernames = []string{
+	"admin", "administrator", "root", "system", "support", "help",
+	"streamify", "official", "staff", "moderator", "owner", "security",
+	"api", "me", "settings", "profiles", "null", "undefined",
 }
 
 func New() (*AppConfig, error) {
@@ -37,6 +68,66 @@ func New() (*AppConfig, error) {
 		return nil, errors.New("JWT_SECRET is required")
 	}
 
+	registrationPolicy, err := registration.NewPolicy(
+		os.Getenv("REGISTRATION_MODE"),
+		utils.GetEnvList("ALLOWED_EMAIL_DOMAINS"),
+		os.Getenv("DISPOSABLE_EMAIL_DOMAINS_FILE"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	passwords, err := password.NewHasher(
+		os.Getenv("PASSWORD_HASH_ALGORITHM"),
+		password.Argon2Params{
+			Memory:      uint32(utils.GetEnvInt("ARGON2_MEMORY_KIB", int(password.DefaultArgon2Params.Memory))),
+			Iterations:  uint32(utils.GetEnvInt("ARGON2_ITERATIONS", int(password.DefaultArgon2Params.Iterations))),
+			Parallelism: uint8(utils.GetEnvInt("ARGON2_PARALLELISM", int(password.DefaultArgon2Params.Parallelism))),
+			SaltLength:  password.DefaultArgon2Params.SaltLength,
+			KeyLength:   password.DefaultArgon2Params.KeyLength,
+		},
+		utils.GetEnvInt("BCRYPT_COST", bcrypt.DefaultCost),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordPolicy, err := password.NewPolicy(
+		os.Getenv("COMMON_PASSWORDS_FILE"),
+		os.Getenv("BREACHED_PASSWORDS_FILE"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ageRules, err := agegate.NewRules(
+		utils.GetEnvInt("AGE_OF_MAJORITY", 18),
+		utils.GetEnvInt("MINIMUM_SIGNUP_AGE", 13),
+		utils.GetEnvList("AGE_OF_MAJORITY_BY_COUNTRY"),
+		utils.GetEnvList("MINIMUM_SIGNUP_AGE_BY_COUNTRY"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := storage.New(storage.Config{
+		Backend: os.Getenv("STORAGE_BACKEND"),
+		BaseURL: utils.GetEnvString("MEDIA_BASE_URL", "http://localhost:"+port+"/media"),
+		// Profile images are public, audio and video need presigned URLs
+		PublicPrefixes: []string{"avatars/", "covers/"},
+		Dir:            utils.GetEnvString("STORAGE_DIR", "data/media"),
+		Secret:         utils.GetEnvString("STORAGE_SIGNING_SECRET", jwt),
+		Endpoint:       os.Getenv("S3_ENDPOINT"),
+		Region:         os.Getenv("S3_REGION"),
+		Bucket:         os.Getenv("S3_BUCKET"),
+		AccessKey:      os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretKey:      os.Getenv("S3_SECRET_ACCESS_KEY"),
+		PathStyle:      os.Getenv("S3_PATH_STYLE") == "true",
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	conn, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		return nil, err
@@ -62,6 +153,33 @@ func New() (*AppConfig, error) {
 			"http://localhost:3000",
 			"https://yourdomain.com",
 		},
+		Mailer:       mailer.NewLogMailer(),
+		SMS:          sms.NewSender(os.Getenv("SMS_OUTBOX_FILE")),
+		Registration: registrationPolicy,
+		Challenge: challenge.NewGuard(challenge.Config{
+			CaptchaVerifyURL:      utils.GetEnvString("CAPTCHA_VERIFY_URL", "https://api.hcaptcha.com/siteverify"),
+			CaptchaSecret:         os.Getenv("CAPTCHA_SECRET"),
+			CaptchaSiteKey:        os.Getenv("CAPTCHA_SITE_KEY"),
+			PoWSecret:             jwt,
+			PoWDifficulty:         utils.GetEnvInt("POW_DIFFICULTY", 20),
+			PoWTTL:                5 * time.Minute,
+			Routes:                utils.GetEnvList("CHALLENGE_ROUTES"),
+			LoginFailureThreshold: utils.GetEnvInt("CHALLENGE_LOGIN_AFTER_FAILURES", 3),
+			LoginFailureWindow:    15 * time.Minute,
+		}),
+		Passwords:      passwords,
+		PasswordPolicy: passwordPolicy,
+		AgeRules:       ageRules,
+		Cursors:        pagination.NewCodec(jwt),
+		Storage:        store,
+		ImageLimits: imaging.Limits{
+			MaxBytes:  int64(utils.GetEnvInt("MAX_IMAGE_UPLOAD_BYTES", int(imaging.DefaultLimits.MaxBytes))),
+			MaxPixels: utils.GetEnvInt("MAX_IMAGE_PIXELS", imaging.DefaultLimits.MaxPixels),
+		},
+
+		ReservedUsernames:      append(defaultReservedUsernames, utils.GetEnvList("RESERVED_USERNAMES")...),
+		UsernameChangeCooldown: utils.GetEnvDuration("USERNAME_CHANGE_COOLDOWN", 30*24*time.Hour),
+		UsernameHoldPeriod:     utils.GetEnvDuration("USERNAME_HOLD_PERIOD", 30*24*time.Hour),
 	}, nil
 }
 
@@ -0,0 +1,73 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPVerifier validates widget tokens against an hCaptcha or Cloudflare Turnstile
+// compatible "siteverify" endpoint. VerifyURL is configurable so a local stub can
+// stand in for the provider in tests.
+type HTTPVerifier struct {
+	VerifyURL string
+	Secret    string
+	Client    *http.Client
+}
+
+func NewHTTPVerifier(verifyURL, secret string) *HTTPVerifier {
+	return &HTTPVerifier{
+		VerifyURL: verifyURL,
+		Secret:    secret,
+		Client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (v *HTTPVerifier) Verify(ctx context.Context, response, remoteIP string) error {
+	if response == "" {
+		return ErrInvalidResponse
+	}
+
+	form := url.Values{
+		"secret":   {v.Secret},
+		"response": {response},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.VerifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("captcha verify request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha verify request: unexpected status %d", resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("captcha verify response: %w", err)
+	}
+
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(result.ErrorCodes, ","))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newVerifyStub(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm returned error: %v", err)
+		}
+		if got := r.PostForm.Get("secret"); got != "secret" {
+			t.Errorf("expected secret to be sent, got %q", got)
+		}
+		if got := r.PostForm.Get("remoteip"); got != "203.0.113.7" {
+			t.Errorf("expected remoteip to be sent, got %q", got)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPVerifier_Success(t *testing.T) {
+	srv := newVerifyStub(t, http.StatusOK, `{"success":true}`)
+
+	v := NewHTTPVerifier(srv.URL, "secret")
+	if err := v.Verify(context.Background(), "token", "203.0.113.7"); err != nil {
+		t.Fatalf("expected token to verify, got %v", err)
+	}
+}
+
+func TestHTTPVerifier_Failure(t *testing.T) {
+	srv := newVerifyStub(t, http.StatusOK, `{"success":false,"error-codes":["invalid-input-response"]}`)
+
+	v := NewHTTPVerifier(srv.URL, "secret")
+	if err := v.Verify(context.Background(), "token", "203.0.113.7"); !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected ErrInvalidResponse, got %v", err)
+	}
+}
+
+func TestHTTPVerifier_UpstreamError(t *testing.T) {
+	srv := newVerifyStub(t, http.StatusInternalServerError, `oops`)
+
+	v := NewHTTPVerifier(srv.URL, "secret")
+	err := v.Verify(context.Background(), "token", "203.0.113.7")
+	if err == nil || errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected upstream error, got %v", err)
+	}
+}
+
+func TestHTTPVerifier_EmptyToken(t *testing.T) {
+	v := NewHTTPVerifier("http://127.0.0.1:0", "secret")
+	if err := v.Verify(context.Background(), "", ""); !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected ErrInvalidResponse, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrInvalidResponse means the client answered the challenge incorrectly
+	ErrInvalidResponse = errors.New("invalid challenge response")
+	// ErrExpired means the challenge was answered too late
+	ErrExpired = errors.New("challenge expired")
+	// ErrReplayed means a proof-of-work solution was already used
+	ErrReplayed = errors.New("challenge already used")
+)
+
+// ChallengeVerifier checks a client's answer to a bot challenge
+type ChallengeVerifier interface {
+	Verify(ctx context.Context, response, remoteIP string) error
+}
@@ -0,0 +1,247 @@
+package challenge
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// Request headers carrying a challenge answer
+const (
+	CaptchaHeader     = "X-Captcha-Token"
+	ProofOfWorkHeader = "X-PoW-Solution"
+)
+
+// Route names that can be protected through Config.Routes
+const (
+	RouteRegister = "register"
+	RouteLogin    = "login"
+)
+
+type Config struct {
+	// CaptchaVerifyURL and CaptchaSecret enable widget challenges when the secret is set
+	CaptchaVerifyURL string
+	CaptchaSecret    string
+	CaptchaSiteKey   string
+
+	PoWSecret     string
+	PoWDifficulty int
+	PoWTTL        time.Duration
+
+	// Routes lists the routes that require a challenge
+	Routes []string
+	// LoginFailureThreshold is the number of failed logins from an IP, or against
+	// an account, after which a challenge is required on the login route
+	LoginFailureThreshold int
+	LoginFailureWindow    time.Duration
+}
+
+// Guard decides when a request has to answer a challenge and verifies the answer.
+// Either a CAPTCHA token or a proof-of-work solution is accepted.
+type Guard struct {
+	Captcha     ChallengeVerifier
+	SiteKey     string
+	ProofOfWork *ProofOfWork
+
+	routes   map[string]bool
+	failures *failureTracker
+}
+
+func NewGuard(cfg Config) *Guard {
+	g := &Guard{
+		SiteKey:     cfg.CaptchaSiteKey,
+		ProofOfWork: NewProofOfWork(cfg.PoWSecret, cfg.PoWDifficulty, cfg.PoWTTL),
+		routes:      map[string]bool{},
+		failures:    newFailureTracker(cfg.LoginFailureThreshold, cfg.LoginFailureWindow),
+	}
+	if cfg.CaptchaSecret != "" {
+		g.Captcha = NewHTTPVerifier(cfg.CaptchaVerifyURL, cfg.CaptchaSecret)
+	}
+	for _, route := range cfg.Routes {
+		g.routes[route] = true
+	}
+	return g
+}
+
+// Enabled reports whether route is configured to require a challenge
+func (g *Guard) Enabled(route string) bool {
+	return g.routes[route]
+}
+
+// Require protects a route with a challenge on every request when enabled for it
+func (g *Guard) Require(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !g.Enabled(route) {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if g.check(w, r) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// RequireAfterFailures asks for a challenge once the client IP has accumulated
+// too many failures, see RecordFailure. Routes enabled through Config.Routes
+// always require one.
+func (g *Guard) RequireAfterFailures(route string) func(http.Handler) http.Handler {
+	if g.Enabled(route) {
+		return g.Require(route)
+	}
+	return func(next http.Handler) http.Handler {
+		if g.failures.threshold <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !g.failures.exceeded(ipKey(r)) || g.check(w, r) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// RequireForAccount asks for a challenge once account has accumulated too many
+// failures, whichever IP they came from. It is called by handlers after decoding
+// the account from the body and reports whether the request may proceed; on false
+// the response has been written. Routes enabled through Config.Routes are already
+// checked by the middleware.
+func (g *Guard) RequireForAccount(w http.ResponseWriter, r *http.Request, route, account string) bool {
+	if g.Enabled(route) || g.failures.threshold <= 0 {
+		return true
+	}
+	if !g.failures.exceeded(accountKey(account)) {
+		return true
+	}
+	return g.check(w, r)
+}
+
+// RecordFailure counts a failed attempt (e.g. wrong password) for the client IP
+// and for the targeted account
+func (g *Guard) RecordFailure(r *http.Request, account string) {
+	g.failures.add(ipKey(r))
+	if account != "" {
+		g.failures.add(accountKey(account))
+	}
+}
+
+// ResetFailures clears the failure counts of the client IP and account after a success
+func (g *Guard) ResetFailures(r *http.Request, account string) {
+	g.failures.reset(ipKey(r))
+	if account != "" {
+		g.failures.reset(accountKey(account))
+	}
+}
+
+// ipKey uses RemoteAddr as resolved by the RealIP middleware rather than the raw
+// X-Forwarded-For header, which the client can change on every attempt
+func ipKey(r *http.Request) string {
+	return "ip:" + utils.NormalizeIP(r.RemoteAddr)
+}
+
+func accountKey(account string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+// check verifies the challenge answer on r, writing an error response when it is missing or wrong
+func (g *Guard) check(w http.ResponseWriter, r *http.Request) bool {
+	ctx := r.Context()
+	ip := utils.NormalizeIP(r.RemoteAddr)
+
+	var err error
+	switch {
+	case r.Header.Get(CaptchaHeader) != "" && g.Captcha != nil:
+		err = g.Captcha.Verify(ctx, r.Header.Get(CaptchaHeader), ip)
+	case r.Header.Get(ProofOfWorkHeader) != "":
+		err = g.ProofOfWork.Verify(ctx, r.Header.Get(ProofOfWorkHeader), ip)
+	default:
+		w.Header().Set("X-Challenge-Required", g.accepted())
+		utils.RespondWithError(w, http.StatusPreconditionRequired, "Challenge required")
+		return false
+	}
+
+	if err != nil {
+		logger.Warn(ctx, "Challenge verification failed", "ip", ip, "error", err)
+		if !errors.Is(err, ErrInvalidResponse) && !errors.Is(err, ErrExpired) && !errors.Is(err, ErrReplayed) {
+			utils.RespondWithError(w, http.StatusServiceUnavailable, "Challenge verification unavailable")
+			return false
+		}
+		w.Header().Set("X-Challenge-Required", g.accepted())
+		utils.RespondWithError(w, http.StatusForbidden, "Challenge failed", err)
+		return false
+	}
+	return true
+}
+
+func (g *Guard) accepted() string {
+	if g.Captcha != nil {
+		return "captcha, pow"
+	}
+	return "pow"
+}
+
+// failureTracker counts failures per key within a sliding window
+type failureTracker struct {
+	threshold int
+	window    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*failureEntry
+}
+
+type failureEntry struct {
+	count int
+	since time.Time
+}
+
+func newFailureTracker(threshold int, window time.Duration) *failureTracker {
+	return &failureTracker{
+		threshold: threshold,
+		window:    window,
+		entries:   map[string]*failureEntry{},
+	}
+}
+
+func (t *failureTracker) add(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	e, ok := t.entries[key]
+	if !ok || now.Sub(e.since) > t.window {
+		t.prune(now)
+		e = &failureEntry{since: now}
+		t.entries[key] = e
+	}
+	e.count++
+}
+
+func (t *failureTracker) exceeded(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.entries[key]
+	if !ok || time.Since(e.since) > t.window {
+		return false
+	}
+	return e.count >= t.threshold
+}
+
+func (t *failureTracker) reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, key)
+}
+
+func (t *failureTracker) prune(now time.Time) {
+	for key, e := range t.entries {
+		if now.Sub(e.since) > t.window {
+			delete(t.entries, key)
+		}
+	}
+}
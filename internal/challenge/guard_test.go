@@ -0,0 +1,132 @@
+package challenge
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestGuard(routes ...string) *Guard {
+	return NewGuard(Config{
+		PoWSecret:             "secret",
+		PoWDifficulty:         8,
+		PoWTTL:                time.Minute,
+		Routes:                routes,
+		LoginFailureThreshold: 3,
+		LoginFailureWindow:    time.Minute,
+	})
+}
+
+func newRequest(remoteAddr string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestGuard_RequireWithoutAnswer(t *testing.T) {
+	g := newTestGuard(RouteRegister)
+	rec := serve(g.Require(RouteRegister)(okHandler), newRequest("203.0.113.7:1234"))
+
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("X-Challenge-Required"); got != "pow" {
+		t.Fatalf("expected X-Challenge-Required to be %q, got %q", "pow", got)
+	}
+}
+
+func TestGuard_RequireWithProofOfWork(t *testing.T) {
+	g := newTestGuard(RouteRegister)
+	c, _ := g.ProofOfWork.Issue()
+
+	r := newRequest("203.0.113.7:1234")
+	r.Header.Set(ProofOfWorkHeader, solve(t, c.Challenge, c.Difficulty))
+	if rec := serve(g.Require(RouteRegister)(okHandler), r); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestGuard_RequireDisabledRoute(t *testing.T) {
+	g := newTestGuard()
+	if rec := serve(g.Require(RouteRegister)(okHandler), newRequest("203.0.113.7:1234")); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestGuard_RequireAfterFailures(t *testing.T) {
+	g := newTestGuard()
+	h := g.RequireAfterFailures(RouteLogin)(okHandler)
+
+	for i := 0; i < 3; i++ {
+		if rec := serve(h, newRequest("203.0.113.7:1234")); rec.Code != http.StatusOK {
+			t.Fatalf("attempt %d: expected 200 below the threshold, got %d", i+1, rec.Code)
+		}
+		g.RecordFailure(newRequest("203.0.113.7:1234"), "")
+	}
+
+	rec := serve(h, newRequest("203.0.113.7:1234"))
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428 after the threshold, got %d", rec.Code)
+	}
+	if rec.Header().Get("X-Challenge-Required") == "" {
+		t.Fatal("expected X-Challenge-Required header")
+	}
+
+	if rec := serve(h, newRequest("198.51.100.1:1234")); rec.Code != http.StatusOK {
+		t.Fatalf("expected other IPs to be unaffected, got %d", rec.Code)
+	}
+
+	g.ResetFailures(newRequest("203.0.113.7:1234"), "")
+	if rec := serve(h, newRequest("203.0.113.7:1234")); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after reset, got %d", rec.Code)
+	}
+}
+
+func TestGuard_RequireAfterFailuresIgnoresForwardedFor(t *testing.T) {
+	g := newTestGuard()
+	h := g.RequireAfterFailures(RouteLogin)(okHandler)
+
+	for i := 0; i < 3; i++ {
+		r := newRequest("203.0.113.7:1234")
+		r.Header.Set("X-Forwarded-For", "10.0.0."+string(rune('1'+i)))
+		g.RecordFailure(r, "")
+	}
+
+	r := newRequest("203.0.113.7:1234")
+	r.Header.Set("X-Forwarded-For", "10.0.0.9")
+	if rec := serve(h, r); rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428 despite a rotated X-Forwarded-For, got %d", rec.Code)
+	}
+}
+
+func TestGuard_RequireForAccount(t *testing.T) {
+	g := newTestGuard()
+
+	for i := 0; i < 3; i++ {
+		g.RecordFailure(newRequest("203.0.113."+string(rune('1'+i))+":1234"), "Victim@example.com")
+	}
+
+	rec := httptest.NewRecorder()
+	if g.RequireForAccount(rec, newRequest("198.51.100.1:1234"), RouteLogin, "victim@example.com") {
+		t.Fatal("expected the account to require a challenge from a new IP")
+	}
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	if !g.RequireForAccount(rec, newRequest("198.51.100.1:1234"), RouteLogin, "other@example.com") {
+		t.Fatal("expected other accounts to be unaffected")
+	}
+}
@@ -0,0 +1,141 @@
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProofOfWork is a stateless hashcash-style challenge for clients that can't show
+// a CAPTCHA widget. The server signs the challenge instead of storing it; the
+// client must find a nonce such that sha256(challenge + ":" + nonce) starts with
+// Difficulty zero bits, and sends back "challenge:nonce".
+type ProofOfWork struct {
+	Difficulty int
+	TTL        time.Duration
+
+	secret []byte
+
+	// used remembers solved challenges until they expire so a solution can't be replayed
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// Challenge is handed to the client to solve
+type Challenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	Algorithm  string    `json:"algorithm"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func NewProofOfWork(secret string, difficulty int, ttl time.Duration) *ProofOfWork {
+	return &ProofOfWork{
+		Difficulty: difficulty,
+		TTL:        ttl,
+		secret:     []byte(secret),
+		used:       map[string]time.Time{},
+	}
+}
+
+// Issue creates a new signed challenge
+func (p *ProofOfWork) Issue() (Challenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return Challenge{}, err
+	}
+
+	expiresAt := time.Now().Add(p.TTL)
+	payload := fmt.Sprintf("%d.%d.%s", p.Difficulty, expiresAt.Unix(), hex.EncodeToString(nonce))
+
+	return Challenge{
+		Challenge:  payload + "." + p.sign(payload),
+		Difficulty: p.Difficulty,
+		Algorithm:  "sha256",
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Verify checks a "challenge:nonce" response
+func (p *ProofOfWork) Verify(_ context.Context, response, _ string) error {
+	i := strings.LastIndex(response, ":")
+	if i < 0 || len(response)-i > 65 {
+		return ErrInvalidResponse
+	}
+	challenge := response[:i]
+
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 4 {
+		return ErrInvalidResponse
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(p.sign(payload)), []byte(parts[3])) {
+		return ErrInvalidResponse
+	}
+
+	difficulty, err := strconv.Atoi(parts[0])
+	if err != nil || difficulty < p.Difficulty {
+		return ErrInvalidResponse
+	}
+
+	expiresUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInvalidResponse
+	}
+	expiresAt := time.Unix(expiresUnix, 0)
+	if time.Now().After(expiresAt) {
+		return ErrExpired
+	}
+
+	sum := sha256.Sum256([]byte(response))
+	if leadingZeroBits(sum[:]) < difficulty {
+		return ErrInvalidResponse
+	}
+
+	return p.markUsed(challenge, expiresAt)
+}
+
+func (p *ProofOfWork) sign(payload string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (p *ProofOfWork) markUsed(challenge string, expiresAt time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for c, exp := range p.used {
+		if now.After(exp) {
+			delete(p.used, c)
+		}
+	}
+
+	if _, ok := p.used[challenge]; ok {
+		return ErrReplayed
+	}
+	p.used[challenge] = expiresAt
+	return nil
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
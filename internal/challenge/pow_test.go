@@ -0,0 +1,59 @@
+package challenge
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func solve(t *testing.T, challenge string, difficulty int) string {
+	t.Helper()
+	for i := 0; i < 1<<20; i++ {
+		response := challenge + ":" + strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(response))
+		if leadingZeroBits(sum[:]) >= difficulty {
+			return response
+		}
+	}
+	t.Fatal("no solution found")
+	return ""
+}
+
+func TestProofOfWork_Verify(t *testing.T) {
+	pow := NewProofOfWork("secret", 8, time.Minute)
+	c, err := pow.Issue()
+	if err != nil {
+		t.Fatalf("Issue returned error: %v", err)
+	}
+
+	response := solve(t, c.Challenge, c.Difficulty)
+	if err := pow.Verify(context.Background(), response, ""); err != nil {
+		t.Fatalf("expected valid solution to verify, got %v", err)
+	}
+	if err := pow.Verify(context.Background(), response, ""); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected replayed solution to fail with ErrReplayed, got %v", err)
+	}
+}
+
+func TestProofOfWork_RejectsForgedChallenge(t *testing.T) {
+	c, _ := NewProofOfWork("other-secret", 8, time.Minute).Issue()
+	response := solve(t, c.Challenge, c.Difficulty)
+
+	err := NewProofOfWork("secret", 8, time.Minute).Verify(context.Background(), response, "")
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected ErrInvalidResponse, got %v", err)
+	}
+}
+
+func TestProofOfWork_RejectsExpiredChallenge(t *testing.T) {
+	pow := NewProofOfWork("secret", 8, -time.Minute)
+	c, _ := pow.Issue()
+	response := solve(t, c.Challenge, c.Difficulty)
+
+	if err := pow.Verify(context.Background(), response, ""); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+}
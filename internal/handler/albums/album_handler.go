@@ -0,0 +1,127 @@
+package albums
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+)
+
+type AlbumHandler struct {
+	App     *app.AppConfig
+	Service *service.AlbumService
+}
+
+func NewAlbumHandler(app *app.AppConfig) *AlbumHandler {
+	return &AlbumHandler{App: app}
+}
+
+// artistActor describes the caller for artist permission checks
+func artistActor(ctx context.Context) (service.ArtistActor, error) {
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		return service.ArtistActor{}, err
+	}
+	return service.ArtistActor{
+		UserID:   userID,
+		Admin:    middleware.IsAdmin(ctx),
+		ArtistID: middleware.GetArtistID(ctx),
+	}, nil
+}
+
+// AlbumResponse is an album's release metadata
+type AlbumResponse struct {
+	ID         uuid.UUID `json:"id"`
+	ArtistID   uuid.UUID `json:"artist_id"`
+	ArtistName string    `json:"artist_name,omitempty"`
+	Title      string    `json:"title"`
+	// ReleaseType is album, ep, single or compilation
+	ReleaseType string `json:"release_type"`
+	// ReleaseDate is formatted as YYYY-MM-DD
+	ReleaseDate      *string `json:"release_date"`
+	Upc              *string `json:"upc"`
+	Label            *string `json:"label"`
+	CopyrightLine    *string `json:"copyright_line"`
+	PhonographicLine *string `json:"phonographic_line"`
+	CoverUrl         *string `json:"cover_url"`
+	// Renditions maps the edge length in pixels to the URL of an uploaded cover
+	Renditions map[string]string `json:"renditions,omitempty"`
+	TrackCount *int64            `json:"track_count,omitempty"`
+	// DurationSeconds sums the track durations; only reported with the tracklist
+	DurationSeconds *int64          `json:"duration_seconds,omitempty"`
+	Tracks          []TrackResponse `json:"tracks,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// TrackResponse is a song on an album's tracklist
+type TrackResponse struct {
+	SongID          uuid.UUID `json:"song_id"`
+	Title           string    `json:"title"`
+	DiscNumber      int32     `json:"disc_number"`
+	TrackNumber     *int32    `json:"track_number"`
+	DurationSeconds *int32    `json:"duration_seconds"`
+	Explicit        bool      `json:"explicit"`
+}
+
+type AlbumListResponse struct {
+	Albums     []AlbumResponse `json:"albums"`
+	Limit      int32           `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+func (h *AlbumHandler) mapAlbum(a database.Album) AlbumResponse {
+	resp := AlbumResponse{
+		ID:          a.ID,
+		ArtistID:    a.ArtistID,
+		Title:       a.Title,
+		ReleaseType: string(a.ReleaseType),
+		Renditions:  service.RenditionURLs(h.App, a.CoverKey),
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+	if a.ReleaseDate.Valid {
+		date := a.ReleaseDate.Time.Format(time.DateOnly)
+		resp.ReleaseDate = &date
+	}
+	if a.Upc.Valid {
+		resp.Upc = &a.Upc.String
+	}
+	if a.Label.Valid {
+		resp.Label = &a.Label.String
+	}
+	if a.CopyrightLine.Valid {
+		resp.CopyrightLine = &a.CopyrightLine.String
+	}
+	if a.PhonographicLine.Valid {
+		resp.PhonographicLine = &a.PhonographicLine.String
+	}
+	if a.CoverUrl.Valid {
+		resp.CoverUrl = &a.CoverUrl.String
+	}
+	return resp
+}
+
+func mapTracks(rows []database.ListAlbumTracksRow) []TrackResponse {
+	tracks := make([]TrackResponse, len(rows))
+	for i, t := range rows {
+		tracks[i] = TrackResponse{
+			SongID:     t.ID,
+			Title:      t.Title,
+			DiscNumber: t.DiscNumber,
+			Explicit:   t.Explicit,
+		}
+		if t.TrackNumber.Valid {
+			tracks[i].TrackNumber = &t.TrackNumber.Int32
+		}
+		if t.Duration.Valid {
+			tracks[i].DurationSeconds = &t.Duration.Int32
+		}
+	}
+	return tracks
+}
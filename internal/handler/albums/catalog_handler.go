@@ -0,0 +1,283 @@
+package albums
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type CreateAlbumRequest struct {
+	ArtistID uuid.UUID `json:"artist_id"`
+	Title    string    `json:"title"`
+	// ReleaseType is album (default), ep, single or compilation
+	ReleaseType string `json:"release_type"`
+	// ReleaseDate is formatted as YYYY-MM-DD
+	ReleaseDate      *string `json:"release_date"`
+	Upc              *string `json:"upc"`
+	Label            *string `json:"label"`
+	CopyrightLine    *string `json:"copyright_line"`
+	PhonographicLine *string `json:"phonographic_line"`
+}
+
+// CreateAlbum adds an album to an artist's catalog.
+// @Summary      Create album
+// @Description  Creates an album without tracks; add songs with PUT /albums/{id}/tracks. UPCs are 12 or 13 digits and unique. Needs an access token in the artist's context (any member role) or an admin.
+// @Tags         Albums
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateAlbumRequest  true  "Album"
+// @Success      201      {object}  AlbumResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/albums [post]
+func (h *AlbumHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req CreateAlbumRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ArtistID == uuid.Nil {
+		utils.RespondWithFieldErrors(w, http.StatusBadRequest, "Validation failed", map[string]string{"artist_id": "is required"})
+		return
+	}
+
+	album, appErr := h.Service.CreateAlbum(ctx, actor, service.CreateAlbumParams{
+		ArtistID:         req.ArtistID,
+		Title:            req.Title,
+		ReleaseType:      req.ReleaseType,
+		ReleaseDate:      req.ReleaseDate,
+		Upc:              req.Upc,
+		Label:            req.Label,
+		CopyrightLine:    req.CopyrightLine,
+		PhonographicLine: req.PhonographicLine,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Album created", "album_id", album.ID, "artist_id", album.ArtistID, "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusCreated, h.mapAlbum(album))
+}
+
+// GetAlbum returns an album with its tracklist.
+// @Summary      Get album
+// @Description  Public. Returns the album's release metadata and its tracklist ordered by disc and track number, with durations in seconds. Songs on the album without a track number come last.
+// @Tags         Albums
+// @Produce      json
+// @Param        id   path      string  true  "Album ID"
+// @Success      200  {object}  AlbumResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Router       /api/v1/albums/{id} [get]
+func (h *AlbumHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	albumID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	detail, appErr := h.Service.GetAlbum(ctx, albumID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := h.mapAlbum(detail.Album)
+	resp.ArtistName = detail.ArtistName
+	trackCount := int64(len(detail.Tracks))
+	resp.TrackCount = &trackCount
+	resp.DurationSeconds = &detail.Duration
+	resp.Tracks = mapTracks(detail.Tracks)
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListAlbums returns an artist's albums.
+// @Summary      List artist albums
+// @Description  Public. Lists the artist's albums newest first with keyset pagination, optionally of one release type. Follow next_cursor or the Link rel="next" header for the next page; filters must stay the same across pages.
+// @Tags         Albums
+// @Produce      json
+// @Param        artist_id  query     string  true   "Artist ID"
+// @Param        type       query     string  false  "Release type"  Enums(album, ep, single, compilation)
+// @Param        limit      query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor     query     string  false  "Cursor from the previous page"
+// @Success      200        {object}  AlbumListResponse
+// @Header       200        {string}  Link  "RFC 8288 links to the next and first pages"
+// @Failure      400        {object}  utils.ErrorResponse
+// @Failure      500        {object}  utils.ErrorResponse
+// @Router       /api/v1/albums [get]
+func (h *AlbumHandler) ListAlbums(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+	artistID, err := uuid.Parse(query.Get("artist_id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "artist_id must be a valid UUID", err)
+		return
+	}
+
+	// Cursors are bound to the filters they were issued for
+	scopeQuery := url.Values{}
+	for _, key := range []string{"artist_id", "type"} {
+		scopeQuery.Set(key, query.Get(key))
+	}
+	scope := "albums?" + scopeQuery.Encode()
+	page, err := h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	result, appErr := h.Service.ListArtistAlbums(ctx, artistID, query.Get("type"), page)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
+	resp := AlbumListResponse{
+		Albums:     make([]AlbumResponse, len(result.Albums)),
+		Limit:      page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
+	}
+	for i, row := range result.Albums {
+		resp.Albums[i] = h.mapAlbum(row.Album)
+		resp.Albums[i].TrackCount = &row.TrackCount
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// PatchAlbum applies a JSON merge patch to an album.
+// @Summary      Patch album
+// @Description  RFC 7396 merge patch of the album's title, release_type, release_date, upc, label, copyright_line and phonographic_line: absent fields are left unchanged and null clears a field (the title and release type can't be cleared). Needs an access token in the artist's context (any member role) or an admin.
+// @Tags         Albums
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id       path      string              true  "Album ID"
+// @Param        request  body      CreateAlbumRequest  true  "Fields to change; null clears (artist_id is ignored)"
+// @Success      200      {object}  AlbumResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      415      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/albums/{id} [patch]
+func (h *AlbumHandler) PatchAlbum(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	albumID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req service.AlbumPatch
+	if err := patch.Decode(r, &req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid merge patch", err)
+		return
+	}
+
+	album, appErr := h.Service.PatchAlbum(ctx, actor, albumID, req)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Album patched", "album_id", albumID, "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapAlbum(album))
+}
+
+// DeleteAlbum removes an album from the catalog.
+// @Summary      Delete album
+// @Description  Deletes an album without tracks. An album that still has tracks answers 409 unless detach_tracks=true, which keeps its songs as standalone songs without an album. Needs an access token in the artist's context (any member role) or an admin.
+// @Tags         Albums
+// @Param        id             path   string  true   "Album ID"
+// @Param        detach_tracks  query  bool    false  "Detach the album's songs instead of refusing"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      409  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/albums/{id} [delete]
+func (h *AlbumHandler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	albumID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var detach bool
+	if v := r.URL.Query().Get("detach_tracks"); v != "" {
+		detach, err = strconv.ParseBool(v)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "detach_tracks must be true or false", err)
+			return
+		}
+	}
+
+	if appErr := h.Service.DeleteAlbum(ctx, actor, albumID, detach); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Album deleted", "album_id", albumID, "detach_tracks", detach, "user_id", actor.UserID)
+	w.WriteHeader(http.StatusNoContent)
+}
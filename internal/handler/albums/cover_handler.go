@@ -0,0 +1,71 @@
+package albums
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// CoverResponse points at the uploaded cover's square renditions
+type CoverResponse struct {
+	AlbumID  uuid.UUID `json:"album_id"`
+	CoverUrl string    `json:"cover_url"`
+	// Renditions maps the edge length in pixels to the rendition URL
+	Renditions map[string]string `json:"renditions"`
+}
+
+// UploadCover replaces an album's cover art with an uploaded image.
+// @Summary      Upload album cover
+// @Description  Accepts a JPEG, PNG or GIF in the "file" form field. The image is center-cropped to a square, stripped of metadata and stored as 64, 256 and 640 pixel JPEG renditions; cover_url is set to the 640 pixel one. Needs an access token in the album artist's context (any member role) or an admin.
+// @Tags         Albums
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      string  true  "Album ID"
+// @Param        file  formData  file    true  "Cover image"
+// @Success      200   {object}  CoverResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      413   {object}  utils.ErrorResponse
+// @Failure      415   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/albums/{id}/cover [put]
+func (h *AlbumHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	albumID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	data, err := utils.ReadUpload(w, r, "file", h.App.ImageLimits.MaxBytes)
+	if err != nil {
+		utils.RespondWithUploadError(w, err)
+		return
+	}
+
+	album, appErr := h.Service.UploadCover(ctx, actor, albumID, data)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Album cover uploaded", "album_id", albumID)
+	utils.RespondWithJSON(w, http.StatusOK, CoverResponse{
+		AlbumID:    album.ID,
+		CoverUrl:   album.CoverUrl.String,
+		Renditions: service.RenditionURLs(h.App, album.CoverKey),
+	})
+}
@@ -0,0 +1,81 @@
+package albums
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type AlbumTrackRequest struct {
+	SongID uuid.UUID `json:"song_id"`
+	// DiscNumber defaults to 1
+	DiscNumber  int32 `json:"disc_number"`
+	TrackNumber int32 `json:"track_number"`
+}
+
+type SetTracksRequest struct {
+	Tracks []AlbumTrackRequest `json:"tracks"`
+}
+
+type TrackListResponse struct {
+	Tracks []TrackResponse `json:"tracks"`
+}
+
+// SetTracks replaces an album's tracklist.
+// @Summary      Set album tracks
+// @Description  Replaces the tracklist with the given songs and their disc and track numbers. Songs must be credited to the album's artist; a song moves here from any other album, and songs left out are detached from this one. Positions must be unique per disc. Needs an access token in the artist's context (any member role) or an admin.
+// @Tags         Albums
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string            true  "Album ID"
+// @Param        request  body      SetTracksRequest  true  "Tracklist"
+// @Success      200      {object}  TrackListResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/albums/{id}/tracks [put]
+func (h *AlbumHandler) SetTracks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	albumID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req SetTracksRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	params := service.SetAlbumTracksParams{Tracks: make([]service.AlbumTrackParams, len(req.Tracks))}
+	for i, t := range req.Tracks {
+		params.Tracks[i] = service.AlbumTrackParams{
+			SongID:      t.SongID,
+			DiscNumber:  t.DiscNumber,
+			TrackNumber: t.TrackNumber,
+		}
+	}
+
+	tracks, appErr := h.Service.SetTracks(ctx, actor, albumID, params)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Album tracklist updated", "album_id", albumID, "tracks", len(tracks), "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusOK, TrackListResponse{Tracks: mapTracks(tracks)})
+}
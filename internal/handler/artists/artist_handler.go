@@ -0,0 +1,83 @@
+package artists
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+)
+
+type ArtistHandler struct {
+	App     *app.AppConfig
+	Service *service.ArtistService
+}
+
+func NewArtistHandler(app *app.AppConfig) *ArtistHandler {
+	return &ArtistHandler{App: app}
+}
+
+// artistActor describes the caller for artist permission checks
+func artistActor(ctx context.Context) (service.ArtistActor, error) {
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		return service.ArtistActor{}, err
+	}
+	return service.ArtistActor{
+		UserID:   userID,
+		Admin:    middleware.IsAdmin(ctx),
+		ArtistID: middleware.GetArtistID(ctx),
+	}, nil
+}
+
+// ArtistResponse is an artist's public profile
+type ArtistResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Bio       *string   `json:"bio"`
+	AvatarUrl *string   `json:"avatar_url"`
+	// Renditions maps the edge length in pixels to the URL of an uploaded avatar
+	Renditions    map[string]string `json:"renditions,omitempty"`
+	Verified      bool              `json:"verified"`
+	FollowerCount int64             `json:"follower_count"`
+	PlayCount     int64             `json:"play_count"`
+	// Following is only reported for a single artist
+	Following *bool     `json:"following,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ArtistListResponse struct {
+	Artists    []ArtistResponse `json:"artists"`
+	Limit      int32            `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	HasMore    bool             `json:"has_more"`
+}
+
+func (h *ArtistHandler) mapArtist(a database.Artist) ArtistResponse {
+	resp := ArtistResponse{
+		ID:         a.ID,
+		Name:       a.Name,
+		Renditions: service.RenditionURLs(h.App, a.AvatarKey),
+		Verified:   a.Verified,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+	}
+	if a.Bio.Valid {
+		resp.Bio = &a.Bio.String
+	}
+	if a.AvatarUrl.Valid {
+		resp.AvatarUrl = &a.AvatarUrl.String
+	}
+	return resp
+}
+
+func (h *ArtistHandler) mapArtistWithStats(a service.ArtistWithStats) ArtistResponse {
+	resp := h.mapArtist(a.Artist)
+	resp.FollowerCount = a.FollowerCount
+	resp.PlayCount = a.PlayCount
+	return resp
+}
@@ -0,0 +1,94 @@
+package artists
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// UploadAvatar replaces an artist's avatar with an uploaded image.
+// @Summary      Upload artist avatar
+// @Description  Accepts a JPEG, PNG or GIF in the "file" form field. The image is center-cropped to a square, stripped of metadata and stored as 64, 256 and 640 pixel JPEG renditions; avatar_url is set to the 640 pixel one. Needs an access token in the artist's context with the owner or manager role, or an admin.
+// @Tags         Artists
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path      string  true  "Artist ID"
+// @Param        file  formData  file    true  "Avatar image"
+// @Success      200   {object}  ArtistResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      413   {object}  utils.ErrorResponse
+// @Failure      415   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/avatar [put]
+func (h *ArtistHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	data, err := utils.ReadUpload(w, r, "file", h.App.ImageLimits.MaxBytes)
+	if err != nil {
+		utils.RespondWithUploadError(w, err)
+		return
+	}
+
+	artist, appErr := h.Service.UploadAvatar(ctx, actor, artistID, data)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist avatar uploaded", "artist_id", artistID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapArtist(artist))
+}
+
+// DeleteAvatar removes an artist's avatar.
+// @Summary      Delete artist avatar
+// @Description  Clears avatar_url and deletes any uploaded renditions. Needs an access token in the artist's context with the owner or manager role, or an admin.
+// @Tags         Artists
+// @Param        id   path  string  true  "Artist ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/avatar [delete]
+func (h *ArtistHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if _, appErr := h.Service.DeleteAvatar(ctx, actor, artistID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist avatar deleted", "artist_id", artistID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,310 @@
+package artists
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type CreateArtistRequest struct {
+	Name      string  `json:"name"`
+	Bio       *string `json:"bio"`
+	AvatarUrl *string `json:"avatar_url"`
+}
+
+type SetVerifiedRequest struct {
+	Verified *bool `json:"verified"`
+}
+
+// CreateArtist adds an artist to the catalog.
+// @Summary      Create artist
+// @Description  Creates an unverified artist. Names are unique. Admin only.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateArtistRequest  true  "Artist"
+// @Success      201      {object}  ArtistResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists [post]
+func (h *ArtistHandler) CreateArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateArtistRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	artist, appErr := h.Service.CreateArtist(ctx, service.CreateArtistParams{
+		Name:      req.Name,
+		Bio:       req.Bio,
+		AvatarUrl: req.AvatarUrl,
+	})
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist created", "artist_id", artist.ID, "name", artist.Name)
+	utils.RespondWithJSON(w, http.StatusCreated, h.mapArtist(artist))
+}
+
+// GetArtist returns an artist's profile.
+// @Summary      Get artist
+// @Description  Public. Returns the artist with follower and play counts, and whether the caller follows them when a bearer token is sent.
+// @Tags         Artists
+// @Produce      json
+// @Param        id   path      string  true  "Artist ID"
+// @Success      200  {object}  ArtistResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id} [get]
+func (h *ArtistHandler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Anonymous visitors get the profile without the follow state
+	viewerID, err := middleware.GetUserUUID(ctx)
+	signedIn := err == nil
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	artist, appErr := h.Service.GetArtist(ctx, artistID, viewerID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := h.mapArtistWithStats(artist)
+	if signedIn {
+		resp.Following = &artist.Following
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListArtists returns a page of artists with optional search.
+// @Summary      List artists
+// @Description  Public. Lists artists alphabetically, or by similarity when searching, with keyset pagination. q matches the artist name (substring, ranked by similarity). Follow next_cursor or the Link rel="next" header for the next page; filters and sort must stay the same across pages.
+// @Tags         Artists
+// @Produce      json
+// @Param        limit     query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor    query     string  false  "Cursor from the previous page"
+// @Param        q         query     string  false  "Search the artist name"
+// @Param        verified  query     bool    false  "Filter by verified artists"
+// @Param        sort      query     string  false  "Sort order (default relevance with q, name otherwise)"  Enums(name, relevance)
+// @Success      200       {object}  ArtistListResponse
+// @Header       200       {string}  Link  "RFC 8288 links to the next and first pages"
+// @Failure      400       {object}  utils.ErrorResponse
+// @Failure      500       {object}  utils.ErrorResponse
+// @Router       /api/v1/artists [get]
+func (h *ArtistHandler) ListArtists(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+	params := service.ListArtistsParams{
+		Query: query.Get("q"),
+		Sort:  query.Get("sort"),
+	}
+	if v := query.Get("verified"); v != "" {
+		verified, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "verified must be true or false", err)
+			return
+		}
+		params.Verified = &verified
+	}
+
+	// Cursors are bound to the filters and sort they were issued for
+	scopeQuery := url.Values{}
+	for _, key := range []string{"q", "verified", "sort"} {
+		scopeQuery.Set(key, query.Get(key))
+	}
+	scope := "artists?" + scopeQuery.Encode()
+	var err error
+	params.Page, err = h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	result, appErr := h.Service.ListArtists(ctx, params)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
+	resp := ArtistListResponse{
+		Artists:    make([]ArtistResponse, len(result.Artists)),
+		Limit:      params.Page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
+	}
+	for i, a := range result.Artists {
+		resp.Artists[i] = h.mapArtistWithStats(a)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// PatchArtist applies a JSON merge patch to an artist.
+// @Summary      Patch artist
+// @Description  RFC 7396 merge patch of the artist's name, bio and avatar_url: absent fields are left unchanged and null clears a field (the name can't be cleared). Setting avatar_url by hand removes uploaded avatar renditions. Needs an access token in the artist's context with the owner or manager role, or an admin.
+// @Tags         Artists
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id       path      string               true  "Artist ID"
+// @Param        request  body      CreateArtistRequest  true  "Fields to change; null clears"
+// @Success      200      {object}  ArtistResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      415      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id} [patch]
+func (h *ArtistHandler) PatchArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req service.ArtistPatch
+	if err := patch.Decode(r, &req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid merge patch", err)
+		return
+	}
+
+	artist, appErr := h.Service.PatchArtist(ctx, actor, artistID, req)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist patched", "artist_id", artistID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapArtist(artist))
+}
+
+// SetVerified grants or removes an artist's verified badge.
+// @Summary      Set artist verification
+// @Description  Marks the artist as verified or not. Admin only.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string              true  "Artist ID"
+// @Param        request  body      SetVerifiedRequest  true  "Verification flag"
+// @Success      200      {object}  ArtistResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/verified [put]
+func (h *ArtistHandler) SetVerified(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req SetVerifiedRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.Verified == nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "verified is required")
+		return
+	}
+
+	artist, appErr := h.Service.SetVerified(ctx, artistID, *req.Verified)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	adminID, _ := middleware.GetUserUUID(ctx)
+	logger.Info(ctx, "Artist verification changed", "artist_id", artistID, "verified", artist.Verified, "admin_id", adminID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapArtist(artist))
+}
+
+// DeleteArtist removes an artist from the catalog.
+// @Summary      Delete artist
+// @Description  Deletes an artist that has no albums or media left; otherwise answers 409. Admin only.
+// @Tags         Artists
+// @Param        id   path  string  true  "Artist ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      409  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id} [delete]
+func (h *ArtistHandler) DeleteArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := h.Service.DeleteArtist(ctx, artistID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist deleted", "artist_id", artistID)
+	w.WriteHeader(http.StatusNoContent)
+}
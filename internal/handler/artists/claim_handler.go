@@ -0,0 +1,241 @@
+package artists
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type ClaimArtistRequest struct {
+	// Message tells the reviewers why the artist is the caller's
+	Message *string `json:"message"`
+}
+
+type ReviewClaimRequest struct {
+	Note *string `json:"note"`
+}
+
+type ClaimResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ArtistID   uuid.UUID  `json:"artist_id"`
+	ArtistName string     `json:"artist_name,omitempty"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Username   string     `json:"username,omitempty"`
+	Message    *string    `json:"message"`
+	Status     string     `json:"status"`
+	ReviewNote *string    `json:"review_note,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ClaimListResponse struct {
+	Claims     []ClaimResponse `json:"claims"`
+	Limit      int32           `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+func mapClaim(c database.ArtistClaim) ClaimResponse {
+	resp := ClaimResponse{
+		ID:        c.ID,
+		ArtistID:  c.ArtistID,
+		UserID:    c.UserID,
+		Status:    string(c.Status),
+		CreatedAt: c.CreatedAt,
+	}
+	if c.Message.Valid {
+		resp.Message = &c.Message.String
+	}
+	if c.ReviewNote.Valid {
+		resp.ReviewNote = &c.ReviewNote.String
+	}
+	if c.ReviewedAt.Valid {
+		resp.ReviewedAt = &c.ReviewedAt.Time
+	}
+	return resp
+}
+
+// ClaimArtist asks to become the owner of an artist.
+// @Summary      Claim artist
+// @Description  Submits a claim on an artist that has no owner yet. Admins review claims; an approved claim makes the caller the artist's owner. Artists that already have an owner grant access through invites instead.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string              true   "Artist ID"
+// @Param        request  body      ClaimArtistRequest  false  "Message to the reviewers"
+// @Success      201      {object}  ClaimResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/claims [post]
+func (h *ArtistHandler) ClaimArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req ClaimArtistRequest
+	if r.ContentLength > 0 {
+		if err := utils.ParseJSON(w, r, &req); err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	claim, appErr := h.Service.ClaimArtist(ctx, userID, artistID, req.Message)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist claimed", "artist_id", artistID, "user_id", userID, "claim_id", claim.ID)
+	utils.RespondWithJSON(w, http.StatusCreated, mapClaim(claim))
+}
+
+// ListClaims returns the claims waiting for review.
+// @Summary      List pending artist claims
+// @Description  Review queue of artist claims, oldest first, with keyset pagination. Admin only.
+// @Tags         Artists
+// @Produce      json
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Success      200     {object}  ClaimListResponse
+// @Header       200     {string}  Link  "RFC 8288 links to the next and first pages"
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      403     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/claims [get]
+func (h *ArtistHandler) ListClaims(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	const scope = "artist-claims"
+	page, err := h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	result, appErr := h.Service.ListPendingClaims(ctx, page)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
+	resp := ClaimListResponse{
+		Claims:     make([]ClaimResponse, len(result.Claims)),
+		Limit:      page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
+	}
+	for i, row := range result.Claims {
+		resp.Claims[i] = mapClaim(row.ArtistClaim)
+		resp.Claims[i].ArtistName = row.ArtistName
+		resp.Claims[i].Username = row.Username
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ApproveClaim makes the claimant the artist's owner.
+// @Summary      Approve artist claim
+// @Description  Makes the claimant the artist's owner and rejects the other pending claims on it. Fails with 409 if the artist got an owner in the meantime. Admin only.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        claimID  path      string              true   "Claim ID"
+// @Param        request  body      ReviewClaimRequest  false  "Note to the claimant"
+// @Success      200      {object}  ClaimResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/claims/{claimID}/approve [post]
+func (h *ArtistHandler) ApproveClaim(w http.ResponseWriter, r *http.Request) {
+	h.reviewClaim(w, r, true)
+}
+
+// RejectClaim turns down an artist claim.
+// @Summary      Reject artist claim
+// @Description  Admin only.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        claimID  path      string              true   "Claim ID"
+// @Param        request  body      ReviewClaimRequest  false  "Note to the claimant"
+// @Success      200      {object}  ClaimResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/claims/{claimID}/reject [post]
+func (h *ArtistHandler) RejectClaim(w http.ResponseWriter, r *http.Request) {
+	h.reviewClaim(w, r, false)
+}
+
+func (h *ArtistHandler) reviewClaim(w http.ResponseWriter, r *http.Request, approve bool) {
+	ctx := r.Context()
+
+	adminID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	claimID, errMsg := utils.ReadUUIDParam(r, "claimID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req ReviewClaimRequest
+	if r.ContentLength > 0 {
+		if err := utils.ParseJSON(w, r, &req); err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	claim, appErr := h.Service.ReviewClaim(ctx, adminID, claimID, approve, req.Note)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist claim reviewed", "claim_id", claimID, "artist_id", claim.ArtistID, "status", claim.Status, "admin_id", adminID)
+	utils.RespondWithJSON(w, http.StatusOK, mapClaim(claim))
+}
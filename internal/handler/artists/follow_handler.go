@@ -0,0 +1,80 @@
+package artists
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// FollowArtist makes the caller follow an artist.
+// @Summary      Follow artist
+// @Description  Following an artist twice is a no-op.
+// @Tags         Artists
+// @Param        id   path  string  true  "Artist ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/follow [post]
+func (h *ArtistHandler) FollowArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := h.Service.Follow(ctx, userID, artistID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist followed", "user_id", userID, "artist_id", artistID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnfollowArtist stops the caller following an artist.
+// @Summary      Unfollow artist
+// @Tags         Artists
+// @Param        id   path  string  true  "Artist ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/follow [delete]
+func (h *ArtistHandler) UnfollowArtist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := h.Service.Unfollow(ctx, userID, artistID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist unfollowed", "user_id", userID, "artist_id", artistID)
+	w.WriteHeader(http.StatusNoContent)
+}
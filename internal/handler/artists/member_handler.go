@@ -0,0 +1,564 @@
+package artists
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type MemberResponse struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	// Role is owner, manager or editor
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type MembershipResponse struct {
+	Artist   ArtistResponse `json:"artist"`
+	Role     string         `json:"role"`
+	JoinedAt time.Time      `json:"joined_at"`
+}
+
+type InviteResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ArtistID  uuid.UUID `json:"artist_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Artist is set on the invites addressed to the caller
+	Artist *ArtistResponse `json:"artist,omitempty"`
+}
+
+type InviteMemberRequest struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type ArtistContextResponse struct {
+	AccessToken string `json:"access_token"`
+	// ArtistID and Role are empty after leaving the artist context
+	ArtistID *uuid.UUID `json:"artist_id,omitempty"`
+	Role     string     `json:"role,omitempty"`
+}
+
+func mapMember(m database.ArtistMember, username string) MemberResponse {
+	return MemberResponse{UserID: m.UserID, Username: username, Role: string(m.Role), JoinedAt: m.CreatedAt}
+}
+
+func mapInvite(i database.ArtistMemberInvite) InviteResponse {
+	return InviteResponse{
+		ID:        i.ID,
+		ArtistID:  i.ArtistID,
+		UserID:    i.UserID,
+		Role:      string(i.Role),
+		CreatedAt: i.CreatedAt,
+		ExpiresAt: i.ExpiresAt,
+	}
+}
+
+// ListMyArtists returns the artists the caller is a member of.
+// @Summary      List my artists
+// @Description  Artists the caller belongs to, with their role in each.
+// @Tags         Artists
+// @Produce      json
+// @Success      200  {array}   MembershipResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/mine [get]
+func (h *ArtistHandler) ListMyArtists(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	rows, appErr := h.Service.ListUserArtists(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := make([]MembershipResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = MembershipResponse{
+			Artist:   h.mapArtist(row.Artist),
+			Role:     string(row.ArtistMember.Role),
+			JoinedAt: row.ArtistMember.CreatedAt,
+		}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// SelectArtistContext lets the session act for an artist.
+// @Summary      Select artist context
+// @Description  Switches the current session to act for an artist the caller is a member of and returns an access token carrying the artist claim. Members need this context to edit the artist, its catalog and its members. Refreshed tokens keep the artist until another one is selected or the membership ends.
+// @Tags         Artists
+// @Produce      json
+// @Param        id   path      string  true  "Artist ID"
+// @Success      200  {object}  ArtistContextResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/context [post]
+func (h *ArtistHandler) SelectArtistContext(w http.ResponseWriter, r *http.Request) {
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	h.selectArtistContext(w, r, artistID)
+}
+
+// ClearArtistContext stops the session acting for an artist.
+// @Summary      Leave artist context
+// @Description  Returns an access token without the artist claim; refreshed tokens won't carry it either.
+// @Tags         Artists
+// @Produce      json
+// @Success      200  {object}  ArtistContextResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/context [delete]
+func (h *ArtistHandler) ClearArtistContext(w http.ResponseWriter, r *http.Request) {
+	h.selectArtistContext(w, r, uuid.Nil)
+}
+
+func (h *ArtistHandler) selectArtistContext(w http.ResponseWriter, r *http.Request, artistID uuid.UUID) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	sessionID, err := uuid.Parse(middleware.GetSessionID(ctx))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "A session is required to select an artist", nil)
+		return
+	}
+
+	result, appErr := h.Service.SelectArtistContext(ctx, service.SelectArtistContextParams{
+		UserID:    userID,
+		SessionID: sessionID,
+		ArtistID:  artistID,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := ArtistContextResponse{AccessToken: result.AccessToken}
+	if result.Member != nil {
+		resp.ArtistID = &result.Member.ArtistID
+		resp.Role = string(result.Member.Role)
+	}
+	logger.Info(ctx, "Artist context selected", "user_id", userID, "artist_id", artistID)
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListMembers returns an artist's members.
+// @Summary      List artist members
+// @Description  Visible to the artist's members and admins.
+// @Tags         Artists
+// @Produce      json
+// @Param        id   path      string  true  "Artist ID"
+// @Success      200  {array}   MemberResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/members [get]
+func (h *ArtistHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	rows, appErr := h.Service.ListMembers(ctx, actor, artistID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := make([]MemberResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = mapMember(row.ArtistMember, row.Username)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// UpdateMemberRole changes a member's role.
+// @Summary      Change member role
+// @Description  Owners only, in the artist's context. The last owner can't be demoted.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true  "Artist ID"
+// @Param        userID   path      string                   true  "Member user ID"
+// @Param        request  body      UpdateMemberRoleRequest  true  "New role"
+// @Success      200      {object}  MemberResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/members/{userID} [patch]
+func (h *ArtistHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	userID, errMsg := utils.ReadUUIDParam(r, "userID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req UpdateMemberRoleRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	member, appErr := h.Service.UpdateMemberRole(ctx, actor, artistID, userID, req.Role)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist member role changed", "artist_id", artistID, "user_id", userID, "role", member.Role, "by", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusOK, mapMember(member, ""))
+}
+
+// RemoveMember removes a member from an artist.
+// @Summary      Remove artist member
+// @Description  Owners remove members in the artist's context; any member may remove themselves to leave. The last owner can't leave.
+// @Tags         Artists
+// @Param        id      path  string  true  "Artist ID"
+// @Param        userID  path  string  true  "Member user ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      409  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/members/{userID} [delete]
+func (h *ArtistHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	userID, errMsg := utils.ReadUUIDParam(r, "userID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := h.Service.RemoveMember(ctx, actor, artistID, userID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist member removed", "artist_id", artistID, "user_id", userID, "by", actor.UserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// InviteMember invites a user into an artist.
+// @Summary      Invite artist member
+// @Description  Owners only, in the artist's context. The invitee sees the invite under GET /artists/invites and has 14 days to accept; inviting them again replaces the invite.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string               true  "Artist ID"
+// @Param        request  body      InviteMemberRequest  true  "Invitee and role"
+// @Success      201      {object}  InviteResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/invites [post]
+func (h *ArtistHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	invite, appErr := h.Service.InviteMember(ctx, actor, artistID, service.InviteArtistMemberParams{
+		Username: req.Username,
+		Role:     req.Role,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist member invited", "artist_id", artistID, "user_id", invite.UserID, "role", invite.Role, "by", actor.UserID)
+	resp := mapInvite(invite)
+	resp.Username = req.Username
+	utils.RespondWithJSON(w, http.StatusCreated, resp)
+}
+
+// ListMemberInvites returns an artist's open invites.
+// @Summary      List artist invites
+// @Description  Owners only, in the artist's context.
+// @Tags         Artists
+// @Produce      json
+// @Param        id   path      string  true  "Artist ID"
+// @Success      200  {array}   InviteResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/invites [get]
+func (h *ArtistHandler) ListMemberInvites(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	rows, appErr := h.Service.ListMemberInvites(ctx, actor, artistID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := make([]InviteResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = mapInvite(row.ArtistMemberInvite)
+		resp[i].Username = row.Username
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// RevokeInvite withdraws an open invite.
+// @Summary      Revoke artist invite
+// @Description  Owners only, in the artist's context.
+// @Tags         Artists
+// @Param        id        path  string  true  "Artist ID"
+// @Param        inviteID  path  string  true  "Invite ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/invites/{inviteID} [delete]
+func (h *ArtistHandler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	inviteID, errMsg := utils.ReadUUIDParam(r, "inviteID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := h.Service.RevokeInvite(ctx, actor, artistID, inviteID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist invite revoked", "artist_id", artistID, "invite_id", inviteID, "by", actor.UserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMyInvites returns the open artist invites addressed to the caller.
+// @Summary      List my artist invites
+// @Tags         Artists
+// @Produce      json
+// @Success      200  {array}   InviteResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/invites [get]
+func (h *ArtistHandler) ListMyInvites(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	rows, appErr := h.Service.ListUserInvites(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := make([]InviteResponse, len(rows))
+	for i, row := range rows {
+		artist := h.mapArtist(row.Artist)
+		resp[i] = mapInvite(row.ArtistMemberInvite)
+		resp[i].Artist = &artist
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// AcceptInvite joins the inviting artist.
+// @Summary      Accept artist invite
+// @Description  Makes the caller a member with the invited role. Select the artist context afterwards to act for it.
+// @Tags         Artists
+// @Produce      json
+// @Param        inviteID  path      string  true  "Invite ID"
+// @Success      200       {object}  MemberResponse
+// @Failure      400       {object}  utils.ErrorResponse
+// @Failure      401       {object}  utils.ErrorResponse
+// @Failure      404       {object}  utils.ErrorResponse
+// @Failure      500       {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/invites/{inviteID}/accept [post]
+func (h *ArtistHandler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	inviteID, errMsg := utils.ReadUUIDParam(r, "inviteID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	member, appErr := h.Service.AcceptInvite(ctx, userID, inviteID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist invite accepted", "artist_id", member.ArtistID, "user_id", userID, "role", member.Role)
+	utils.RespondWithJSON(w, http.StatusOK, mapMember(member, ""))
+}
+
+// DeclineInvite turns down an artist invite.
+// @Summary      Decline artist invite
+// @Tags         Artists
+// @Param        inviteID  path  string  true  "Invite ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/invites/{inviteID} [delete]
+func (h *ArtistHandler) DeclineInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	inviteID, errMsg := utils.ReadUUIDParam(r, "inviteID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := h.Service.DeclineInvite(ctx, userID, inviteID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist invite declined", "invite_id", inviteID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
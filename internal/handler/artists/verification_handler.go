@@ -0,0 +1,402 @@
+package artists
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type SubmitVerificationRequest struct {
+	// EvidenceLinks point to official sites, social profiles or label pages
+	EvidenceLinks []string `json:"evidence_links"`
+	Note          *string  `json:"note"`
+}
+
+type VerificationTransitionRequest struct {
+	// Status is pending, needs_info, approved or rejected
+	Status  string  `json:"status"`
+	Comment *string `json:"comment"`
+	// EvidenceLinks replaces the links when the artist answers a needs_info request
+	EvidenceLinks []string `json:"evidence_links"`
+}
+
+type VerificationCommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+type VerificationRequestResponse struct {
+	ID            uuid.UUID                   `json:"id"`
+	ArtistID      uuid.UUID                   `json:"artist_id"`
+	ArtistName    string                      `json:"artist_name,omitempty"`
+	SubmittedBy   *uuid.UUID                  `json:"submitted_by"`
+	EvidenceLinks []string                    `json:"evidence_links"`
+	Note          *string                     `json:"note"`
+	Status        string                      `json:"status"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	UpdatedAt     time.Time                   `json:"updated_at"`
+	Events        []VerificationEventResponse `json:"events,omitempty"`
+}
+
+// VerificationEventResponse is a status change or comment. Statuses are
+// empty for plain comments and from_status is empty for the submission.
+type VerificationEventResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	ActorID       *uuid.UUID `json:"actor_id"`
+	ActorUsername *string    `json:"actor_username"`
+	FromStatus    string     `json:"from_status,omitempty"`
+	ToStatus      string     `json:"to_status,omitempty"`
+	Comment       *string    `json:"comment"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type VerificationRequestListResponse struct {
+	Requests   []VerificationRequestResponse `json:"requests"`
+	Limit      int32                         `json:"limit,omitempty"`
+	NextCursor string                        `json:"next_cursor,omitempty"`
+	HasMore    bool                          `json:"has_more"`
+}
+
+func mapVerificationRequest(v database.ArtistVerificationRequest) VerificationRequestResponse {
+	resp := VerificationRequestResponse{
+		ID:            v.ID,
+		ArtistID:      v.ArtistID,
+		EvidenceLinks: v.EvidenceLinks,
+		Status:        string(v.Status),
+		CreatedAt:     v.CreatedAt,
+		UpdatedAt:     v.UpdatedAt,
+	}
+	if resp.EvidenceLinks == nil {
+		resp.EvidenceLinks = []string{}
+	}
+	if v.SubmittedBy.Valid {
+		resp.SubmittedBy = &v.SubmittedBy.UUID
+	}
+	if v.Note.Valid {
+		resp.Note = &v.Note.String
+	}
+	return resp
+}
+
+func mapVerificationEvent(e database.ArtistVerificationEvent) VerificationEventResponse {
+	resp := VerificationEventResponse{
+		ID:        e.ID,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.ActorID.Valid {
+		resp.ActorID = &e.ActorID.UUID
+	}
+	if e.FromStatus.Valid {
+		resp.FromStatus = string(e.FromStatus.VerificationStatus)
+	}
+	if e.ToStatus.Valid {
+		resp.ToStatus = string(e.ToStatus.VerificationStatus)
+	}
+	if e.Comment.Valid {
+		resp.Comment = &e.Comment.String
+	}
+	return resp
+}
+
+// SubmitVerification asks the reviewers to verify an artist.
+// @Summary      Submit verification request
+// @Description  Opens a verification request with evidence links. Requires the manager role in the artist's context. An artist can have one open request at a time.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "Artist ID"
+// @Param        request  body      SubmitVerificationRequest  true  "Evidence"
+// @Success      201      {object}  VerificationRequestResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/verification-requests [post]
+func (h *ArtistHandler) SubmitVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req SubmitVerificationRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	created, appErr := h.Service.SubmitVerification(ctx, actor, artistID, service.SubmitVerificationParams{
+		EvidenceLinks: req.EvidenceLinks,
+		Note:          req.Note,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist verification requested", "artist_id", artistID, "request_id", created.ID, "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusCreated, mapVerificationRequest(created))
+}
+
+// ListArtistVerificationRequests returns an artist's verification requests.
+// @Summary      List artist verification requests
+// @Description  The artist's verification requests, newest first. Visible to the artist's members and admins.
+// @Tags         Artists
+// @Produce      json
+// @Param        id   path      string  true  "Artist ID"
+// @Success      200  {object}  VerificationRequestListResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/{id}/verification-requests [get]
+func (h *ArtistHandler) ListArtistVerificationRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	artistID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	reqs, appErr := h.Service.ListArtistVerificationRequests(ctx, actor, artistID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := VerificationRequestListResponse{Requests: make([]VerificationRequestResponse, len(reqs))}
+	for i, v := range reqs {
+		resp.Requests[i] = mapVerificationRequest(v)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListVerificationQueue returns the verification requests to review.
+// @Summary      List verification queue
+// @Description  Verification requests in a status, least recently updated first, with keyset pagination. Admin only.
+// @Tags         Artists
+// @Produce      json
+// @Param        status  query     string  false  "pending (default), needs_info, approved or rejected"
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Success      200     {object}  VerificationRequestListResponse
+// @Header       200     {string}  Link  "RFC 8288 links to the next and first pages"
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      403     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/verification-requests [get]
+func (h *ArtistHandler) ListVerificationQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status := r.URL.Query().Get("status")
+	// Cursors carry updated_at, which only orders requests within one status
+	scope := "artist-verification:" + status
+	page, err := h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	result, appErr := h.Service.ListVerificationQueue(ctx, status, page)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
+	resp := VerificationRequestListResponse{
+		Requests:   make([]VerificationRequestResponse, len(result.Requests)),
+		Limit:      page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
+	}
+	for i, row := range result.Requests {
+		resp.Requests[i] = mapVerificationRequest(row.ArtistVerificationRequest)
+		resp.Requests[i].ArtistName = row.ArtistName
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// GetVerificationRequest returns a verification request with its history.
+// @Summary      Get verification request
+// @Description  The request with its status changes and comments, oldest first. Visible to the artist's members and admins.
+// @Tags         Artists
+// @Produce      json
+// @Param        requestID  path      string  true  "Verification request ID"
+// @Success      200        {object}  VerificationRequestResponse
+// @Failure      400        {object}  utils.ErrorResponse
+// @Failure      401        {object}  utils.ErrorResponse
+// @Failure      403        {object}  utils.ErrorResponse
+// @Failure      404        {object}  utils.ErrorResponse
+// @Failure      500        {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/verification-requests/{requestID} [get]
+func (h *ArtistHandler) GetVerificationRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	requestID, errMsg := utils.ReadUUIDParam(r, "requestID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	result, appErr := h.Service.GetVerificationRequest(ctx, actor, requestID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := mapVerificationRequest(result.Request)
+	resp.Events = make([]VerificationEventResponse, len(result.Events))
+	for i, row := range result.Events {
+		resp.Events[i] = mapVerificationEvent(row.ArtistVerificationEvent)
+		if row.ActorUsername.Valid {
+			resp.Events[i].ActorUsername = &row.ActorUsername.String
+		}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// TransitionVerification changes a verification request's status.
+// @Summary      Change verification status
+// @Description  Admins move pending requests to needs_info, approved or rejected, and needs_info requests to approved or rejected; needs_info and rejected require a comment. Managers in the artist's context move a needs_info request back to pending, optionally replacing the evidence links. Approving marks the artist verified. The submitter is emailed about admin decisions.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        requestID  path      string                         true  "Verification request ID"
+// @Param        request    body      VerificationTransitionRequest  true  "New status"
+// @Success      200        {object}  VerificationRequestResponse
+// @Failure      400        {object}  utils.ErrorResponse
+// @Failure      401        {object}  utils.ErrorResponse
+// @Failure      403        {object}  utils.ErrorResponse
+// @Failure      404        {object}  utils.ErrorResponse
+// @Failure      409        {object}  utils.ErrorResponse
+// @Failure      500        {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/verification-requests/{requestID}/status [post]
+func (h *ArtistHandler) TransitionVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	requestID, errMsg := utils.ReadUUIDParam(r, "requestID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req VerificationTransitionRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	updated, appErr := h.Service.TransitionVerification(ctx, actor, requestID, service.VerificationTransitionParams{
+		Status:        req.Status,
+		Comment:       req.Comment,
+		EvidenceLinks: req.EvidenceLinks,
+	})
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Artist verification status changed", "request_id", requestID, "artist_id", updated.ArtistID, "status", updated.Status, "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusOK, mapVerificationRequest(updated))
+}
+
+// CommentOnVerification adds a comment to an open verification request.
+// @Summary      Comment on verification request
+// @Description  Adds a comment without changing the status. Open to the artist's members and admins while the request is pending or needs_info. The submitter is emailed about admin comments.
+// @Tags         Artists
+// @Accept       json
+// @Produce      json
+// @Param        requestID  path      string                      true  "Verification request ID"
+// @Param        request    body      VerificationCommentRequest  true  "Comment"
+// @Success      201        {object}  VerificationEventResponse
+// @Failure      400        {object}  utils.ErrorResponse
+// @Failure      401        {object}  utils.ErrorResponse
+// @Failure      403        {object}  utils.ErrorResponse
+// @Failure      404        {object}  utils.ErrorResponse
+// @Failure      409        {object}  utils.ErrorResponse
+// @Failure      500        {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/artists/verification-requests/{requestID}/comments [post]
+func (h *ArtistHandler) CommentOnVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	requestID, errMsg := utils.ReadUUIDParam(r, "requestID")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req VerificationCommentRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	event, appErr := h.Service.CommentOnVerification(ctx, actor, requestID, req.Comment)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, mapVerificationEvent(event))
+}
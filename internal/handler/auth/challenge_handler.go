@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/challenge"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ChallengeResponse carries a proof-of-work challenge and the CAPTCHA site key, if any
+type ChallengeResponse struct {
+	challenge.Challenge
+	CaptchaSiteKey string `json:"captcha_site_key,omitempty"`
+}
+
+// @Summary      Issue bot challenge
+// @Description  Returns a signed proof-of-work challenge. Solve it by finding a nonce so that sha256("challenge:nonce") starts with `difficulty` zero bits, then send "challenge:nonce" in the X-PoW-Solution header. Clients showing a CAPTCHA widget send its token in X-Captcha-Token instead.
+// @Tags         Authentication
+// @Produce      json
+// @Success      200  {object}  ChallengeResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Router       /api/v1/auth/challenge [get]
+func (h *Handler) IssueChallenge(w http.ResponseWriter, r *http.Request) {
+	c, err := h.App.Challenge.ProofOfWork.Issue()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to issue challenge", err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, ChallengeResponse{
+		Challenge:      c,
+		CaptchaSiteKey: h.App.Challenge.SiteKey,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/techies/streamify/internal/challenge"
 	"github.com/techies/streamify/internal/handler/token"
 	"github.com/techies/streamify/internal/logger"
 	"github.com/techies/streamify/internal/models"
@@ -36,6 +37,8 @@ type LoginResponse struct {
 // @Success      200          {object}  LoginResponse
 // @Failure      400          {object}  utils.ErrorResponse
 // @Failure      401          {object}  utils.ErrorResponse
+// @Failure      403          {object}  utils.ErrorResponse
+// @Failure      428          {object}  utils.ErrorResponse
 // @Failure      500          {object}  utils.ErrorResponse
 // @Router       /api/v1/auth/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
@@ -47,6 +50,11 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Accounts under repeated failed logins require a challenge from any IP
+	if !h.App.Challenge.RequireForAccount(w, r, challenge.RouteLogin, req.Email) {
+		return
+	}
+
 	result, appErr := h.Service.Login(ctx, service.LoginParams{
 		Email:     req.Email,
 		Password:  req.Password,
@@ -55,9 +63,14 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if appErr != nil {
+		// Repeated failures from the same IP or against the same account trigger a bot challenge
+		if appErr.Code == http.StatusUnauthorized {
+			h.App.Challenge.RecordFailure(r, req.Email)
+		}
 		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
 		return
 	}
+	h.App.Challenge.ResetFailures(r, req.Email)
 
 	http.SetCookie(w, &http.Cookie{
 		Name:     "refresh_token",
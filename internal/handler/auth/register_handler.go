@@ -3,6 +3,7 @@ package auth
 import (
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/techies/streamify/internal/handler/users"
 	"github.com/techies/streamify/internal/models"
 	"github.com/techies/streamify/internal/service"
@@ -11,9 +12,15 @@ import (
 
 // RegisterRequest represents registration payload
 type RegisterRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
-	Username string `json:"username" validate:"required,min=3"`
+	Email          string `json:"email" validate:"required,email"`
+	Password       string `json:"password" validate:"required,min=8"`
+	Username       string `json:"username" validate:"required,min=3"`
+	InvitationCode string `json:"invitation_code,omitempty"`
+	// AcceptedDocuments lists the IDs of the current legal documents the user agreed to, see GET /api/v1/legal/documents
+	AcceptedDocuments []uuid.UUID `json:"accepted_documents"`
+	MarketingConsent  bool        `json:"marketing_consent"`
+	BirthDate         string      `json:"birth_date,omitempty" example:"2000-01-31"`
+	Country           string      `json:"country,omitempty" example:"DE"`
 }
 
 // ========================
@@ -28,7 +35,9 @@ type RegisterRequest struct {
 // @Param        user  body      RegisterRequest  true  "User registration details"
 // @Success      201   {object}  models.UserResponse
 // @Failure      400   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
 // @Failure      409   {object}  utils.ErrorResponse
+// @Failure      428   {object}  utils.ErrorResponse
 // @Failure      500   {object}  utils.ErrorResponse
 // @Router       /api/v1/auth/register [post]
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
@@ -40,9 +49,16 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, appErr := h.Service.Register(ctx, service.RegisterParams{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: req.Password,
+		Username:          req.Username,
+		Email:             req.Email,
+		Password:          req.Password,
+		InvitationCode:    req.InvitationCode,
+		AcceptedDocuments: req.AcceptedDocuments,
+		MarketingConsent:  req.MarketingConsent,
+		BirthDate:         req.BirthDate,
+		Country:           req.Country,
+		IP:                utils.GetClientIP(r),
+		UserAgent:         r.UserAgent(),
 	})
 
 	if appErr != nil {
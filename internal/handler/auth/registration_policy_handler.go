@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/utils"
+)
+
+// RegistrationPolicyResponse tells clients how sign-up currently works
+type RegistrationPolicyResponse struct {
+	Mode               string   `json:"mode"`
+	InvitationRequired bool     `json:"invitation_required"`
+	AllowedDomains     []string `json:"allowed_domains,omitempty"`
+}
+
+// @Summary      Registration policy
+// @Description  Returns the current registration mode (open, invite-only, closed) and allowed email domains so clients can adapt the sign-up form
+// @Tags         Authentication
+// @Produce      json
+// @Success      200  {object}  RegistrationPolicyResponse
+// @Router       /api/v1/auth/registration-policy [get]
+func (h *Handler) RegistrationPolicy(w http.ResponseWriter, r *http.Request) {
+	policy := h.App.Registration
+
+	utils.RespondWithJSON(w, http.StatusOK, RegistrationPolicyResponse{
+		Mode:               string(policy.Mode),
+		InvitationRequired: policy.InvitationRequired(),
+		AllowedDomains:     policy.AllowedDomains,
+	})
+}
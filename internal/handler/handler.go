@@ -2,39 +2,75 @@ package handler
 
 import (
 	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/handler/albums"
+	"github.com/techies/streamify/internal/handler/artists"
 	"github.com/techies/streamify/internal/handler/auth"
+	"github.com/techies/streamify/internal/handler/invitations"
+	"github.com/techies/streamify/internal/handler/legal"
+	"github.com/techies/streamify/internal/handler/media"
 	"github.com/techies/streamify/internal/handler/token"
 	"github.com/techies/streamify/internal/handler/users"
 	"github.com/techies/streamify/internal/service"
 )
 
 type Handler struct {
-	App     *app.AppConfig
-	Token   *token.TokenHandler
-	Auth    *auth.Handler
-	User    *users.UserHandler
-	Service struct {
-		Auth *service.AuthService
-		User *service.UserService
+	App        *app.AppConfig
+	Token      *token.TokenHandler
+	Auth       *auth.Handler
+	User       *users.UserHandler
+	Invitation *invitations.InvitationHandler
+	Legal      *legal.LegalHandler
+	Album      *albums.AlbumHandler
+	Artist     *artists.ArtistHandler
+	Media      *media.MediaHandler
+	Service    struct {
+		Auth       *service.AuthService
+		User       *service.UserService
+		Invitation *service.InvitationService
+		Consent    *service.ConsentService
+		Album      *service.AlbumService
+		Artist     *service.ArtistService
+		Media      *service.MediaService
 	}
 }
 
 func NewHandler(appConfig *app.AppConfig) *Handler {
 	authService := service.NewAuthService(appConfig.DB, appConfig)
 	userService := service.NewUserService(appConfig.DB, appConfig)
+	invitationService := service.NewInvitationService(appConfig.DB, appConfig)
+	consentService := service.NewConsentService(appConfig.DB, appConfig)
+	albumService := service.NewAlbumService(appConfig.DB, appConfig)
+	artistService := service.NewArtistService(appConfig.DB, appConfig)
+	mediaService := service.NewMediaService(appConfig.DB, appConfig)
 
 	h := &Handler{
-		App:   appConfig,
-		Token: token.NewTokenHandler(appConfig),
-		Auth:  auth.NewAuthHandler(appConfig),
-		User:  users.NewUserHandler(appConfig),
+		App:        appConfig,
+		Token:      token.NewTokenHandler(appConfig),
+		Auth:       auth.NewAuthHandler(appConfig),
+		User:       users.NewUserHandler(appConfig),
+		Invitation: invitations.NewInvitationHandler(appConfig),
+		Legal:      legal.NewLegalHandler(appConfig),
+		Album:      albums.NewAlbumHandler(appConfig),
+		Artist:     artists.NewArtistHandler(appConfig),
+		Media:      media.NewMediaHandler(appConfig),
 	}
 	h.Service.Auth = authService
 	h.Service.User = userService
+	h.Service.Invitation = invitationService
+	h.Service.Consent = consentService
+	h.Service.Album = albumService
+	h.Service.Artist = artistService
+	h.Service.Media = mediaService
 
 	// Pass services to handlers if needed or keep them accessible via h.Service
 	h.Auth.Service = authService
 	h.User.Service = userService
+	h.Invitation.Service = invitationService
+	h.Legal.Service = consentService
+	h.Album.Service = albumService
+	h.Artist.Service = artistService
+	h.Media.Service = mediaService
+	h.Media.Users = userService
 
 	return h
 }
@@ -0,0 +1,180 @@
+package invitations
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// CreateInvitationRequest represents an invitation creation payload
+type CreateInvitationRequest struct {
+	Email     string     `json:"email,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	MaxUses   int32      `json:"max_uses,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateInvitation generates a new invitation code.
+// @Summary      Create invitation
+// @Description  Generates an invitation code with optional usage limit, expiry and email restriction. Admin only.
+// @Tags         Invitations
+// @Accept       json
+// @Produce      json
+// @Param        body  body      CreateInvitationRequest  true  "Invitation options"
+// @Success      201   {object}  InvitationResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/invitations [post]
+func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	adminID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req CreateInvitationRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	invitation, appErr := h.Service.CreateInvitation(ctx, service.CreateInvitationParams{
+		CreatedBy: adminID,
+		Email:     req.Email,
+		Note:      req.Note,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Invitation created", "invitation_id", invitation.ID, "created_by", adminID)
+	utils.RespondWithJSON(w, http.StatusCreated, MapInvitationToResponse(invitation))
+}
+
+// ListInvitations returns invitation codes, newest first.
+// @Summary      List invitations
+// @Description  Returns a paginated list of invitation codes. Admin only.
+// @Tags         Invitations
+// @Produce      json
+// @Param        limit   query     int  false  "Max results per page (default 20, max 100)"
+// @Param        offset  query     int  false  "Offset for pagination (default 0)"
+// @Success      200     {array}   InvitationResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      403     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/invitations [get]
+func (h *InvitationHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := parseInt(r.URL.Query().Get("limit"), 20)
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseInt(r.URL.Query().Get("offset"), 0)
+
+	invitations, appErr := h.Service.ListInvitations(ctx, int32(limit), int32(offset))
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	response := make([]InvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		response[i] = MapInvitationToResponse(inv)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// RevokeInvitation stops an invitation code from being redeemed.
+// @Summary      Revoke invitation
+// @Description  Revokes an invitation code. Accounts already created with it are not affected. Admin only.
+// @Tags         Invitations
+// @Produce      json
+// @Param        id   path      string  true  "Invitation ID (UUID)"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/invitations/{id} [delete]
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if appErr := h.Service.RevokeInvitation(ctx, id); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Invitation revoked", "invitation_id", id)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Invitation revoked"})
+}
+
+// ListRedemptions returns the accounts created with an invitation.
+// @Summary      List invitation redemptions
+// @Description  Returns the users who signed up with an invitation code, for referral attribution. Admin only.
+// @Tags         Invitations
+// @Produce      json
+// @Param        id   path      string  true  "Invitation ID (UUID)"
+// @Success      200  {array}   RedemptionResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/invitations/{id}/redemptions [get]
+func (h *InvitationHandler) ListRedemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	redemptions, appErr := h.Service.ListRedemptions(ctx, id)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	response := make([]RedemptionResponse, len(redemptions))
+	for i, rd := range redemptions {
+		response[i] = RedemptionResponse{
+			UserID:     rd.UserID,
+			Username:   rd.Username,
+			Email:      rd.Email,
+			RedeemedAt: rd.RedeemedAt,
+		}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+func parseInt(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	res, err := strconv.Atoi(value)
+	if err != nil || res < 0 {
+		return fallback
+	}
+	return res
+}
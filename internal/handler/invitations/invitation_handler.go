@@ -0,0 +1,64 @@
+package invitations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/service"
+)
+
+type InvitationHandler struct {
+	App     *app.AppConfig
+	Service *service.InvitationService
+}
+
+func NewInvitationHandler(app *app.AppConfig) *InvitationHandler {
+	return &InvitationHandler{App: app}
+}
+
+func MapInvitationToResponse(inv database.Invitation) InvitationResponse {
+	resp := InvitationResponse{
+		ID:        inv.ID,
+		Code:      inv.Code,
+		Email:     inv.Email.String,
+		Note:      inv.Note.String,
+		Role:      string(inv.Role.UserRole),
+		MaxUses:   inv.MaxUses,
+		UseCount:  inv.UseCount,
+		Revoked:   inv.RevokedAt.Valid,
+		CreatedAt: inv.CreatedAt,
+	}
+	if inv.CreatedBy.Valid {
+		resp.CreatedBy = &inv.CreatedBy.UUID
+	}
+	if inv.ExpiresAt.Valid {
+		resp.ExpiresAt = &inv.ExpiresAt.Time
+	}
+	return resp
+}
+
+// InvitationResponse describes an invitation code
+type InvitationResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Code  string    `json:"code"`
+	Email string    `json:"email,omitempty"`
+	Note  string    `json:"note,omitempty"`
+	// Role is granted on registration; set for accounts pre-provisioned by CSV import
+	Role      string     `json:"role,omitempty"`
+	MaxUses   int32      `json:"max_uses"`
+	UseCount  int32      `json:"use_count"`
+	Revoked   bool       `json:"revoked"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RedemptionResponse describes a user who signed up with an invitation
+type RedemptionResponse struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
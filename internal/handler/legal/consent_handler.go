@@ -0,0 +1,134 @@
+package legal
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// GiveConsentRequest accepts a legal document, or opts in to marketing with kind "marketing"
+type GiveConsentRequest struct {
+	DocumentID uuid.UUID `json:"document_id,omitempty"`
+	Kind       string    `json:"kind,omitempty" example:"marketing"`
+}
+
+// ListConsents returns the authenticated user's consents.
+// @Summary      List my consents
+// @Description  Returns the consents given by the current user, including withdrawn ones, and the mandatory documents still to accept.
+// @Tags         Legal
+// @Produce      json
+// @Success      200  {object}  ConsentsResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/consents [get]
+func (h *LegalHandler) ListConsents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	consents, pending, appErr := h.Service.ListConsents(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	response := ConsentsResponse{
+		Consents: make([]ConsentResponse, len(consents)),
+		Pending:  mapLegalDocuments(pending),
+	}
+	for i, c := range consents {
+		response.Consents[i] = mapConsentRowToResponse(c)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GiveConsent records the authenticated user's consent.
+// @Summary      Give consent
+// @Description  Accepts a legal document by ID, or opts in to marketing with kind "marketing". The time and IP address are recorded.
+// @Tags         Legal
+// @Accept       json
+// @Produce      json
+// @Param        body  body      GiveConsentRequest  true  "Document or consent kind"
+// @Success      201   {object}  ConsentResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/consents [post]
+func (h *LegalHandler) GiveConsent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req GiveConsentRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	consent, appErr := h.Service.GiveConsent(ctx, service.GiveConsentParams{
+		UserID:     userID,
+		DocumentID: req.DocumentID,
+		Kind:       req.Kind,
+		IP:         utils.GetClientIP(r),
+		UserAgent:  r.UserAgent(),
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Consent given", "user_id", userID, "kind", consent.Kind)
+	utils.RespondWithJSON(w, http.StatusCreated, mapConsentToResponse(consent))
+}
+
+// WithdrawConsent withdraws one of the authenticated user's consents.
+// @Summary      Withdraw consent
+// @Description  Withdraws a consent. Withdrawing a mandatory document blocks the API until it is accepted again.
+// @Tags         Legal
+// @Produce      json
+// @Param        id   path      string  true  "Consent ID (UUID)"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/consents/{id} [delete]
+func (h *LegalHandler) WithdrawConsent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	consentID, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if appErr := h.Service.WithdrawConsent(ctx, userID, consentID, utils.GetClientIP(r)); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Consent withdrawn", "user_id", userID, "consent_id", consentID)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Consent withdrawn"})
+}
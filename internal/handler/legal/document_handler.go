@@ -0,0 +1,79 @@
+package legal
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// CreateLegalDocumentRequest represents a new legal document version
+type CreateLegalDocumentRequest struct {
+	Kind        string     `json:"kind" example:"terms"`
+	Version     string     `json:"version" example:"2026-01"`
+	Title       string     `json:"title" example:"Terms of Service"`
+	URL         string     `json:"url" example:"https://streamify.com/legal/terms/2026-01"`
+	Mandatory   bool       `json:"mandatory"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// ListLegalDocuments returns the current legal documents.
+// @Summary      List legal documents
+// @Description  Returns the current version of the terms of service and privacy policy. Pass all=true for every version.
+// @Tags         Legal
+// @Produce      json
+// @Param        all  query     bool  false  "Include past and scheduled versions"
+// @Success      200  {array}   LegalDocumentResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Router       /api/v1/legal/documents [get]
+func (h *LegalHandler) ListLegalDocuments(w http.ResponseWriter, r *http.Request) {
+	docs, appErr := h.Service.ListLegalDocuments(r.Context(), r.URL.Query().Get("all") == "true")
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapLegalDocuments(docs))
+}
+
+// CreateLegalDocument publishes a new legal document version.
+// @Summary      Publish legal document
+// @Description  Publishes a new version of the terms or privacy policy. Once a mandatory version is published, users must accept it before using the API. Admin only.
+// @Tags         Legal
+// @Accept       json
+// @Produce      json
+// @Param        body  body      CreateLegalDocumentRequest  true  "Document version"
+// @Success      201   {object}  LegalDocumentResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      409   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/legal/documents [post]
+func (h *LegalHandler) CreateLegalDocument(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateLegalDocumentRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	doc, appErr := h.Service.CreateLegalDocument(ctx, service.CreateLegalDocumentParams{
+		Kind:        req.Kind,
+		Version:     req.Version,
+		Title:       req.Title,
+		URL:         req.URL,
+		Mandatory:   req.Mandatory,
+		PublishedAt: req.PublishedAt,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Legal document published", "kind", doc.Kind, "version", doc.Version, "mandatory", doc.Mandatory)
+	utils.RespondWithJSON(w, http.StatusCreated, MapLegalDocumentToResponse(doc))
+}
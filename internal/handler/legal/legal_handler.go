@@ -0,0 +1,98 @@
+package legal
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/service"
+)
+
+type LegalHandler struct {
+	App     *app.AppConfig
+	Service *service.ConsentService
+}
+
+func NewLegalHandler(app *app.AppConfig) *LegalHandler {
+	return &LegalHandler{App: app}
+}
+
+// LegalDocumentResponse describes a version of the terms or privacy policy
+type LegalDocumentResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Kind        string    `json:"kind"`
+	Version     string    `json:"version"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Mandatory   bool      `json:"mandatory"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ConsentResponse describes a consent given by the user
+type ConsentResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	Kind            string     `json:"kind"`
+	DocumentID      *uuid.UUID `json:"document_id,omitempty"`
+	DocumentVersion string     `json:"document_version,omitempty"`
+	IPAddress       string     `json:"ip_address,omitempty"`
+	AcceptedAt      time.Time  `json:"accepted_at"`
+	WithdrawnAt     *time.Time `json:"withdrawn_at,omitempty"`
+}
+
+// ConsentsResponse lists a user's consents and the documents still to accept
+type ConsentsResponse struct {
+	Consents []ConsentResponse       `json:"consents"`
+	Pending  []LegalDocumentResponse `json:"pending"`
+}
+
+func MapLegalDocumentToResponse(d database.LegalDocument) LegalDocumentResponse {
+	return LegalDocumentResponse{
+		ID:          d.ID,
+		Kind:        d.Kind,
+		Version:     d.Version,
+		Title:       d.Title,
+		URL:         d.Url,
+		Mandatory:   d.Mandatory,
+		PublishedAt: d.PublishedAt,
+	}
+}
+
+func mapLegalDocuments(docs []database.LegalDocument) []LegalDocumentResponse {
+	response := make([]LegalDocumentResponse, len(docs))
+	for i, d := range docs {
+		response[i] = MapLegalDocumentToResponse(d)
+	}
+	return response
+}
+
+func mapConsentToResponse(c database.UserConsent) ConsentResponse {
+	return mapConsentRowToResponse(database.ListUserConsentsRow{
+		ID:          c.ID,
+		UserID:      c.UserID,
+		Kind:        c.Kind,
+		DocumentID:  c.DocumentID,
+		IpAddress:   c.IpAddress,
+		UserAgent:   c.UserAgent,
+		AcceptedAt:  c.AcceptedAt,
+		WithdrawnAt: c.WithdrawnAt,
+		WithdrawnIp: c.WithdrawnIp,
+	})
+}
+
+func mapConsentRowToResponse(c database.ListUserConsentsRow) ConsentResponse {
+	resp := ConsentResponse{
+		ID:              c.ID,
+		Kind:            c.Kind,
+		DocumentVersion: c.DocumentVersion.String,
+		IPAddress:       c.IpAddress.String,
+		AcceptedAt:      c.AcceptedAt,
+	}
+	if c.DocumentID.Valid {
+		resp.DocumentID = &c.DocumentID.UUID
+	}
+	if c.WithdrawnAt.Valid {
+		resp.WithdrawnAt = &c.WithdrawnAt.Time
+	}
+	return resp
+}
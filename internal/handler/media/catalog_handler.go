@@ -0,0 +1,293 @@
+package media
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/agegate"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type CreateMediaRequest struct {
+	// Type is song or video
+	Type     string    `json:"type"`
+	ArtistID uuid.UUID `json:"artist_id"`
+	Title    string    `json:"title"`
+	// Duration is in seconds
+	Duration *int32 `json:"duration"`
+	Explicit bool   `json:"explicit"`
+
+	// Songs: exactly one of audio_url and audio_key
+	AlbumID     *uuid.UUID `json:"album_id"`
+	AudioUrl    *string    `json:"audio_url"`
+	AudioKey    *string    `json:"audio_key"`
+	Bitrate     *int32     `json:"bitrate"`
+	DiscNumber  *int32     `json:"disc_number"`
+	TrackNumber *int32     `json:"track_number"`
+
+	// Videos: exactly one of video_url and video_key
+	VideoUrl   *string `json:"video_url"`
+	VideoKey   *string `json:"video_key"`
+	Resolution *string `json:"resolution"`
+}
+
+// CreateMedia adds a song or video to an artist's catalog.
+// @Summary      Create media
+// @Description  Creates a song or video together with its subtype row in one transaction. Songs take audio_url or the storage key of an uploaded file in audio_key, plus optional bitrate and album placement; videos take video_url or video_key and an optional resolution. Fields of the other type are rejected. Needs an access token in the artist's context (any member role) or an admin.
+// @Tags         Media
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateMediaRequest  true  "Media"
+// @Success      201      {object}  MediaResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/media [post]
+func (h *MediaHandler) CreateMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req CreateMediaRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.ArtistID == uuid.Nil {
+		utils.RespondWithFieldErrors(w, http.StatusBadRequest, "Validation failed", map[string]string{"artist_id": "is required"})
+		return
+	}
+
+	item, appErr := h.Service.CreateMedia(ctx, actor, service.CreateMediaParams{
+		Type:        req.Type,
+		ArtistID:    req.ArtistID,
+		Title:       req.Title,
+		Duration:    req.Duration,
+		Explicit:    req.Explicit,
+		AlbumID:     req.AlbumID,
+		AudioUrl:    req.AudioUrl,
+		AudioKey:    req.AudioKey,
+		Bitrate:     req.Bitrate,
+		DiscNumber:  req.DiscNumber,
+		TrackNumber: req.TrackNumber,
+		VideoUrl:    req.VideoUrl,
+		VideoKey:    req.VideoKey,
+		Resolution:  req.Resolution,
+	})
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Media created", "media_id", item.Medium.ID, "type", item.Medium.Type, "artist_id", req.ArtistID, "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusCreated, h.mapMedia(item))
+}
+
+// GetMedia returns a song or video.
+// @Summary      Get media
+// @Description  Returns the media with its song or video fields and a stream URL. Explicit media answers 403 with code explicit_content_blocked when the caller's profile may not see it. Taken down media is only visible to admins and the artist's members.
+// @Tags         Media
+// @Produce      json
+// @Param        id   path      string  true  "Media ID"
+// @Success      200  {object}  MediaResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/media/{id} [get]
+func (h *MediaHandler) GetMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	viewer, appErr := h.viewer(ctx)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	mediaID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	item, appErr := h.Service.GetMedia(ctx, viewer, mediaID)
+	if appErr != nil {
+		if errors.Is(appErr.Err, service.ErrExplicitBlocked) {
+			utils.RespondWithErrorCode(w, appErr.Code, agegate.BlockedCode, appErr.Message)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, h.mapMedia(item))
+}
+
+// ListMedia returns a page of songs and videos.
+// @Summary      List media
+// @Description  Lists media newest first with keyset pagination, filtered by type, artist and album. Explicit media is left out when the caller's profile may not see it. Follow next_cursor or the Link rel="next" header for the next page; filters must stay the same across pages.
+// @Tags         Media
+// @Produce      json
+// @Param        type                query     string  false  "Media type"  Enums(song, video)
+// @Param        artist_id           query     string  false  "Artist ID"
+// @Param        album_id            query     string  false  "Album ID"
+// @Param        include_taken_down  query     bool    false  "Include taken down media (admin only)"
+// @Param        limit               query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor              query     string  false  "Cursor from the previous page"
+// @Success      200                 {object}  MediaListResponse
+// @Header       200                 {string}  Link  "RFC 8288 links to the next and first pages"
+// @Failure      400                 {object}  utils.ErrorResponse
+// @Failure      401                 {object}  utils.ErrorResponse
+// @Failure      403                 {object}  utils.ErrorResponse
+// @Failure      500                 {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/media [get]
+func (h *MediaHandler) ListMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	viewer, appErr := h.viewer(ctx)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	query := r.URL.Query()
+	params := service.ListMediaParams{Type: query.Get("type")}
+	if v := query.Get("artist_id"); v != "" {
+		artistID, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "artist_id must be a valid UUID", err)
+			return
+		}
+		params.ArtistID = &artistID
+	}
+	if v := query.Get("album_id"); v != "" {
+		albumID, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "album_id must be a valid UUID", err)
+			return
+		}
+		params.AlbumID = &albumID
+	}
+	if v := query.Get("include_taken_down"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "include_taken_down must be true or false", err)
+			return
+		}
+		params.IncludeTakenDown = include
+	}
+
+	// Cursors are bound to the filters they were issued for
+	scopeQuery := url.Values{}
+	for _, key := range []string{"type", "artist_id", "album_id", "include_taken_down"} {
+		scopeQuery.Set(key, query.Get(key))
+	}
+	scope := "media?" + scopeQuery.Encode()
+	var err error
+	params.Page, err = h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	result, appErr := h.Service.ListMedia(ctx, viewer, params)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
+	resp := MediaListResponse{
+		Media:      make([]MediaResponse, len(result.Media)),
+		Limit:      params.Page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
+	}
+	for i, item := range result.Media {
+		resp.Media[i] = h.mapMedia(item)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// PatchMedia applies a JSON merge patch to a song or video.
+// @Summary      Patch media
+// @Description  RFC 7396 merge patch of title, duration and explicit, plus bitrate for songs or resolution for videos: absent fields are left unchanged and null clears a field (title and explicit can't be cleared). Album placement is set through the album's tracklist. Needs an access token in the artist's context (any member role) or an admin.
+// @Tags         Media
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id       path      string              true  "Media ID"
+// @Param        request  body      service.MediaPatch  true  "Fields to change; null clears"
+// @Success      200      {object}  MediaResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      415      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/media/{id} [patch]
+func (h *MediaHandler) PatchMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, err := artistActor(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	mediaID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req service.MediaPatch
+	if err := patch.Decode(r, &req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid merge patch", err)
+		return
+	}
+
+	item, appErr := h.Service.PatchMedia(ctx, actor, mediaID, req)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Media patched", "media_id", mediaID, "user_id", actor.UserID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapMedia(item))
+}
@@ -0,0 +1,126 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type MediaHandler struct {
+	App     *app.AppConfig
+	Service *service.MediaService
+	// Users resolves the viewer's explicit content settings
+	Users *service.UserService
+}
+
+func NewMediaHandler(app *app.AppConfig) *MediaHandler {
+	return &MediaHandler{App: app}
+}
+
+// artistActor describes the caller for artist permission checks
+func artistActor(ctx context.Context) (service.ArtistActor, error) {
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		return service.ArtistActor{}, err
+	}
+	return service.ArtistActor{
+		UserID:   userID,
+		Admin:    middleware.IsAdmin(ctx),
+		ArtistID: middleware.GetArtistID(ctx),
+	}, nil
+}
+
+// viewer describes the caller for media reads
+func (h *MediaHandler) viewer(ctx context.Context) (service.MediaViewer, *utils.AppError) {
+	actor, err := artistActor(ctx)
+	if err != nil {
+		return service.MediaViewer{}, &utils.AppError{Code: http.StatusUnauthorized, Message: "Authentication required"}
+	}
+	allowed, appErr := h.Users.ExplicitAllowed(ctx, actor.UserID, middleware.GetProfileID(ctx))
+	if appErr != nil {
+		return service.MediaViewer{}, appErr
+	}
+	return service.MediaViewer{Actor: actor, ExplicitAllowed: allowed}, nil
+}
+
+// MediaResponse is a song or video. Fields of the other subtype are omitted.
+type MediaResponse struct {
+	ID uuid.UUID `json:"id"`
+	// Type is song or video
+	Type            string     `json:"type"`
+	Title           string     `json:"title"`
+	ArtistID        *uuid.UUID `json:"artist_id"`
+	DurationSeconds *int32     `json:"duration_seconds"`
+	Explicit        bool       `json:"explicit"`
+	// StreamURL plays the media; presigned URLs expire after an hour
+	StreamURL string `json:"stream_url,omitempty"`
+
+	// Songs
+	AlbumID     *uuid.UUID `json:"album_id,omitempty"`
+	DiscNumber  *int32     `json:"disc_number,omitempty"`
+	TrackNumber *int32     `json:"track_number,omitempty"`
+	// Bitrate is in kbps
+	Bitrate *int32 `json:"bitrate,omitempty"`
+
+	// Videos
+	Resolution *string `json:"resolution,omitempty"`
+
+	// TakenDownAt and TakedownReason are only set on taken down media
+	TakenDownAt    *time.Time `json:"taken_down_at,omitempty"`
+	TakedownReason *string    `json:"takedown_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type MediaListResponse struct {
+	Media      []MediaResponse `json:"media"`
+	Limit      int32           `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+func (h *MediaHandler) mapMedia(item service.MediaItem) MediaResponse {
+	m := item.Medium
+	resp := MediaResponse{
+		ID:        m.ID,
+		Type:      m.Type,
+		Title:     m.Title,
+		Explicit:  m.Explicit,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if m.ArtistID.Valid {
+		resp.ArtistID = &m.ArtistID.UUID
+	}
+	if m.Duration.Valid {
+		resp.DurationSeconds = &m.Duration.Int32
+	}
+	if m.TakenDownAt.Valid {
+		resp.TakenDownAt = &m.TakenDownAt.Time
+		resp.TakedownReason = &m.TakedownReason.String
+	} else {
+		resp.StreamURL = service.StreamURL(h.App, item)
+	}
+	if item.AlbumID.Valid {
+		resp.AlbumID = &item.AlbumID.UUID
+	}
+	if item.DiscNumber.Valid && item.AlbumID.Valid {
+		resp.DiscNumber = &item.DiscNumber.Int32
+	}
+	if item.TrackNumber.Valid {
+		resp.TrackNumber = &item.TrackNumber.Int32
+	}
+	if item.Bitrate.Valid {
+		resp.Bitrate = &item.Bitrate.Int32
+	}
+	if item.Resolution.Valid {
+		resp.Resolution = &item.Resolution.String
+	}
+	return resp
+}
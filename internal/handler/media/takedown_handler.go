@@ -0,0 +1,94 @@
+package media
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type TakeDownRequest struct {
+	// Reason is shown to the artist's members, for instance the rights complaint
+	Reason string `json:"reason"`
+}
+
+// TakeDown hides a song or video from listeners.
+// @Summary      Take down media
+// @Description  Hides the media from every listing and lookup except for admins and the artist's members. The media and its files are kept; taking down again replaces the reason. Admin only.
+// @Tags         Media
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string           true  "Media ID"
+// @Param        request  body      TakeDownRequest  true  "Reason"
+// @Success      200      {object}  MediaResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      404      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/media/{id}/takedown [put]
+func (h *MediaHandler) TakeDown(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	mediaID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	var req TakeDownRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	item, appErr := h.Service.TakeDown(ctx, mediaID, req.Reason)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	adminID, _ := middleware.GetUserUUID(ctx)
+	logger.Info(ctx, "Media taken down", "media_id", mediaID, "admin_id", adminID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapMedia(item))
+}
+
+// Restore reverses a takedown.
+// @Summary      Restore media
+// @Description  Makes taken down media visible to listeners again. Admin only.
+// @Tags         Media
+// @Produce      json
+// @Param        id   path      string  true  "Media ID"
+// @Success      200  {object}  MediaResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/media/{id}/takedown [delete]
+func (h *MediaHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	mediaID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	item, appErr := h.Service.Restore(ctx, mediaID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	adminID, _ := middleware.GetUserUUID(ctx)
+	logger.Info(ctx, "Media restored", "media_id", mediaID, "admin_id", adminID)
+	utils.RespondWithJSON(w, http.StatusOK, h.mapMedia(item))
+}
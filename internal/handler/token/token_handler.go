@@ -91,6 +91,8 @@ func (h *TokenHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		IpAddress:    IpAddress,
 		UserAgent:    UserAgent,
 		ExpiresAt:    time.Now().Add(RefreshTokenTTL),
+		ProfileID:    session.ProfileID,
+		ArtistID:     session.ArtistID,
 	})
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to create new session", err)
@@ -112,6 +114,8 @@ func (h *TokenHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		user.LastName.String,
 		user.PhoneNumber.String,
 		user.Email,
+		session.ProfileID.UUID,
+		session.ArtistID.UUID,
 	)
 
 	if err != nil {
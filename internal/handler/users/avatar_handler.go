@@ -0,0 +1,87 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// AvatarResponse points at the uploaded avatar's square renditions
+type AvatarResponse struct {
+	AvatarUrl string `json:"avatar_url"`
+	// Renditions maps the edge length in pixels to the rendition URL
+	Renditions map[string]string `json:"renditions"`
+}
+
+// UploadAvatar replaces the authenticated user's avatar with an uploaded image.
+// @Summary      Upload avatar
+// @Description  Accepts a JPEG, PNG or GIF in the "file" form field. The image is center-cropped to a square, stripped of metadata and stored as 64, 256 and 640 pixel JPEG renditions; avatar_url is set to the 640 pixel one.
+// @Tags         Users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "Avatar image"
+// @Success      200   {object}  AvatarResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      413   {object}  utils.ErrorResponse
+// @Failure      415   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/avatar [put]
+func (h *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	data, err := utils.ReadUpload(w, r, "file", h.App.ImageLimits.MaxBytes)
+	if err != nil {
+		utils.RespondWithUploadError(w, err)
+		return
+	}
+
+	user, appErr := h.Service.UploadAvatar(ctx, userID, data)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Avatar uploaded", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusOK, AvatarResponse{
+		AvatarUrl:  user.AvatarUrl.String,
+		Renditions: service.RenditionURLs(h.App, user.AvatarKey),
+	})
+}
+
+// DeleteAvatar removes the authenticated user's avatar.
+// @Summary      Delete avatar
+// @Description  Clears avatar_url and deletes any uploaded renditions.
+// @Tags         Users
+// @Success      204
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/avatar [delete]
+func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	if _, appErr := h.Service.DeleteAvatar(ctx, userID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Avatar deleted", "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
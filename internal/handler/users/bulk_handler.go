@@ -0,0 +1,485 @@
+package users
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// maxImportBytes bounds CSV imports; 10000 rows of email, role and note fit comfortably
+const maxImportBytes = 4 << 20
+
+// UserFilterRequest selects users with the same filters as GET /users
+type UserFilterRequest struct {
+	Query       string `json:"q"`
+	PhoneNumber string `json:"phone_number"`
+	Role        string `json:"role" enums:"user,customer,admin,owner"`
+	Status      string `json:"status"`
+	Verified    *bool  `json:"verified"`
+	Locked      *bool  `json:"locked"`
+}
+
+// BulkJobRequest starts a bulk operation over user_ids or every user matching filter
+type BulkJobRequest struct {
+	Action  string             `json:"action" enums:"lock,unlock,set_role,delete,logout"`
+	Role    string             `json:"role,omitempty" enums:"user,customer,admin"`
+	UserIDs []uuid.UUID        `json:"user_ids,omitempty"`
+	Filter  *UserFilterRequest `json:"filter,omitempty"`
+	DryRun  bool               `json:"dry_run"`
+}
+
+// BulkJobResponse describes a bulk job and how far it got
+type BulkJobResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Action     string     `json:"action"`
+	Role       string     `json:"role,omitempty"`
+	DryRun     bool       `json:"dry_run"`
+	Status     string     `json:"status" enums:"pending,running,completed"`
+	Total      int64      `json:"total"`
+	Done       int64      `json:"done"`
+	Skipped    int64      `json:"skipped"`
+	Failed     int64      `json:"failed"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// BulkJobItemResponse is the result for one user or CSV row; in dry runs "done" means it would change
+type BulkJobItemResponse struct {
+	Position    int32      `json:"position"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	Status      string     `json:"status" enums:"pending,done,skipped,failed"`
+	Message     string     `json:"message,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+// CreateBulkJob queues a bulk operation over users.
+// @Summary      Start a bulk user operation
+// @Description  Locks, unlocks, changes the role of, soft-deletes or signs out many users in a background job. Give either user_ids or a filter (same filters as GET /users; resolved when the job is created, max 10000 users). The owner and the calling admin are always skipped. With dry_run nothing changes and the results say what would. Poll GET /users/bulk/{id} for progress. Admin only.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      BulkJobRequest  true  "Operation and targets"
+// @Success      202      {object}  BulkJobResponse
+// @Header       202      {string}  Location  "URL of the job"
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/bulk [post]
+func (h *UserHandler) CreateBulkJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	adminID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req BulkJobRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	params := service.CreateBulkUserJobParams{
+		CreatedBy: adminID,
+		Action:    strings.ToLower(req.Action),
+		Role:      strings.ToLower(req.Role),
+		UserIDs:   req.UserIDs,
+		DryRun:    req.DryRun,
+	}
+	if req.Filter != nil {
+		params.Filter = &service.UserFilter{
+			Query:       req.Filter.Query,
+			PhoneNumber: req.Filter.PhoneNumber,
+			Role:        req.Filter.Role,
+			Status:      req.Filter.Status,
+			Verified:    req.Filter.Verified,
+			Locked:      req.Filter.Locked,
+		}
+	}
+
+	job, appErr := h.Service.CreateBulkUserJob(ctx, params)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Bulk user job created", "job_id", job.BulkUserJob.ID, "action", params.Action, "total", job.Total, "dry_run", params.DryRun)
+	w.Header().Set("Location", "/api/v1/users/bulk/"+job.BulkUserJob.ID.String())
+	utils.RespondWithJSON(w, http.StatusAccepted, mapBulkJob(job.BulkUserJob, job.Total, job.Done, job.Skipped, job.Failed))
+}
+
+// ListBulkJobs lists bulk jobs, newest first.
+// @Summary      List bulk user operations
+// @Description  Returns bulk jobs and CSV imports with their progress, newest first. Admin only.
+// @Tags         Users
+// @Produce      json
+// @Param        limit   query     int  false  "Max results per page (default 20, max 100)"
+// @Param        offset  query     int  false  "Offset for pagination (default 0)"
+// @Success      200     {array}   BulkJobResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      403     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/bulk [get]
+func (h *UserHandler) ListBulkJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := h.parseInt(r.URL.Query().Get("limit"), 20)
+	if limit > 100 {
+		limit = 100
+	}
+	offset := h.parseInt(r.URL.Query().Get("offset"), 0)
+
+	jobs, appErr := h.Service.ListBulkUserJobs(ctx, int32(limit), int32(offset))
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	response := make([]BulkJobResponse, len(jobs))
+	for i, j := range jobs {
+		response[i] = mapBulkJob(j.BulkUserJob, j.Total, j.Done, j.Skipped, j.Failed)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GetBulkJob returns a bulk job's progress.
+// @Summary      Get a bulk user operation
+// @Description  Returns the status and result counts of a bulk job or CSV import. Admin only.
+// @Tags         Users
+// @Produce      json
+// @Param        id   path      string  true  "Job ID (UUID)"
+// @Success      200  {object}  BulkJobResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/bulk/{id} [get]
+func (h *UserHandler) GetBulkJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	job, appErr := h.Service.GetBulkUserJob(ctx, id)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, mapBulkJob(job.BulkUserJob, job.Total, job.Done, job.Skipped, job.Failed))
+}
+
+// ListBulkJobItems returns the per-user results of a bulk job.
+// @Summary      List bulk operation results
+// @Description  Returns what happened to each user or CSV row of a bulk job, in order. Admin only.
+// @Tags         Users
+// @Produce      json
+// @Param        id      path      string  true   "Job ID (UUID)"
+// @Param        status  query     string  false  "Only results with this status"  Enums(pending, done, skipped, failed)
+// @Param        limit   query     int     false  "Max results per page (default 100, max 1000)"
+// @Param        offset  query     int     false  "Offset for pagination (default 0)"
+// @Success      200     {array}   BulkJobItemResponse
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      404     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/bulk/{id}/items [get]
+func (h *UserHandler) ListBulkJobItems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := h.parseInt(query.Get("limit"), 100)
+	if limit > 1000 {
+		limit = 1000
+	}
+	offset := h.parseInt(query.Get("offset"), 0)
+
+	items, appErr := h.Service.ListBulkUserJobItems(ctx, id, query.Get("status"), int32(limit), int32(offset))
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	response := make([]BulkJobItemResponse, len(items))
+	for i, item := range items {
+		response[i] = BulkJobItemResponse{
+			Position: item.Position,
+			Email:    item.Email.String,
+			Status:   string(item.Status),
+			Message:  item.Message.String,
+		}
+		if item.UserID.Valid {
+			response[i].UserID = &item.UserID.UUID
+		}
+		if item.ProcessedAt.Valid {
+			response[i].ProcessedAt = &item.ProcessedAt.Time
+		}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// ExportUsers streams the filtered user list as CSV.
+// @Summary      Export users as CSV
+// @Description  Downloads every user matching the filters (same as GET /users), newest first, as CSV with the columns id, username, email, first_name, last_name, phone_number, role, status, is_verified, is_locked, created_at. Admin only.
+// @Tags         Users
+// @Produce      text/csv
+// @Param        q             query     string  false  "Search username, email and name"
+// @Param        phone_number  query     string  false  "Search by phone number (partial match)"
+// @Param        role          query     string  false  "Filter by role"  Enums(user, customer, admin, owner)
+// @Param        status        query     string  false  "Filter by account status, e.g. active or deleted"
+// @Param        verified      query     bool    false  "Filter by email verification"
+// @Param        locked        query     bool    false  "Filter by locked accounts"
+// @Success      200           {file}    file
+// @Failure      400           {object}  utils.ErrorResponse
+// @Failure      401           {object}  utils.ErrorResponse
+// @Failure      403           {object}  utils.ErrorResponse
+// @Failure      500           {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/export [get]
+func (h *UserHandler) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, msg := userFilterFromQuery(r.URL.Query())
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// Headers go out with the first row, so errors after that can only cut the file short
+	out := csv.NewWriter(w)
+	started := false
+	appErr := h.Service.ExportUsers(ctx, filter, func(u database.User) error {
+		if !started {
+			started = true
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="users-%s.csv"`, time.Now().UTC().Format("20060102-150405")))
+			if err := out.Write(exportColumns); err != nil {
+				return err
+			}
+		}
+		return out.Write(exportRow(u))
+	})
+	if appErr != nil {
+		if started {
+			logger.Error(ctx, "ExportUsers: export interrupted", appErr.Err)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+	if !started {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		out.Write(exportColumns)
+	}
+	out.Flush()
+}
+
+var exportColumns = []string{"id", "username", "email", "first_name", "last_name", "phone_number", "role", "status", "is_verified", "is_locked", "created_at"}
+
+func exportRow(u database.User) []string {
+	return []string{
+		u.ID.String(),
+		csvCell(u.Username),
+		csvCell(u.Email),
+		csvCell(u.FirstName.String),
+		csvCell(u.LastName.String),
+		csvCell(u.PhoneNumber.String),
+		string(u.Role),
+		u.Status,
+		strconv.FormatBool(u.IsVerified),
+		strconv.FormatBool(u.IsLocked),
+		u.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// csvCell stops spreadsheets from running user-controlled text as a formula
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// ImportUsers queues invitations for accounts listed in a CSV file.
+// @Summary      Import users from CSV
+// @Description  Pre-provisions accounts from a CSV upload (form field "file") with a header row and the columns email (required), role (user, customer or admin; default user) and note. Each row gets a single-use invitation bound to its email, valid for 14 days, and an invitation email; registering with it grants the role. Registered and already invited emails are skipped. Runs as a bulk job; dry_run only validates. Admin only.
+// @Tags         Users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file     formData  file    true   "CSV file"
+// @Param        dry_run  query     bool    false  "Only report what would happen"
+// @Success      202      {object}  BulkJobResponse
+// @Header       202      {string}  Location  "URL of the job"
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      413      {object}  utils.ErrorResponse
+// @Failure      415      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/import [post]
+func (h *UserHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	adminID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	dryRun, err := parseOptionalBool(r.URL.Query().Get("dry_run"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "dry_run must be true or false", err)
+		return
+	}
+
+	data, err := utils.ReadUpload(w, r, "file", maxImportBytes)
+	if err != nil {
+		utils.RespondWithUploadError(w, err)
+		return
+	}
+
+	rows, err := parseImportCSV(bytes.NewReader(data))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid CSV: "+err.Error())
+		return
+	}
+
+	job, appErr := h.Service.CreateImportJob(ctx, adminID, rows, dryRun != nil && *dryRun)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "User import queued", "job_id", job.BulkUserJob.ID, "rows", job.Total, "dry_run", job.BulkUserJob.DryRun)
+	w.Header().Set("Location", "/api/v1/users/bulk/"+job.BulkUserJob.ID.String())
+	utils.RespondWithJSON(w, http.StatusAccepted, mapBulkJob(job.BulkUserJob, job.Total, job.Done, job.Skipped, job.Failed))
+}
+
+// parseImportCSV reads the email, role and note columns by header name; other columns are ignored
+func parseImportCSV(r io.Reader) ([]service.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	emailCol, ok := columns["email"]
+	if !ok {
+		return nil, errors.New("the header row needs an email column")
+	}
+	cell := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []service.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(rows) == service.MaxBulkTargets {
+			return nil, fmt.Errorf("imports are limited to %d rows", service.MaxBulkTargets)
+		}
+		email := ""
+		if emailCol < len(record) {
+			email = strings.TrimSpace(record[emailCol])
+		}
+		rows = append(rows, service.ImportRow{
+			Email: email,
+			Role:  cell(record, "role"),
+			Note:  cell(record, "note"),
+		})
+	}
+}
+
+// userFilterFromQuery reads the GET /users filters; msg is set when one is malformed
+func userFilterFromQuery(query url.Values) (service.UserFilter, string) {
+	filter := service.UserFilter{
+		Query:       query.Get("q"),
+		PhoneNumber: query.Get("phone_number"),
+		Role:        query.Get("role"),
+		Status:      query.Get("status"),
+	}
+	var err error
+	if filter.Verified, err = parseOptionalBool(query.Get("verified")); err != nil {
+		return service.UserFilter{}, "verified must be true or false"
+	}
+	if filter.Locked, err = parseOptionalBool(query.Get("locked")); err != nil {
+		return service.UserFilter{}, "locked must be true or false"
+	}
+	return filter, ""
+}
+
+func mapBulkJob(j database.BulkUserJob, total, done, skipped, failed int64) BulkJobResponse {
+	resp := BulkJobResponse{
+		ID:        j.ID,
+		Action:    j.Action,
+		Role:      string(j.Role.UserRole),
+		DryRun:    j.DryRun,
+		Status:    string(j.Status),
+		Total:     total,
+		Done:      done,
+		Skipped:   skipped,
+		Failed:    failed,
+		CreatedAt: j.CreatedAt,
+	}
+	if j.CreatedBy.Valid {
+		resp.CreatedBy = &j.CreatedBy.UUID
+	}
+	if j.StartedAt.Valid {
+		resp.StartedAt = &j.StartedAt.Time
+	}
+	if j.FinishedAt.Valid {
+		resp.FinishedAt = &j.FinishedAt.Time
+	}
+	return resp
+}
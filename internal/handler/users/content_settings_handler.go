@@ -0,0 +1,56 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ContentSettingsResponse describes the explicit content rules applied to the user
+type ContentSettingsResponse struct {
+	BirthDate       string `json:"birth_date,omitempty"`
+	Country         string `json:"country,omitempty"`
+	Adult           bool   `json:"adult"`
+	FilterExplicit  bool   `json:"filter_explicit"`
+	KidProfile      bool   `json:"kid_profile"`
+	ExplicitAllowed bool   `json:"explicit_allowed"`
+}
+
+// GetContentSettings returns the authenticated user's explicit content settings.
+// @Summary      Get content settings
+// @Description  Returns whether explicit content is shown to the current user. Minors, users without a birth date, adults with filter_explicit and kid profiles never see explicit content. Set birth_date, country and filter_explicit through the profile update.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  ContentSettingsResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/content-settings [get]
+func (h *UserHandler) GetContentSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	settings, appErr := h.Service.GetContentSettings(ctx, userID, middleware.GetProfileID(ctx))
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	resp := ContentSettingsResponse{
+		Country:         settings.Country,
+		Adult:           settings.Adult,
+		FilterExplicit:  settings.FilterExplicit,
+		KidProfile:      settings.KidProfile,
+		ExplicitAllowed: settings.ExplicitAllowed,
+	}
+	if settings.BirthDate != nil {
+		resp.BirthDate = settings.BirthDate.Format("2006-01-02")
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
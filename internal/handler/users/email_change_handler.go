@@ -0,0 +1,210 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/models"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ChangeEmailRequest represents a change-email payload
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// EmailChangeResponse describes an email change request without its tokens
+type EmailChangeResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	NewEmail    string     `json:"new_email"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func mapEmailChangeToResponse(req database.EmailChangeRequest) EmailChangeResponse {
+	resp := EmailChangeResponse{
+		ID:        req.ID,
+		NewEmail:  req.NewEmail,
+		Status:    req.Status,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: req.CreatedAt,
+	}
+	if req.CompletedAt.Valid {
+		resp.CompletedAt = &req.CompletedAt.Time
+	}
+	return resp
+}
+
+// RequestEmailChange starts an email change for the authenticated user.
+// @Summary      Request email change
+// @Description  Sends a confirmation link to the new address and a revocation link to the current one. The email is only changed once the new address is confirmed.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      ChangeEmailRequest  true  "New email and current password"
+// @Success      202   {object}  EmailChangeResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      409   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/email [post]
+func (h *UserHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		logger.Warn(ctx, "RequestEmailChange: invalid user ID in context")
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		logger.Warn(ctx, "RequestEmailChange: malformed request", "error", err)
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	change, appErr := h.Service.RequestEmailChange(ctx, service.RequestEmailChangeParams{
+		UserID:   userID,
+		NewEmail: req.NewEmail,
+		Password: req.Password,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Email change requested", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusAccepted, mapEmailChangeToResponse(change))
+}
+
+// GetEmailChange returns the authenticated user's latest email change request.
+// @Summary      Get email change status
+// @Description  Returns the most recent email change request of the current user, including whether it is pending, confirmed or expired.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  EmailChangeResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/email [get]
+func (h *UserHandler) GetEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	change, appErr := h.Service.GetEmailChange(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapEmailChangeToResponse(change))
+}
+
+// CancelEmailChange cancels the authenticated user's pending email change.
+// @Summary      Cancel email change
+// @Description  Cancels the pending email change of the current user. The confirmation link stops working.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/email [delete]
+func (h *UserHandler) CancelEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	if appErr := h.Service.CancelEmailChange(ctx, userID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Email change cancelled"})
+}
+
+// ConfirmEmailChange confirms the new address using the token mailed to it.
+// @Summary      Confirm email change
+// @Description  Confirms the new email address, swaps it on the account and signs out every session.
+// @Tags         Authentication
+// @Produce      json
+// @Param        token  query     string  true  "Confirmation token"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  utils.ErrorResponse
+// @Failure      401    {object}  utils.ErrorResponse
+// @Failure      409    {object}  utils.ErrorResponse
+// @Failure      500    {object}  utils.ErrorResponse
+// @Router       /api/v1/auth/email/confirm [get]
+func (h *UserHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tok := r.URL.Query().Get("token")
+	if tok == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	user, appErr := h.Service.ConfirmEmailChange(ctx, tok)
+	if appErr != nil {
+		logger.Warn(ctx, "ConfirmEmailChange: failed", "error", appErr.Message)
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Email changed successfully", "user_id", user.ID)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Email changed successfully, please log in again",
+		"user":    models.NewUserResponse(&user),
+	})
+}
+
+// RevokeEmailChange cancels or rolls back an email change from the old address.
+// @Summary      Revoke email change
+// @Description  Cancels a pending email change, or restores the previous address if it was already confirmed, and signs out every session. A confirmed change can only be revoked while the account still has the new address and no later change was confirmed; otherwise it answers 409.
+// @Tags         Authentication
+// @Produce      json
+// @Param        token  query     string  true  "Revocation token"
+// @Success      200    {object}  map[string]string
+// @Failure      400    {object}  utils.ErrorResponse
+// @Failure      401    {object}  utils.ErrorResponse
+// @Failure      409    {object}  utils.ErrorResponse
+// @Failure      500    {object}  utils.ErrorResponse
+// @Router       /api/v1/auth/email/revoke [get]
+func (h *UserHandler) RevokeEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tok := r.URL.Query().Get("token")
+	if tok == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	if appErr := h.Service.RevokeEmailChange(ctx, tok); appErr != nil {
+		logger.Warn(ctx, "RevokeEmailChange: failed", "error", appErr.Message)
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Email change revoked and all sessions signed out",
+	})
+}
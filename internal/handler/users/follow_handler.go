@@ -0,0 +1,328 @@
+package users
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type FollowResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	// Status is pending while the user has to approve the request
+	Status database.FollowStatus `json:"status" enums:"pending,accepted"`
+}
+
+type FollowUserResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// Since is when the follow, request or block was made
+	Since time.Time `json:"since"`
+}
+
+type FollowListResponse struct {
+	Users      []FollowUserResponse `json:"users"`
+	Limit      int32                `json:"limit"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	HasMore    bool                 `json:"has_more"`
+}
+
+// FollowUser follows another user.
+// @Summary      Follow user
+// @Description  Follows the user, or sends a follow request when they require approval. Following again is a no-op. Blocked users can't follow.
+// @Tags         Users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  FollowResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      409  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/follow [post]
+func (h *UserHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	targetID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	follow, appErr := h.Service.FollowUser(ctx, userID, targetID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "User followed", "user_id", userID, "target_id", targetID, "status", follow.Status)
+	utils.RespondWithJSON(w, http.StatusOK, FollowResponse{UserID: targetID, Status: follow.Status})
+}
+
+// UnfollowUser stops following a user.
+// @Summary      Unfollow user
+// @Description  Stops following the user or withdraws a pending follow request.
+// @Tags         Users
+// @Param        id   path  string  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/follow [delete]
+func (h *UserHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	h.followAction(w, r, h.Service.UnfollowUser, "User unfollowed")
+}
+
+// RemoveFollower removes one of the authenticated user's followers.
+// @Summary      Remove follower
+// @Description  Makes the user stop following the current user.
+// @Tags         Users
+// @Param        id   path  string  true  "Follower user ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/followers/{id} [delete]
+func (h *UserHandler) RemoveFollower(w http.ResponseWriter, r *http.Request) {
+	h.followAction(w, r, h.Service.RemoveFollower, "Follower removed")
+}
+
+// ApproveFollowRequest accepts a pending follow request.
+// @Summary      Approve follow request
+// @Tags         Users
+// @Param        id   path  string  true  "Requesting user ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/follow-requests/{id}/approve [post]
+func (h *UserHandler) ApproveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	h.followAction(w, r, h.Service.ApproveFollowRequest, "Follow request approved")
+}
+
+// DeclineFollowRequest deletes a pending follow request.
+// @Summary      Decline follow request
+// @Tags         Users
+// @Param        id   path  string  true  "Requesting user ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/follow-requests/{id} [delete]
+func (h *UserHandler) DeclineFollowRequest(w http.ResponseWriter, r *http.Request) {
+	h.followAction(w, r, h.Service.DeclineFollowRequest, "Follow request declined")
+}
+
+// BlockUser blocks another user.
+// @Summary      Block user
+// @Description  Blocks the user: follows between the two are removed, and neither can follow the other or see the other's profile and follow lists.
+// @Tags         Users
+// @Param        id   path  string  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/block [post]
+func (h *UserHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	h.followAction(w, r, h.Service.BlockUser, "User blocked")
+}
+
+// UnblockUser lifts a block.
+// @Summary      Unblock user
+// @Tags         Users
+// @Param        id   path  string  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/block [delete]
+func (h *UserHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	h.followAction(w, r, h.Service.UnblockUser, "User unblocked")
+}
+
+// followAction runs a follow or block change between the caller and the {id} user
+func (h *UserHandler) followAction(w http.ResponseWriter, r *http.Request, action func(context.Context, uuid.UUID, uuid.UUID) *utils.AppError, logMsg string) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	otherID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if appErr := action(ctx, userID, otherID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, logMsg, "user_id", userID, "other_id", otherID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListFollowers lists a user's followers.
+// @Summary      List followers
+// @Description  Newest first, with keyset pagination. Hidden profiles return 404.
+// @Tags         Users
+// @Produce      json
+// @Param        id      path      string  true   "User ID"
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Success      200     {object}  FollowListResponse
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      404     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/followers [get]
+func (h *UserHandler) ListFollowers(w http.ResponseWriter, r *http.Request) {
+	h.userFollowList(w, r, "followers", h.Service.ListFollowers)
+}
+
+// ListFollowing lists the users a user follows.
+// @Summary      List following
+// @Description  Newest first, with keyset pagination. Hidden profiles return 404.
+// @Tags         Users
+// @Produce      json
+// @Param        id      path      string  true   "User ID"
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Success      200     {object}  FollowListResponse
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      404     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/following [get]
+func (h *UserHandler) ListFollowing(w http.ResponseWriter, r *http.Request) {
+	h.userFollowList(w, r, "following", h.Service.ListFollowing)
+}
+
+// ListFollowRequests lists pending requests to follow the authenticated user.
+// @Summary      List follow requests
+// @Tags         Users
+// @Produce      json
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Success      200     {object}  FollowListResponse
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/follow-requests [get]
+func (h *UserHandler) ListFollowRequests(w http.ResponseWriter, r *http.Request) {
+	h.ownFollowList(w, r, "follow-requests", h.Service.ListFollowRequests)
+}
+
+// ListBlockedUsers lists the users the authenticated user blocked.
+// @Summary      List blocked users
+// @Tags         Users
+// @Produce      json
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page"
+// @Success      200     {object}  FollowListResponse
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      401     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/blocks [get]
+func (h *UserHandler) ListBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	h.ownFollowList(w, r, "blocks", h.Service.ListBlockedUsers)
+}
+
+type followListFunc func(pagination.Params) (service.FollowListResult, *utils.AppError)
+
+// userFollowList serves a follow list of the {id} user as seen by the caller
+func (h *UserHandler) userFollowList(w http.ResponseWriter, r *http.Request, name string, list func(context.Context, uuid.UUID, uuid.UUID, pagination.Params) (service.FollowListResult, *utils.AppError)) {
+	ctx := r.Context()
+
+	viewerID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	userID, errMsg := utils.ReadUUIDParam(r, "id")
+	if errMsg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	h.respondFollowList(w, r, name+":"+userID.String(), func(page pagination.Params) (service.FollowListResult, *utils.AppError) {
+		return list(ctx, userID, viewerID, page)
+	})
+}
+
+// ownFollowList serves one of the caller's own lists
+func (h *UserHandler) ownFollowList(w http.ResponseWriter, r *http.Request, name string, list func(context.Context, uuid.UUID, pagination.Params) (service.FollowListResult, *utils.AppError)) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	h.respondFollowList(w, r, name+":"+userID.String(), func(page pagination.Params) (service.FollowListResult, *utils.AppError) {
+		return list(ctx, userID, page)
+	})
+}
+
+func (h *UserHandler) respondFollowList(w http.ResponseWriter, r *http.Request, scope string, list followListFunc) {
+	page, err := h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	result, appErr := list(page)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
+	resp := FollowListResponse{
+		Users:      make([]FollowUserResponse, len(result.Users)),
+		Limit:      page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
+	}
+	for i, u := range result.Users {
+		resp.Users[i] = FollowUserResponse{ID: u.ID, Username: u.Username, Since: u.Since}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
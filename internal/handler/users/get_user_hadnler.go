@@ -6,19 +6,21 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
 	"github.com/techies/streamify/internal/models"
 	"github.com/techies/streamify/internal/utils"
 )
 
 // GetUser returns a user by ID.
 // @Summary      Get user by ID
-// @Description  Get a single user by their unique ID.
+// @Description  Get a single user by their unique ID. The full account, with email and phone number, is only returned to the user themselves and to admins. Other callers get the public profile (PublicProfileResponse) under the same privacy and block rules as GET /profiles/{username}, and 404 when it is hidden from them.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
 // @Param        id    path      string  true   "User ID (UUID)"
 // @Success      200   {object}  models.UserResponse
 // @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
 // @Failure      404   {object}  utils.ErrorResponse
 // @Failure      500   {object}  utils.ErrorResponse
 // @Security     BearerAuth
@@ -33,6 +35,23 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	callerID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	// Contact details stay between the user and admins
+	if callerID != userID && !middleware.IsAdmin(ctx) {
+		profile, appErr := h.Service.GetPublicProfileByID(ctx, userID, callerID)
+		if appErr != nil {
+			utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+			return
+		}
+		utils.RespondWithJSON(w, http.StatusOK, mapPublicProfile(profile))
+		return
+	}
+
 	user, appErr := h.Service.GetUser(ctx, userID)
 	if appErr != nil {
 		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
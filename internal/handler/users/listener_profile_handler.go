@@ -0,0 +1,303 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// CreateListenerProfileRequest represents a new household profile
+type CreateListenerProfileRequest struct {
+	Name      string `json:"name" example:"Kids"`
+	AvatarUrl string `json:"avatar_url,omitempty"`
+	IsKid     bool   `json:"is_kid"`
+	PIN       string `json:"pin,omitempty" example:"1234"`
+}
+
+// UpdateListenerProfileRequest holds the profile fields to change; an empty pin removes the PIN
+type UpdateListenerProfileRequest struct {
+	Name      *string `json:"name,omitempty"`
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+	IsKid     *bool   `json:"is_kid,omitempty"`
+	PIN       *string `json:"pin,omitempty"`
+}
+
+// SelectListenerProfileRequest carries the PIN of a protected profile
+type SelectListenerProfileRequest struct {
+	PIN string `json:"pin,omitempty" example:"1234"`
+}
+
+// ListenerProfileResponse describes a household profile
+type ListenerProfileResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AvatarUrl string    `json:"avatar_url,omitempty"`
+	IsKid     bool      `json:"is_kid"`
+	HasPIN    bool      `json:"has_pin"`
+	IsDefault bool      `json:"is_default"`
+	Selected  bool      `json:"selected"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SelectListenerProfileResponse returns the access token scoped to the selected profile
+type SelectListenerProfileResponse struct {
+	AccessToken string                  `json:"access_token"`
+	Profile     ListenerProfileResponse `json:"profile"`
+}
+
+func mapListenerProfileToResponse(p database.ListenerProfile, selected uuid.UUID) ListenerProfileResponse {
+	return ListenerProfileResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		AvatarUrl: p.AvatarUrl.String,
+		IsKid:     p.IsKid,
+		HasPIN:    p.PinHash.Valid,
+		IsDefault: p.IsDefault,
+		Selected:  p.ID == selected || (selected == uuid.Nil && p.IsDefault),
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+// ListListenerProfiles returns the household profiles of the account.
+// @Summary      List listener profiles
+// @Description  Returns the profiles under the current account, default profile first. selected marks the profile the access token is scoped to.
+// @Tags         Profiles
+// @Produce      json
+// @Success      200  {array}   ListenerProfileResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/profiles [get]
+func (h *UserHandler) ListListenerProfiles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	profiles, appErr := h.Service.ListListenerProfiles(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	selected := middleware.GetProfileID(ctx)
+	response := make([]ListenerProfileResponse, len(profiles))
+	for i, p := range profiles {
+		response[i] = mapListenerProfileToResponse(p, selected)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// CreateListenerProfile adds a household profile.
+// @Summary      Create listener profile
+// @Description  Adds a profile with its own streams, likes, playlists and history. Kid profiles never see explicit content. An optional 4-6 digit PIN is required to select the profile. Not available from kid profiles.
+// @Tags         Profiles
+// @Accept       json
+// @Produce      json
+// @Param        body  body      CreateListenerProfileRequest  true  "Profile"
+// @Success      201   {object}  ListenerProfileResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      409   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/profiles [post]
+func (h *UserHandler) CreateListenerProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req CreateListenerProfileRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	profile, appErr := h.Service.CreateListenerProfile(ctx, service.CreateListenerProfileParams{
+		UserID:          userID,
+		ActingProfileID: middleware.GetProfileID(ctx),
+		Name:            req.Name,
+		AvatarUrl:       req.AvatarUrl,
+		IsKid:           req.IsKid,
+		PIN:             req.PIN,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Listener profile created", "user_id", userID, "profile_id", profile.ID, "kid", profile.IsKid)
+	utils.RespondWithJSON(w, http.StatusCreated, mapListenerProfileToResponse(profile, middleware.GetProfileID(ctx)))
+}
+
+// UpdateListenerProfile changes a household profile.
+// @Summary      Update listener profile
+// @Description  Updates name, avatar, kid flag or PIN. An empty pin removes the PIN. The default profile can't become a kid profile. Not available from kid profiles.
+// @Tags         Profiles
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                        true  "Profile ID (UUID)"
+// @Param        body  body      UpdateListenerProfileRequest  true  "Fields to change"
+// @Success      200   {object}  ListenerProfileResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      409   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/profiles/{id} [put]
+func (h *UserHandler) UpdateListenerProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	profileID, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var req UpdateListenerProfileRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	profile, appErr := h.Service.UpdateListenerProfile(ctx, service.UpdateListenerProfileParams{
+		UserID:          userID,
+		ProfileID:       profileID,
+		ActingProfileID: middleware.GetProfileID(ctx),
+		Name:            req.Name,
+		AvatarUrl:       req.AvatarUrl,
+		IsKid:           req.IsKid,
+		PIN:             req.PIN,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapListenerProfileToResponse(profile, middleware.GetProfileID(ctx)))
+}
+
+// DeleteListenerProfile removes a household profile.
+// @Summary      Delete listener profile
+// @Description  Deletes a profile with its streams, likes and playlists. The default profile can't be deleted. Not available from kid profiles.
+// @Tags         Profiles
+// @Produce      json
+// @Param        id   path      string  true  "Profile ID (UUID)"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/profiles/{id} [delete]
+func (h *UserHandler) DeleteListenerProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	profileID, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if appErr := h.Service.DeleteListenerProfile(ctx, userID, profileID, middleware.GetProfileID(ctx)); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Listener profile deleted", "user_id", userID, "profile_id", profileID)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Profile deleted"})
+}
+
+// SelectListenerProfile scopes the session to a household profile.
+// @Summary      Select listener profile
+// @Description  Switches the current session to a profile and returns an access token carrying the profile claim. Refreshed tokens keep the profile. Protected profiles require their PIN.
+// @Tags         Profiles
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                        true   "Profile ID (UUID)"
+// @Param        body  body      SelectListenerProfileRequest  false  "PIN"
+// @Success      200   {object}  SelectListenerProfileResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/profiles/{id}/select [post]
+func (h *UserHandler) SelectListenerProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	sessionID, err := uuid.Parse(middleware.GetSessionID(ctx))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "A session is required to select a profile", nil)
+		return
+	}
+
+	profileID, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var req SelectListenerProfileRequest
+	if r.ContentLength > 0 {
+		if err := utils.ParseJSON(w, r, &req); err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+			return
+		}
+	}
+
+	result, appErr := h.Service.SelectListenerProfile(ctx, service.SelectListenerProfileParams{
+		UserID:    userID,
+		SessionID: sessionID,
+		ProfileID: profileID,
+		PIN:       req.PIN,
+	})
+	if appErr != nil {
+		if appErr.Code == http.StatusForbidden {
+			logger.Warn(ctx, "Listener profile selection refused", "user_id", userID, "profile_id", profileID)
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, SelectListenerProfileResponse{
+		AccessToken: result.AccessToken,
+		Profile:     mapListenerProfileToResponse(result.Profile, result.Profile.ID),
+	})
+}
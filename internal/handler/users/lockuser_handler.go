@@ -6,17 +6,20 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
 	"github.com/techies/streamify/internal/utils"
 )
 
 // @Summary      Lock user account
-// @Description  Locks a user account by setting is_locked to true
+// @Description  Locks a user account indefinitely and signs it out. Shorthand for a suspension with reason "other"; use POST /users/{id}/suspensions to give a reason or expiry.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "User ID (UUID)"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
 // @Failure      500  {object}  utils.ErrorResponse
 // @Security     BearerAuth
 // @Router       /api/v1/users/{id}/lock [post]
@@ -29,29 +32,34 @@ func (h *UserHandler) LockUser(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
 		return
 	}
-	err = h.App.DB.LockUser(ctx, uid)
+	adminID, err := middleware.GetUserUUID(ctx)
 	if err != nil {
-		logger.Error(ctx, "LockUser: failed to lock user", err, "user_id", uid)
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to lock user", err)
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
 		return
 	}
-	// Invalidate all sessions for this user
-	if err := h.App.DB.DeleteAllUserSessions(ctx, uid); err != nil {
-		logger.Error(ctx, "LockUser: failed to delete user sessions", err, "user_id", uid)
-		// You may want to continue, or return an error depending on your policy
+	_, appErr := h.Service.SuspendUser(ctx, service.SuspendUserParams{
+		UserID:      uid,
+		SuspendedBy: adminID,
+		ReasonCode:  "other",
+	})
+	if appErr != nil {
+		logger.Error(ctx, "LockUser: failed to lock user", appErr.Err, "user_id", uid)
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
 	}
 	logger.Info(ctx, "User locked and sessions invalidated", "user_id", uid)
 	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "User locked and logged out"})
 }
 
 // @Summary      Unlock user account
-// @Description  Unlocks a user account by setting is_locked to false
+// @Description  Unlocks a user account and lifts its open suspensions
 // @Tags         Users
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "User ID (UUID)"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
 // @Failure      500  {object}  utils.ErrorResponse
 // @Security     BearerAuth
 // @Router       /api/v1/users/{id}/unlock [post]
@@ -64,10 +72,14 @@ func (h *UserHandler) UnLockUser(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
 		return
 	}
-	err = h.App.DB.UnlockUser(ctx, uid)
+	adminID, err := middleware.GetUserUUID(ctx)
 	if err != nil {
-		logger.Error(ctx, "UnLockUser: failed to unlock user", err, "user_id", uid)
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to unlock user", err)
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	if appErr := h.Service.LiftSuspension(ctx, uid, adminID); appErr != nil {
+		logger.Error(ctx, "UnLockUser: failed to unlock user", appErr.Err, "user_id", uid)
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
 		return
 	}
 	logger.Info(ctx, "User unlocked successfully", "user_id", uid)
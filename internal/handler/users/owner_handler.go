@@ -0,0 +1,203 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// StartOwnershipTransferRequest represents an ownership transfer payload
+type StartOwnershipTransferRequest struct {
+	ToUserID uuid.UUID `json:"to_user_id"`
+	Password string    `json:"password"`
+}
+
+// OwnershipTransferResponse describes a pending ownership transfer
+type OwnershipTransferResponse struct {
+	ID         uuid.UUID `json:"id"`
+	FromUserID uuid.UUID `json:"from_user_id"`
+	ToUserID   uuid.UUID `json:"to_user_id"`
+	Status     string    `json:"status"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func mapOwnershipTransferToResponse(t database.OwnershipTransfer) OwnershipTransferResponse {
+	return OwnershipTransferResponse{
+		ID:         t.ID,
+		FromUserID: t.FromUserID,
+		ToUserID:   t.ToUserID,
+		Status:     t.Status,
+		ExpiresAt:  t.ExpiresAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// StartOwnershipTransfer offers ownership to an admin.
+// @Summary      Start ownership transfer
+// @Description  Offers ownership to an active admin. Requires the owner's password. Ownership only changes once the admin accepts. Owner only.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      StartOwnershipTransferRequest  true  "Receiving admin and owner password"
+// @Success      202   {object}  OwnershipTransferResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      403   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/owner/transfer [post]
+func (h *UserHandler) StartOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ownerID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req StartOwnershipTransferRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	transfer, appErr := h.Service.StartOwnershipTransfer(ctx, service.StartOwnershipTransferParams{
+		OwnerID:  ownerID,
+		ToUserID: req.ToUserID,
+		Password: req.Password,
+	})
+	if appErr != nil {
+		logger.Warn(ctx, "StartOwnershipTransfer: failed", "error", appErr.Message)
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Ownership transfer started", "from_user_id", ownerID, "to_user_id", req.ToUserID)
+	utils.RespondWithJSON(w, http.StatusAccepted, mapOwnershipTransferToResponse(transfer))
+}
+
+// GetOwnershipTransfer returns the pending ownership transfer.
+// @Summary      Get ownership transfer
+// @Description  Returns the pending ownership transfer. Only visible to the owner and the admin it was offered to.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  OwnershipTransferResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/owner/transfer [get]
+func (h *UserHandler) GetOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	transfer, appErr := h.Service.GetOwnershipTransfer(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapOwnershipTransferToResponse(transfer))
+}
+
+// AcceptOwnershipTransfer completes the pending ownership transfer.
+// @Summary      Accept ownership transfer
+// @Description  Makes the receiving admin the owner and the previous owner an admin. Both are signed out and have to log in again.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      409  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/owner/transfer/accept [post]
+func (h *UserHandler) AcceptOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	if appErr := h.Service.AcceptOwnershipTransfer(ctx, userID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Ownership transferred", "new_owner_id", userID)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "You are now the owner, please log in again",
+	})
+}
+
+// CancelOwnershipTransfer withdraws or declines the pending ownership transfer.
+// @Summary      Cancel ownership transfer
+// @Description  Lets the owner withdraw, or the receiving admin decline, the pending ownership transfer.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/owner/transfer [delete]
+func (h *UserHandler) CancelOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	if appErr := h.Service.CancelOwnershipTransfer(ctx, userID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Ownership transfer cancelled", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Ownership transfer cancelled"})
+}
+
+// DemoteAllAdmins removes the admin role from every admin.
+// @Summary      Demote all admins
+// @Description  Turns every admin into a regular user and signs them out. The owner keeps their role. Owner only.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/admins/demote [post]
+func (h *UserHandler) DemoteAllAdmins(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	demoted, appErr := h.Service.DemoteAllAdmins(ctx)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "All admins demoted", "count", demoted, "by", middleware.GetUserID(ctx))
+	utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Admins demoted",
+		"demoted": demoted,
+	})
+}
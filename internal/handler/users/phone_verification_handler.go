@@ -0,0 +1,98 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/models"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ConfirmPhoneRequest represents a phone verification code submission
+type ConfirmPhoneRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// PhoneVerificationResponse describes a code that was sent
+type PhoneVerificationResponse struct {
+	PhoneNumber string    `json:"phone_number"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// StartPhoneVerification sends a one-time code to the user's phone number.
+// @Summary      Start phone verification
+// @Description  Sends a one-time SMS code to the phone number on the current user's profile.
+// @Tags         Users
+// @Produce      json
+// @Success      202  {object}  PhoneVerificationResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      409  {object}  utils.ErrorResponse
+// @Failure      429  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/phone/verify [post]
+func (h *UserHandler) StartPhoneVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	verification, appErr := h.Service.StartPhoneVerification(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Phone verification code sent", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusAccepted, PhoneVerificationResponse{
+		PhoneNumber: verification.PhoneNumber,
+		ExpiresAt:   verification.ExpiresAt,
+	})
+}
+
+// ConfirmPhoneVerification verifies the user's phone number with the code sent by SMS.
+// @Summary      Confirm phone verification
+// @Description  Checks the one-time code and marks the current user's phone number as verified.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      ConfirmPhoneRequest  true  "Verification code"
+// @Success      200   {object}  models.UserResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      409   {object}  utils.ErrorResponse
+// @Failure      429   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/phone/verify/confirm [post]
+func (h *UserHandler) ConfirmPhoneVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req ConfirmPhoneRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	user, appErr := h.Service.ConfirmPhoneVerification(ctx, userID, req.Code)
+	if appErr != nil {
+		logger.Warn(ctx, "ConfirmPhoneVerification: failed", "user_id", userID, "error", appErr.Message)
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Phone number verified", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusOK, models.UserResponse(MapUserToResponse(user)))
+}
@@ -0,0 +1,148 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/preferences"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// PreferencesResponse is the preferences document described by GET /users/me/preferences/schema
+type PreferencesResponse struct {
+	Version int `json:"version"`
+	preferences.Preferences
+}
+
+// preferencesETag changes with every saved revision and with the document version
+func preferencesETag(p service.UserPreferences) string {
+	return fmt.Sprintf(`"v%d.%d"`, preferences.Version, p.Revision)
+}
+
+func respondWithPreferences(w http.ResponseWriter, p service.UserPreferences) {
+	w.Header().Set("ETag", preferencesETag(p))
+	w.Header().Set("Cache-Control", "private, no-cache")
+	utils.RespondWithJSON(w, http.StatusOK, PreferencesResponse{Version: preferences.Version, Preferences: p.Preferences})
+}
+
+// GetPreferences returns the authenticated user's preferences.
+// @Summary      Get preferences
+// @Description  Returns the user's language, playback and notification preferences; defaults are filled in for anything never saved. The ETag header is needed to update them. Sends 304 when If-None-Match matches.
+// @Tags         Users
+// @Produce      json
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client already has"
+// @Success      200  {object}  PreferencesResponse
+// @Success      304
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/preferences [get]
+func (h *UserHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	prefs, appErr := h.Service.GetPreferences(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" && utils.ETagMatches(inm, preferencesETag(prefs), true) {
+		w.Header().Set("ETag", preferencesETag(prefs))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	respondWithPreferences(w, prefs)
+}
+
+// PatchPreferences updates the authenticated user's preferences.
+// @Summary      Update preferences
+// @Description  Applies a JSON merge patch (RFC 7396) to the preferences: nested objects are merged, null restores a member's default. If-Match must carry the ETag from the last read so changes from other devices aren't overwritten; a stale ETag gets 412 and the client should read again. Lossless quality needs a premium plan.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        If-Match  header    string                   true  "ETag of the preferences the patch is based on"
+// @Param        request   body      PreferencesResponse  true  "Merge patch; any subset of the document"
+// @Success      200  {object}  PreferencesResponse
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      403  {object}  utils.ErrorResponse  "Value not available on the user's plan"
+// @Failure      412  {object}  utils.ErrorResponse  "Preferences changed since they were read"
+// @Failure      415  {object}  utils.ErrorResponse
+// @Failure      428  {object}  utils.ErrorResponse  "If-Match is missing"
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/preferences [patch]
+func (h *UserHandler) PatchPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		utils.RespondWithError(w, http.StatusPreconditionRequired, "If-Match header is required")
+		return
+	}
+
+	doc, err := patch.Read(r)
+	if err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid merge patch", err)
+		return
+	}
+
+	current, appErr := h.Service.GetPreferences(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+	if !utils.ETagMatches(ifMatch, preferencesETag(current), false) {
+		utils.RespondWithError(w, http.StatusPreconditionFailed, "Preferences were changed by another device; fetch them again and retry")
+		return
+	}
+
+	prefs, appErr := h.Service.PatchPreferences(ctx, userID, &current.Revision, doc)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Preferences updated", "user_id", userID, "revision", prefs.Revision)
+	respondWithPreferences(w, prefs)
+}
+
+// GetPreferencesSchema returns the JSON schema of the preferences document.
+// @Summary      Get preferences schema
+// @Description  JSON schema (draft 2020-12) of the document returned by GET /users/me/preferences, with defaults and allowed values.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/preferences/schema [get]
+func (h *UserHandler) GetPreferencesSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", preferences.SchemaContentType)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(preferences.Schema)
+}
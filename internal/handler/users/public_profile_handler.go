@@ -0,0 +1,280 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// PrivacySettingsResponse holds the visibility of each profile field: public, followers or private
+type PrivacySettingsResponse struct {
+	Profile         database.PrivacyLevel `json:"profile"`
+	DisplayName     database.PrivacyLevel `json:"display_name"`
+	Avatar          database.PrivacyLevel `json:"avatar"`
+	Bio             database.PrivacyLevel `json:"bio"`
+	Country         database.PrivacyLevel `json:"country"`
+	Playlists       database.PrivacyLevel `json:"playlists"`
+	FollowedArtists database.PrivacyLevel `json:"followed_artists"`
+	ListeningStats  database.PrivacyLevel `json:"listening_stats"`
+	// RequireFollowApproval holds new followers as requests until approved
+	RequireFollowApproval bool `json:"require_follow_approval"`
+}
+
+// PrivacySettingsRequest documents the merge patch accepted by PATCH /users/me/privacy
+type PrivacySettingsRequest struct {
+	Profile               *string `json:"profile,omitempty" enums:"public,followers,private"`
+	DisplayName           *string `json:"display_name,omitempty" enums:"public,followers,private"`
+	Avatar                *string `json:"avatar,omitempty" enums:"public,followers,private"`
+	Bio                   *string `json:"bio,omitempty" enums:"public,followers,private"`
+	Country               *string `json:"country,omitempty" enums:"public,followers,private"`
+	Playlists             *string `json:"playlists,omitempty" enums:"public,followers,private"`
+	FollowedArtists       *string `json:"followed_artists,omitempty" enums:"public,followers,private"`
+	ListeningStats        *string `json:"listening_stats,omitempty" enums:"public,followers,private"`
+	RequireFollowApproval *bool   `json:"require_follow_approval,omitempty"`
+}
+
+type PublicPlaylistResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	ItemCount int64     `json:"item_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FollowedArtistResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AvatarUrl string    `json:"avatar_url,omitempty"`
+	Verified  bool      `json:"verified"`
+}
+
+type TopArtistResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	StreamCount int64     `json:"stream_count"`
+}
+
+type ListeningStatsResponse struct {
+	StreamCount      int64               `json:"stream_count"`
+	DistinctMedia    int64               `json:"distinct_media"`
+	ListeningSeconds int64               `json:"listening_seconds"`
+	TopArtists       []TopArtistResponse `json:"top_artists"`
+}
+
+// PublicProfileResponse is what others see of a user. Fields hidden by the
+// owner's privacy settings are left out.
+type PublicProfileResponse struct {
+	ID              uuid.UUID                `json:"id"`
+	Username        string                   `json:"username"`
+	DisplayName     *string                  `json:"display_name,omitempty"`
+	AvatarUrl       *string                  `json:"avatar_url,omitempty"`
+	Bio             *string                  `json:"bio,omitempty"`
+	Country         *string                  `json:"country,omitempty"`
+	Playlists       []PublicPlaylistResponse `json:"playlists,omitempty"`
+	FollowedArtists []FollowedArtistResponse `json:"followed_artists,omitempty"`
+	ListeningStats  *ListeningStatsResponse  `json:"listening_stats,omitempty"`
+	FollowerCount   int64                    `json:"follower_count"`
+	FollowingCount  int64                    `json:"following_count"`
+	// FollowStatus is the caller's follow of this user, absent when not following
+	FollowStatus database.FollowStatus `json:"follow_status,omitempty" enums:"pending,accepted"`
+}
+
+// GetPublicProfile returns a user's public profile.
+// @Summary      Get public profile
+// @Description  Public. Returns the parts of a user's profile the caller is allowed to see; anonymous callers only see public fields. Hidden fields are omitted. A recently changed username answers with 307 and a Location header pointing to the profile under the current one. Private, deleted and locked profiles, and profiles of blocked or blocking users, return 404 under old and current names alike.
+// @Tags         Profiles
+// @Produce      json
+// @Param        username  path      string  true  "Username"
+// @Success      200       {object}  PublicProfileResponse
+// @Success      307       {object}  map[string]string
+// @Failure      400       {object}  utils.ErrorResponse
+// @Failure      401       {object}  utils.ErrorResponse
+// @Failure      404       {object}  utils.ErrorResponse
+// @Failure      500       {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/profiles/{username} [get]
+func (h *UserHandler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Anonymous visitors are strangers and only see public fields
+	viewerID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		viewerID = uuid.Nil
+	}
+
+	username := strings.TrimSpace(utils.GetParam(r, "username"))
+	if username == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Username is required", nil)
+		return
+	}
+
+	profile, redirected, appErr := h.Service.LookupPublicProfile(ctx, username, viewerID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	if redirected {
+		respondProfileRedirect(w, profile.Username)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapPublicProfile(profile))
+}
+
+// GetPrivacySettings returns the authenticated user's privacy settings.
+// @Summary      Get privacy settings
+// @Description  Returns who can see each part of the current user's public profile.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  PrivacySettingsResponse
+// @Failure      401  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/privacy [get]
+func (h *UserHandler) GetPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	settings, appErr := h.Service.GetPrivacySettings(ctx, userID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapPrivacySettings(settings))
+}
+
+// PatchPrivacySettings applies a JSON merge patch to the authenticated user's privacy settings.
+// @Summary      Patch privacy settings
+// @Description  RFC 7396 merge patch of the current user's privacy settings: absent fields are left unchanged and null restores the default. Turning require_follow_approval off accepts all pending follow requests.
+// @Tags         Users
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        settings  body      PrivacySettingsRequest  true  "Levels to change; null restores the default"
+// @Success      200       {object}  PrivacySettingsResponse
+// @Failure      400       {object}  utils.ErrorResponse
+// @Failure      401       {object}  utils.ErrorResponse
+// @Failure      415       {object}  utils.ErrorResponse
+// @Failure      500       {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/privacy [patch]
+func (h *UserHandler) PatchPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req service.PrivacyPatch
+	if err := patch.Decode(r, &req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid merge patch", err)
+		return
+	}
+
+	settings, appErr := h.Service.PatchPrivacySettings(ctx, userID, req)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Privacy settings updated", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusOK, mapPrivacySettings(settings))
+}
+
+// respondProfileRedirect points a lookup under an old username to the canonical profile URL
+func respondProfileRedirect(w http.ResponseWriter, username string) {
+	location := "/api/v1/profiles/" + url.PathEscape(username)
+	w.Header().Set("Location", location)
+	utils.RespondWithJSON(w, http.StatusTemporaryRedirect, map[string]string{
+		"username": username,
+		"location": location,
+	})
+}
+
+func mapPrivacySettings(s database.UserPrivacySetting) PrivacySettingsResponse {
+	return PrivacySettingsResponse{
+		Profile:         s.Profile,
+		DisplayName:     s.DisplayName,
+		Avatar:          s.Avatar,
+		Bio:             s.Bio,
+		Country:         s.Country,
+		Playlists:       s.Playlists,
+		FollowedArtists: s.FollowedArtists,
+		ListeningStats:  s.ListeningStats,
+
+		RequireFollowApproval: s.RequireFollowApproval,
+	}
+}
+
+func mapPublicProfile(p service.PublicProfile) PublicProfileResponse {
+	resp := PublicProfileResponse{
+		ID:          p.UserID,
+		Username:    p.Username,
+		DisplayName: p.DisplayName,
+		AvatarUrl:   p.AvatarUrl,
+		Bio:         p.Bio,
+		Country:     p.Country,
+
+		FollowerCount:  p.FollowerCount,
+		FollowingCount: p.FollowingCount,
+		FollowStatus:   p.FollowStatus,
+	}
+	for _, pl := range p.Playlists {
+		resp.Playlists = append(resp.Playlists, PublicPlaylistResponse{
+			ID:        pl.ID,
+			Name:      pl.Name,
+			ItemCount: pl.ItemCount,
+			CreatedAt: pl.CreatedAt,
+		})
+	}
+	for _, a := range p.FollowedArtists {
+		resp.FollowedArtists = append(resp.FollowedArtists, FollowedArtistResponse{
+			ID:        a.ID,
+			Name:      a.Name,
+			AvatarUrl: a.AvatarUrl.String,
+			Verified:  a.Verified,
+		})
+	}
+	if p.Stats != nil {
+		stats := &ListeningStatsResponse{
+			StreamCount:      p.Stats.StreamCount,
+			DistinctMedia:    p.Stats.DistinctMedia,
+			ListeningSeconds: p.Stats.ListeningSeconds,
+			TopArtists:       []TopArtistResponse{},
+		}
+		for _, a := range p.Stats.TopArtists {
+			stats.TopArtists = append(stats.TopArtists, TopArtistResponse{
+				ID:          a.ID,
+				Name:        a.Name,
+				StreamCount: a.StreamCount,
+			})
+		}
+		resp.ListeningStats = stats
+	}
+	return resp
+}
@@ -0,0 +1,192 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// SuspendUserRequest represents a suspension payload
+type SuspendUserRequest struct {
+	ReasonCode string     `json:"reason_code" example:"spam"`
+	Note       string     `json:"note,omitempty"`
+	Message    string     `json:"message,omitempty" example:"Repeated spam in comments"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// SuspensionResponse describes a suspension as seen by admins
+type SuspensionResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	ReasonCode  string     `json:"reason_code"`
+	Note        string     `json:"note,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	SuspendedBy *uuid.UUID `json:"suspended_by,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LiftedAt    *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy    *uuid.UUID `json:"lifted_by,omitempty"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func mapSuspensionToResponse(s database.UserSuspension) SuspensionResponse {
+	resp := SuspensionResponse{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		ReasonCode: s.ReasonCode,
+		Note:       s.Note.String,
+		Message:    s.Message.String,
+		CreatedAt:  s.CreatedAt,
+		Active:     !s.LiftedAt.Valid && (!s.ExpiresAt.Valid || s.ExpiresAt.Time.After(time.Now())),
+	}
+	if s.SuspendedBy.Valid {
+		resp.SuspendedBy = &s.SuspendedBy.UUID
+	}
+	if s.ExpiresAt.Valid {
+		resp.ExpiresAt = &s.ExpiresAt.Time
+	}
+	if s.LiftedAt.Valid {
+		resp.LiftedAt = &s.LiftedAt.Time
+	}
+	if s.LiftedBy.Valid {
+		resp.LiftedBy = &s.LiftedBy.UUID
+	}
+	return resp
+}
+
+// SuspendUser suspends a user account.
+// @Summary      Suspend user
+// @Description  Locks an account and signs it out everywhere. reason_code is one of spam, abuse, harassment, fraud, copyright, impersonation, other. message is shown to the user on login, note is internal. Without expires_at the suspension lasts until lifted. Admin only.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string              true  "User ID (UUID)"
+// @Param        body  body      SuspendUserRequest  true  "Suspension details"
+// @Success      201   {object}  SuspensionResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      404   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/suspensions [post]
+func (h *UserHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uid, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	adminID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req SuspendUserRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	suspension, appErr := h.Service.SuspendUser(ctx, service.SuspendUserParams{
+		UserID:      uid,
+		SuspendedBy: adminID,
+		ReasonCode:  req.ReasonCode,
+		Note:        req.Note,
+		Message:     req.Message,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "User suspended", "user_id", uid, "suspended_by", adminID, "reason", req.ReasonCode)
+	utils.RespondWithJSON(w, http.StatusCreated, mapSuspensionToResponse(suspension))
+}
+
+// LiftSuspension ends a user's suspension.
+// @Summary      Lift suspension
+// @Description  Lifts every open suspension of a user and unlocks the account. Admin only.
+// @Tags         Users
+// @Produce      json
+// @Param        id   path      string  true  "User ID (UUID)"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  utils.ErrorResponse
+// @Failure      404  {object}  utils.ErrorResponse
+// @Failure      500  {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/suspensions [delete]
+func (h *UserHandler) LiftSuspension(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uid, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	adminID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	if appErr := h.Service.LiftSuspension(ctx, uid, adminID); appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "User suspension lifted", "user_id", uid, "lifted_by", adminID)
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Suspension lifted"})
+}
+
+// ListSuspensions returns a user's suspension history.
+// @Summary      List user suspensions
+// @Description  Returns the suspension history of a user, newest first. Admin only.
+// @Tags         Users
+// @Produce      json
+// @Param        id      path      string  true   "User ID (UUID)"
+// @Param        limit   query     int     false  "Max results per page (default 20, max 100)"
+// @Param        offset  query     int     false  "Offset for pagination (default 0)"
+// @Success      200     {array}   SuspensionResponse
+// @Failure      400     {object}  utils.ErrorResponse
+// @Failure      404     {object}  utils.ErrorResponse
+// @Failure      500     {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/{id}/suspensions [get]
+func (h *UserHandler) ListSuspensions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uid, msg := utils.ReadUUIDParam(r, "id")
+	if msg != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	limit := h.parseInt(r.URL.Query().Get("limit"), 20)
+	if limit > 100 {
+		limit = 100
+	}
+	offset := h.parseInt(r.URL.Query().Get("offset"), 0)
+
+	suspensions, appErr := h.Service.ListSuspensions(ctx, uid, int32(limit), int32(offset))
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	response := make([]SuspensionResponse, len(suspensions))
+	for i, s := range suspensions {
+		response[i] = mapSuspensionToResponse(s)
+	}
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
@@ -27,25 +27,27 @@ func MapUserListToResponse(dbUsers []database.User) []models.UserResponse {
 
 func MapUserToResponse(u database.User) models.UserResponse {
 	return models.UserResponse{
-		ID:          u.ID,
-		Username:    u.Username,
-		Email:       u.Email,
-		IsVerified:  u.IsVerified,
-		CreatedAt:   u.CreatedAt,
-		Status:      u.Status,
-		UpdatedAt:   u.UpdatedAt,
-		Bio:         u.Bio.String,
-		PhoneNumber: u.PhoneNumber.String,
-		FirstName:   u.FirstName.String,
-		LastName:    u.LastName.String,
-		AvatarUrl:   u.AvatarUrl.String,
+		ID:            u.ID,
+		Username:      u.Username,
+		Email:         u.Email,
+		IsVerified:    u.IsVerified,
+		CreatedAt:     u.CreatedAt,
+		Status:        u.Status,
+		UpdatedAt:     u.UpdatedAt,
+		Bio:           u.Bio.String,
+		PhoneNumber:   u.PhoneNumber.String,
+		PhoneVerified: u.PhoneVerifiedAt.Valid,
+		FirstName:     u.FirstName.String,
+		LastName:      u.LastName.String,
+		AvatarUrl:     u.AvatarUrl.String,
 	}
 }
 
 type UserListResponse struct {
-	Users   []models.UserResponse `json:"users"`
-	Total   int64                 `json:"total"`
-	Limit   int32                 `json:"limit"`
-	Offset  int32                 `json:"offset"`
-	HasMore bool                  `json:"has_more"`
+	Users []models.UserResponse `json:"users"`
+	// Total is only present when include_total=true was requested
+	Total      *int64 `json:"total,omitempty"`
+	Limit      int32  `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
 }
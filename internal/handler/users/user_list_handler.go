@@ -2,24 +2,33 @@ package users
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
 
+	"github.com/techies/streamify/internal/pagination"
 	"github.com/techies/streamify/internal/service"
 	"github.com/techies/streamify/internal/utils"
 )
 
-// UserList returns a paginated list of users with optional search.
+// UserList returns a page of users with optional search.
 // @Summary      List users
-// @Description  Get a paginated list of users. Supports search by username, email, and phone number.
+// @Description  Search and filter users with keyset pagination. Admin only. q matches username, email and full name (substring, ranked by similarity). Follow next_cursor or the Link rel="next" header for the next page; filters and sort must stay the same across pages.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
-// @Param        limit         query     int     false  "Max results per page (default 20, max 100)"
-// @Param        offset        query     int     false  "Offset for pagination (default 0)"
-// @Param        username      query     string  false  "Search by username (partial match)"
-// @Param        email         query     string  false  "Search by email (partial match)"
-// @Param        phone_number  query     string  false  "Search by phone number (partial match)"
+// @Param        limit          query     int     false  "Max results per page (default 20, max 100)"
+// @Param        cursor         query     string  false  "Cursor from the previous page"
+// @Param        include_total  query     bool    false  "Also return the exact number of matching users"
+// @Param        q              query     string  false  "Search username, email and name"
+// @Param        phone_number   query     string  false  "Search by phone number (partial match)"
+// @Param        role           query     string  false  "Filter by role"  Enums(user, customer, admin, owner)
+// @Param        status         query     string  false  "Filter by account status, e.g. active or deleted"
+// @Param        verified       query     bool    false  "Filter by email verification"
+// @Param        locked         query     bool    false  "Filter by locked accounts"
+// @Param        sort           query     string  false  "Sort order (default relevance with q, created_at otherwise)"  Enums(created_at, username, relevance)
 // @Success      200       {object}  UserListResponse
+// @Header       200       {string}  Link  "RFC 8288 links to the next and first pages"
+// @Failure      400       {object}  utils.ErrorResponse
 // @Failure      401       {object}  utils.ErrorResponse
 // @Failure      500       {object}  utils.ErrorResponse
 // @Security     BearerAuth
@@ -27,34 +36,69 @@ import (
 func (h *UserHandler) UserList(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	limit := h.parseInt(r.URL.Query().Get("limit"), 20)
-	if limit > 100 {
-		limit = 100
+	query := r.URL.Query()
+	params := service.ListUsersParams{
+		Query:       query.Get("q"),
+		PhoneNumber: query.Get("phone_number"),
+		Role:        query.Get("role"),
+		Status:      query.Get("status"),
+		Sort:        query.Get("sort"),
+	}
+	var err error
+	if params.Verified, err = parseOptionalBool(query.Get("verified")); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "verified must be true or false", err)
+		return
+	}
+	if params.Locked, err = parseOptionalBool(query.Get("locked")); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "locked must be true or false", err)
+		return
 	}
-	offset := h.parseInt(r.URL.Query().Get("offset"), 0)
 
-	result, appErr := h.Service.ListUsers(ctx, service.ListUsersParams{
-		Limit:       int32(limit),
-		Offset:      int32(offset),
-		Username:    r.URL.Query().Get("username"),
-		Email:       r.URL.Query().Get("email"),
-		PhoneNumber: r.URL.Query().Get("phone_number"),
-	})
+	// Cursors are bound to the filters and sort they were issued for
+	scopeQuery := url.Values{}
+	for _, key := range []string{"q", "phone_number", "role", "status", "verified", "locked", "sort"} {
+		scopeQuery.Set(key, query.Get(key))
+	}
+	scope := "users?" + scopeQuery.Encode()
+	params.Page, err = h.App.Cursors.FromRequest(r, scope, pagination.DefaultOptions)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
 
+	result, appErr := h.Service.ListUsers(ctx, params)
 	if appErr != nil {
 		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
 		return
 	}
 
+	var next string
+	if result.Next != nil {
+		next = h.App.Cursors.Encode(scope, *result.Next)
+	}
+	pagination.SetLinkHeader(w, r, next)
+
 	utils.RespondWithJSON(w, http.StatusOK, UserListResponse{
-		Users:   MapUserListToResponse(result.Users),
-		Total:   result.Total,
-		Limit:   int32(limit),
-		Offset:  int32(offset),
-		HasMore: int64(offset+limit) < result.Total,
+		Users:      MapUserListToResponse(result.Users),
+		Total:      result.Total,
+		Limit:      params.Page.Limit,
+		NextCursor: next,
+		HasMore:    next != "",
 	})
 }
 
+// parseOptionalBool returns nil for an empty value
+func parseOptionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
 // Helper to keep the main handler logic clean
 func (h *UserHandler) parseInt(value string, fallback int) int {
 	if value == "" {
@@ -1,6 +1,7 @@
 package users
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -8,6 +9,7 @@ import (
 	"github.com/techies/streamify/internal/logger"
 	"github.com/techies/streamify/internal/middleware"
 	"github.com/techies/streamify/internal/models"
+	"github.com/techies/streamify/internal/patch"
 	"github.com/techies/streamify/internal/service"
 	"github.com/techies/streamify/internal/utils"
 )
@@ -18,6 +20,10 @@ type UpdateProfileRequest struct {
 	Bio         *string `json:"bio"`
 	AvatarUrl   *string `json:"avatar_url"`
 	PhoneNumber *string `json:"phone_number"`
+	// BirthDate is YYYY-MM-DD and can only be set once
+	BirthDate      *string `json:"birth_date" example:"2000-01-31"`
+	Country        *string `json:"country" example:"DE"`
+	FilterExplicit *bool   `json:"filter_explicit"`
 }
 
 // UpdateProfile updates the authenticated user's profile information.
@@ -30,6 +36,7 @@ type UpdateProfileRequest struct {
 // @Success      200      {object}  models.UserResponse
 // @Failure      400      {object}  utils.ErrorResponse
 // @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
 // @Failure      500      {object}  utils.ErrorResponse
 // @Security     BearerAuth
 // @Router       /api/v1/users/{id} [put]
@@ -55,12 +62,15 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 
 	// 3. Service Call
 	user, appErr := h.Service.UpdateProfile(ctx, service.UpdateProfileParams{
-		UserID:      userID,
-		FirstName:   req.FirstName,
-		LastName:    req.LastName,
-		Bio:         req.Bio,
-		AvatarUrl:   req.AvatarUrl,
-		PhoneNumber: req.PhoneNumber,
+		UserID:         userID,
+		FirstName:      req.FirstName,
+		LastName:       req.LastName,
+		Bio:            req.Bio,
+		AvatarUrl:      req.AvatarUrl,
+		PhoneNumber:    req.PhoneNumber,
+		BirthDate:      req.BirthDate,
+		Country:        req.Country,
+		FilterExplicit: req.FilterExplicit,
 	})
 
 	if appErr != nil {
@@ -121,3 +131,52 @@ func (h *UserHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
 		"message": "User role updated successfully",
 	})
 }
+
+// PatchProfile applies a JSON merge patch to the authenticated user's profile.
+// @Summary      Patch own profile
+// @Description  RFC 7396 merge patch of the current user's profile: absent fields are left unchanged and null clears a field (birth_date can only be set once and never cleared). Validation errors list the offending fields in "fields".
+// @Tags         Users
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        profile  body      UpdateProfileRequest  true  "Fields to change; null clears"
+// @Success      200      {object}  models.UserResponse
+// @Failure      400      {object}  utils.ErrorResponse
+// @Failure      401      {object}  utils.ErrorResponse
+// @Failure      403      {object}  utils.ErrorResponse
+// @Failure      409      {object}  utils.ErrorResponse
+// @Failure      415      {object}  utils.ErrorResponse
+// @Failure      500      {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me [patch]
+func (h *UserHandler) PatchProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req service.ProfilePatch
+	if err := patch.Decode(r, &req); err != nil {
+		if errors.Is(err, patch.ErrUnsupportedMediaType) {
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid merge patch", err)
+		return
+	}
+
+	user, appErr := h.Service.PatchProfile(ctx, userID, req)
+	if appErr != nil {
+		if appErr.Fields != nil {
+			utils.RespondWithFieldErrors(w, appErr.Code, appErr.Message, appErr.Fields)
+			return
+		}
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Profile patched", "user_id", userID)
+	utils.RespondWithJSON(w, http.StatusOK, models.UserResponse(MapUserToResponse(user)))
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/models"
+	"github.com/techies/streamify/internal/service"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ChangeUsernameRequest represents a username change payload
+type ChangeUsernameRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=30"`
+}
+
+// ChangeUsername changes the authenticated user's username.
+// @Summary      Change username
+// @Description  Changes the current user's username. Limited by a cooldown; the old username keeps redirecting to the user for a while and can't be claimed by others meanwhile.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      ChangeUsernameRequest  true  "New username"
+// @Success      200   {object}  models.UserResponse
+// @Failure      400   {object}  utils.ErrorResponse
+// @Failure      401   {object}  utils.ErrorResponse
+// @Failure      409   {object}  utils.ErrorResponse
+// @Failure      429   {object}  utils.ErrorResponse
+// @Failure      500   {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/me/username [put]
+func (h *UserHandler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req ChangeUsernameRequest
+	if err := utils.ParseJSON(w, r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid JSON format", err)
+		return
+	}
+
+	user, appErr := h.Service.ChangeUsername(ctx, service.ChangeUsernameParams{
+		UserID:   userID,
+		Username: req.Username,
+	})
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	logger.Info(ctx, "Username changed", "user_id", userID, "username", user.Username)
+	utils.RespondWithJSON(w, http.StatusOK, models.UserResponse(MapUserToResponse(user)))
+}
+
+// GetUserByUsername returns a user's public profile by username, case-insensitively.
+// @Summary      Get user by username
+// @Description  Looks up a user by username and returns the same public profile as GET /profiles/{username}. A recently changed username answers with 307 and a Location header pointing to the profile under the current one. Private, deleted and locked profiles, and profiles of blocked or blocking users, return 404 under old and current names alike.
+// @Tags         Users
+// @Produce      json
+// @Param        username  path      string  true  "Username"
+// @Success      200       {object}  PublicProfileResponse
+// @Success      307       {object}  map[string]string
+// @Failure      401       {object}  utils.ErrorResponse
+// @Failure      404       {object}  utils.ErrorResponse
+// @Failure      500       {object}  utils.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/v1/users/username/{username} [get]
+func (h *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	viewerID, err := middleware.GetUserUUID(ctx)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	username := utils.GetParam(r, "username")
+
+	profile, redirected, appErr := h.Service.LookupPublicProfile(ctx, username, viewerID)
+	if appErr != nil {
+		utils.RespondWithError(w, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	if redirected {
+		respondProfileRedirect(w, profile.Username)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, mapPublicProfile(profile))
+}
@@ -0,0 +1,199 @@
+// Package imaging turns uploaded images into fixed-size square JPEG
+// renditions. Decoding and re-encoding drops all metadata, EXIF included,
+// after the EXIF orientation has been applied to the pixels.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"math"
+	"net/http"
+
+	// Registered decoders for the accepted formats
+	_ "image/gif"
+	_ "image/png"
+)
+
+// RenditionSizes are the edge lengths in pixels of the square renditions
+var RenditionSizes = []int{64, 256, 640}
+
+// jpegQuality balances size and artefacts for photos and artwork
+const jpegQuality = 85
+
+var (
+	// ErrUnsupportedFormat is returned for files that aren't JPEG, PNG or GIF images
+	ErrUnsupportedFormat = errors.New("image must be a JPEG, PNG or GIF")
+	// ErrMalformed is returned for images that can't be decoded
+	ErrMalformed = errors.New("image is corrupt or truncated")
+	// ErrTooManyPixels is returned for images whose dimensions exceed Limits.MaxPixels
+	ErrTooManyPixels = errors.New("image dimensions are too large")
+)
+
+// Limits bounds what uploads are accepted, independently of the JSON body limit
+type Limits struct {
+	// MaxBytes is the largest accepted file
+	MaxBytes int64
+	// MaxPixels is the largest accepted width*height, which guards against
+	// small files that decode into huge bitmaps
+	MaxPixels int
+}
+
+// DefaultLimits accept files up to 10MB and 4096x4096 pixels
+var DefaultLimits = Limits{MaxBytes: 10 << 20, MaxPixels: 4096 * 4096}
+
+// Rendition is an encoded square JPEG of Size x Size pixels
+type Rendition struct {
+	Size int
+	Data []byte
+}
+
+// ContentType is the media type of every rendition
+const ContentType = "image/jpeg"
+
+// Renditions validates data and produces one rendition per size, center-cropped to a square
+func Renditions(data []byte, limits Limits, sizes []int) ([]Rendition, error) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrMalformed
+	}
+	if limits.MaxPixels > 0 && cfg.Width*cfg.Height > limits.MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	// Flatten onto white, JPEG has no alpha channel
+	canvas := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, img.Bounds().Min, draw.Over)
+	canvas = orient(canvas, exifOrientation(data))
+
+	square := centerSquare(canvas.Bounds())
+	out := make([]Rendition, 0, len(sizes))
+	for _, size := range sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(canvas, square, size), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		out = append(out, Rendition{Size: size, Data: buf.Bytes()})
+	}
+	return out, nil
+}
+
+func centerSquare(b image.Rectangle) image.Rectangle {
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// resize scales the src region r to a size x size image with a triangle filter.
+// The filter widens when shrinking so every source pixel contributes.
+func resize(src *image.RGBA, r image.Rectangle, size int) *image.RGBA {
+	xw := filterWeights(r.Dx(), size)
+	yw := filterWeights(r.Dy(), size)
+
+	// Horizontal pass into a size x r.Dy() buffer of float channels
+	tmp := make([]float32, size*r.Dy()*4)
+	for y := 0; y < r.Dy(); y++ {
+		row := src.Pix[src.PixOffset(r.Min.X, r.Min.Y+y):]
+		for x, w := range xw {
+			var c [4]float32
+			for i, k := range w.k {
+				p := row[(w.start+i)*4:]
+				c[0] += k * float32(p[0])
+				c[1] += k * float32(p[1])
+				c[2] += k * float32(p[2])
+				c[3] += k * float32(p[3])
+			}
+			copy(tmp[(y*size+x)*4:], c[:])
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y, w := range yw {
+		for x := 0; x < size; x++ {
+			var c [4]float32
+			for i, k := range w.k {
+				p := tmp[((w.start+i)*size+x)*4:]
+				c[0] += k * p[0]
+				c[1] += k * p[1]
+				c[2] += k * p[2]
+				c[3] += k * p[3]
+			}
+			o := dst.PixOffset(x, y)
+			for ch := 0; ch < 4; ch++ {
+				dst.Pix[o+ch] = clamp8(c[ch])
+			}
+		}
+	}
+	return dst
+}
+
+type weights struct {
+	start int
+	k     []float32
+}
+
+func filterWeights(srcLen, dstLen int) []weights {
+	scale := float64(srcLen) / float64(dstLen)
+	support := max(scale, 1)
+
+	out := make([]weights, dstLen)
+	for i := range out {
+		center := (float64(i)+0.5)*scale - 0.5
+		lo := max(int(math.Floor(center-support))+1, 0)
+		hi := min(int(math.Ceil(center+support))-1, srcLen-1)
+		if lo > hi {
+			lo = hi
+		}
+
+		k := make([]float32, hi-lo+1)
+		var sum float32
+		for j := lo; j <= hi; j++ {
+			d := (float64(j) - center) / support
+			if d < 0 {
+				d = -d
+			}
+			if d < 1 {
+				k[j-lo] = float32(1 - d)
+				sum += k[j-lo]
+			}
+		}
+		if sum == 0 {
+			k[0], sum = 1, 1
+		}
+		for j := range k {
+			k[j] /= sum
+		}
+		out[i] = weights{start: lo, k: k}
+	}
+	return out
+}
+
+func clamp8(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
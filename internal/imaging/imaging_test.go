@@ -0,0 +1,100 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation inserts an APP1 Exif segment carrying the orientation tag after SOI
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	payload := append(append([]byte("Exif\x00\x00"), tiff...), entry...)
+	payload = append(payload, 0, 0, 0, 0)
+
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, seg...)
+	out = append(out, payload...)
+	return append(out, jpg[2:]...)
+}
+
+func TestRenditionsAreSquareJPEGs(t *testing.T) {
+	renditions, err := Renditions(encodePNG(t, 300, 200), DefaultLimits, RenditionSizes)
+	if err != nil {
+		t.Fatalf("Renditions: %v", err)
+	}
+	if len(renditions) != len(RenditionSizes) {
+		t.Fatalf("got %d renditions", len(renditions))
+	}
+	for i, r := range renditions {
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(r.Data))
+		if err != nil {
+			t.Fatalf("decode %d: %v", r.Size, err)
+		}
+		if format != "jpeg" || cfg.Width != RenditionSizes[i] || cfg.Height != RenditionSizes[i] {
+			t.Errorf("rendition %d is %s %dx%d", r.Size, format, cfg.Width, cfg.Height)
+		}
+	}
+}
+
+func TestRenditionsRejects(t *testing.T) {
+	cases := map[string]struct {
+		data   []byte
+		limits Limits
+		want   error
+	}{
+		"not an image": {[]byte("<html></html>"), DefaultLimits, ErrUnsupportedFormat},
+		"truncated":    {encodePNG(t, 50, 50)[:60], DefaultLimits, ErrMalformed},
+		"too large":    {encodePNG(t, 100, 100), Limits{MaxPixels: 99 * 99}, ErrTooManyPixels},
+	}
+	for name, tc := range cases {
+		if _, err := Renditions(tc.data, tc.limits, RenditionSizes); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestOrientationIsApplied(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := withOrientation(buf.Bytes(), 6)
+
+	if got := exifOrientation(data); got != 6 {
+		t.Fatalf("orientation = %d, want 6", got)
+	}
+	if b := orient(image.NewRGBA(image.Rect(0, 0, 40, 20)), 6).Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+		t.Errorf("rotated bounds = %v", b)
+	}
+	if _, err := Renditions(data, DefaultLimits, []int{16}); err != nil {
+		t.Errorf("Renditions: %v", err)
+	}
+}
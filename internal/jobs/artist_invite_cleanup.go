@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/robfig/cron/v3"
+	"github.com/techies/streamify/internal/app"
+)
+
+// StartArtistInviteCleanupJob schedules a daily job to delete expired artist member invites
+func StartArtistInviteCleanupJob(app *app.AppConfig) {
+	c := cron.New()
+	_, err := c.AddFunc("45 3 * * *", func() {
+		ctx := context.Background()
+		if err := app.DB.DeleteExpiredArtistMemberInvites(ctx); err != nil {
+			log.Printf("Artist invite cleanup job failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule artist invite cleanup job: %v", err)
+	}
+
+	c.Start()
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/robfig/cron/v3"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/service"
+)
+
+// StartBulkUserJobRunner polls for queued bulk user jobs and CSV imports and runs them
+func StartBulkUserJobRunner(app *app.AppConfig) {
+	users := service.NewUserService(app.DB, app)
+
+	// Jobs can outlast the interval; a run still in progress keeps working through the queue
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	_, err := c.AddFunc("@every 5s", func() {
+		if err := users.RunBulkUserJobs(context.Background()); err != nil {
+			log.Printf("Bulk user job runner failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule bulk user job runner: %v", err)
+	}
+
+	c.Start()
+}
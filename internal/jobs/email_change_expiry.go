@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/robfig/cron/v3"
+	"github.com/techies/streamify/internal/app"
+)
+
+// StartEmailChangeExpiryJob schedules an hourly job that marks stale email change requests as expired
+func StartEmailChangeExpiryJob(app *app.AppConfig) {
+	c := cron.New()
+	_, err := c.AddFunc("@hourly", func() {
+		ctx := context.Background()
+		if err := app.DB.ExpireEmailChangeRequests(ctx); err != nil {
+			log.Printf("Email change expiry job failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule email change expiry job: %v", err)
+	}
+
+	c.Start()
+}
@@ -6,4 +6,9 @@ import (
 
 func StartAllJobs(appCfg *app.AppConfig) {
 	StartUserCleanupJob(appCfg)
+	StartEmailChangeExpiryJob(appCfg)
+	StartPhoneVerificationCleanupJob(appCfg)
+	StartSuspensionExpiryJob(appCfg)
+	StartBulkUserJobRunner(appCfg)
+	StartArtistInviteCleanupJob(appCfg)
 }
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/robfig/cron/v3"
+	"github.com/techies/streamify/internal/app"
+)
+
+// StartPhoneVerificationCleanupJob schedules a daily job to delete stale phone verification codes
+func StartPhoneVerificationCleanupJob(app *app.AppConfig) {
+	c := cron.New()
+	_, err := c.AddFunc("30 3 * * *", func() {
+		ctx := context.Background()
+		if err := app.DB.DeleteExpiredPhoneVerifications(ctx); err != nil {
+			log.Printf("Phone verification cleanup job failed: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule phone verification cleanup job: %v", err)
+	}
+
+	c.Start()
+}
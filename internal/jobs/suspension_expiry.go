@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/robfig/cron/v3"
+	"github.com/techies/streamify/internal/app"
+)
+
+// StartSuspensionExpiryJob schedules a job that unlocks accounts whose timed suspensions have ended
+func StartSuspensionExpiryJob(app *app.AppConfig) {
+	c := cron.New()
+	_, err := c.AddFunc("*/5 * * * *", func() {
+		ctx := context.Background()
+		unlocked, err := app.DB.UnlockUsersWithExpiredSuspensions(ctx)
+		if err != nil {
+			log.Printf("Suspension expiry job failed: %v", err)
+			return
+		}
+		if unlocked > 0 {
+			log.Printf("Suspension expiry job: unlocked %d accounts", unlocked)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule suspension expiry job: %v", err)
+	}
+
+	c.Start()
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ConsentRequiredCode is returned when the user has to accept new legal documents first
+const ConsentRequiredCode = "consent_required"
+
+// ConsentRequired blocks authenticated requests while the user hasn't accepted the
+// current mandatory terms or privacy policy. Paths starting with one of allowed stay
+// reachable so the user can review and accept the documents. Must run after AuthMiddleware.
+func ConsentRequired(db *database.Queries, allowed ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range allowed {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			userID, err := GetUserUUID(r.Context())
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			pending, err := db.ListPendingLegalDocuments(r.Context(), userID)
+			if err != nil {
+				logger.Error(r.Context(), "ConsentRequired: failed to check consents", err, "user_id", userID)
+				utils.RespondWithError(w, http.StatusInternalServerError, "Failed to check consents", err)
+				return
+			}
+			if len(pending) > 0 {
+				utils.RespondWithErrorCode(w, http.StatusForbidden, ConsentRequiredCode,
+					"Please accept the updated terms to continue")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
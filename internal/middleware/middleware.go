@@ -20,6 +20,8 @@ const (
 	UserEmailKey contextKey = "user_email"
 	UserRoleKey  contextKey = "user_role" // Added typed key for roles
 	SessionIDKey contextKey = "session_id"
+	ProfileIDKey contextKey = "profile_id"
+	ArtistIDKey  contextKey = "artist_id"
 )
 
 // GetUserID retrieves the user ID from context
@@ -28,12 +30,29 @@ func GetUserID(ctx context.Context) string {
 	return id
 }
 
+// GetUserUUID retrieves the user ID from context parsed as a UUID
+func GetUserUUID(ctx context.Context) (uuid.UUID, error) {
+	return uuid.Parse(GetUserID(ctx))
+}
+
 // GetSessionID retrieves the session ID from context
 func GetSessionID(ctx context.Context) string {
 	id, _ := ctx.Value(SessionIDKey).(string)
 	return id
 }
 
+// GetProfileID retrieves the selected listener profile from context, uuid.Nil when none is selected
+func GetProfileID(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(ProfileIDKey).(uuid.UUID)
+	return id
+}
+
+// GetArtistID retrieves the artist the token acts for, uuid.Nil outside an artist context
+func GetArtistID(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(ArtistIDKey).(uuid.UUID)
+	return id
+}
+
 // GetUserRole retrieves the user role from context
 func GetUserRole(ctx context.Context) string {
 	role, _ := ctx.Value(UserRoleKey).(string)
@@ -116,20 +135,62 @@ func AuthMiddleware(db *database.Queries, jwtSecret string) func(http.Handler) h
 			if role, ok := claims["role"].(string); ok {
 				ctx = context.WithValue(ctx, UserRoleKey, role)
 			}
+			if pid, ok := claims["pid"].(string); ok {
+				if profileID, err := uuid.Parse(pid); err == nil {
+					ctx = context.WithValue(ctx, ProfileIDKey, profileID)
+				}
+			}
+			if aid, ok := claims["aid"].(string); ok {
+				if artistID, err := uuid.Parse(aid); err == nil {
+					ctx = context.WithValue(ctx, ArtistIDKey, artistID)
+				}
+			}
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-// AdminOnly middleware restricts access to admin users
+// OptionalAuth lets anonymous requests through and authenticates the others like
+// AuthMiddleware, for public routes that show more to signed-in callers
+func OptionalAuth(db *database.Queries, jwtSecret string) func(http.Handler) http.Handler {
+	auth := AuthMiddleware(db, jwtSecret)
+	return func(next http.Handler) http.Handler {
+		authenticated := auth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsAdmin reports whether the caller is an admin; the owner is an admin too
+func IsAdmin(ctx context.Context) bool {
+	role := GetUserRole(ctx)
+	return role == "admin" || role == "owner"
+}
+
+// AdminOnly middleware restricts access to admin users; the owner is an admin too
 func AdminOnly(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		role := GetUserRole(r.Context())
-		if role != "admin" {
+		if !IsAdmin(r.Context()) {
 			utils.RespondWithError(w, http.StatusForbidden, "Admin access required", nil)
 			return
 		}
 		next.ServeHTTP(w, r)
 	})
 }
+
+// OwnerOnly middleware restricts access to the owner
+func OwnerOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetUserRole(r.Context()) != "owner" {
+			utils.RespondWithError(w, http.StatusForbidden, "Owner access required", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,32 @@
+package otp
+
+import "testing"
+
+func TestGenerate_ReturnsNumericCode(t *testing.T) {
+	code, err := Generate(6)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if len(code) != 6 {
+		t.Fatalf("expected 6 digits, got %q", code)
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			t.Fatalf("expected numeric code, got %q", code)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	hash := Hash("secret", "123456")
+
+	if !Verify("secret", "123456", hash) {
+		t.Fatal("expected matching code to verify")
+	}
+	if Verify("secret", "654321", hash) {
+		t.Fatal("expected wrong code to fail")
+	}
+	if Verify("other-secret", "123456", hash) {
+		t.Fatal("expected code hashed with another secret to fail")
+	}
+}
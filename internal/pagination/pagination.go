@@ -0,0 +1,207 @@
+// Package pagination implements keyset pagination over (created_at, id) with
+// opaque signed cursors and RFC 8288 Link headers.
+//
+// List queries sort by "created_at DESC, id DESC", take the cursor values as
+// nullable arguments and fetch Params.FetchLimit rows:
+//
+//	WHERE (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+//	   OR (created_at, id) < (sqlc.narg('cursor_created_at'), sqlc.narg('cursor_id')::uuid))
+//	ORDER BY created_at DESC, id DESC
+//	LIMIT $1
+//
+// Lists with other sort orders put their sort value in Cursor.Key and compare
+// (key, id) the same way.
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed, tampered with or from another list
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	headerLen = 8 + 16
+	macLen    = 16
+	maxKeyLen = 256
+)
+
+// Cursor is the sort key of the last row of a page
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+	// Key is the sort value for lists not ordered by created_at
+	Key string
+}
+
+// Codec signs cursors so clients can't forge positions or reuse them across lists
+type Codec struct {
+	key []byte
+}
+
+// NewCodec derives the signing key from secret
+func NewCodec(secret string) *Codec {
+	key := sha256.Sum256([]byte("pagination:" + secret))
+	return &Codec{key: key[:]}
+}
+
+// Encode returns the opaque cursor string. scope identifies the list and its
+// filters; a cursor only decodes under the same scope.
+func (c *Codec) Encode(scope string, cur Cursor) string {
+	key := cur.Key
+	if len(key) > maxKeyLen {
+		key = key[:maxKeyLen]
+	}
+	buf := make([]byte, headerLen, headerLen+len(key)+macLen)
+	binary.BigEndian.PutUint64(buf, uint64(cur.CreatedAt.UnixMicro()))
+	copy(buf[8:], cur.ID[:])
+	buf = append(buf, key...)
+	buf = append(buf, c.sign(scope, buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Decode verifies and parses a cursor made by Encode
+func (c *Codec) Decode(scope, token string) (Cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) < headerLen+macLen || len(buf) > headerLen+maxKeyLen+macLen {
+		return Cursor{}, ErrInvalidCursor
+	}
+	payload := buf[:len(buf)-macLen]
+	if !hmac.Equal(buf[len(payload):], c.sign(scope, payload)) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cur Cursor
+	// Postgres keeps microseconds, so that's what round-trips
+	cur.CreatedAt = time.UnixMicro(int64(binary.BigEndian.Uint64(payload))).UTC()
+	copy(cur.ID[:], payload[8:headerLen])
+	cur.Key = string(payload[headerLen:])
+	return cur, nil
+}
+
+func (c *Codec) sign(scope string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(scope))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)[:macLen]
+}
+
+// Params is a page request
+type Params struct {
+	Limit int32
+	// After is the cursor of the previous page, nil for the first page
+	After *Cursor
+	// IncludeTotal asks for an exact count, which costs a second query
+	IncludeTotal bool
+}
+
+// Options bound the page size of a list
+type Options struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// DefaultOptions suit most list endpoints
+var DefaultOptions = Options{DefaultLimit: 20, MaxLimit: 100}
+
+// FromRequest reads the limit, cursor and include_total query parameters.
+// Only an invalid cursor is an error; bad limits fall back to the defaults.
+func (c *Codec) FromRequest(r *http.Request, scope string, opts Options) (Params, error) {
+	q := r.URL.Query()
+
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = opts.DefaultLimit
+	}
+	if limit > opts.MaxLimit {
+		limit = opts.MaxLimit
+	}
+
+	p := Params{Limit: int32(limit)}
+	p.IncludeTotal, _ = strconv.ParseBool(q.Get("include_total"))
+
+	if token := q.Get("cursor"); token != "" {
+		cur, err := c.Decode(scope, token)
+		if err != nil {
+			return Params{}, err
+		}
+		p.After = &cur
+	}
+	return p, nil
+}
+
+// FetchLimit is the row limit to query: one extra row tells whether there is a next page
+func (p Params) FetchLimit() int32 {
+	return p.Limit + 1
+}
+
+// CursorCreatedAt is the cursor_created_at query argument
+func (p Params) CursorCreatedAt() sql.NullTime {
+	if p.After == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: p.After.CreatedAt, Valid: true}
+}
+
+// CursorKey is the cursor_key query argument of lists sorted by Cursor.Key
+func (p Params) CursorKey() sql.NullString {
+	if p.After == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: p.After.Key, Valid: true}
+}
+
+// CursorID is the cursor_id query argument
+func (p Params) CursorID() uuid.NullUUID {
+	if p.After == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: p.After.ID, Valid: true}
+}
+
+// Trim drops the extra row fetched by FetchLimit and reports whether more rows follow
+func Trim[T any](rows []T, p Params) ([]T, bool) {
+	if len(rows) > int(p.Limit) {
+		return rows[:p.Limit], true
+	}
+	return rows, false
+}
+
+// SetLinkHeader adds RFC 8288 links for the next page (when next is set) and
+// back to the first page (when r asked for a later one). Other query
+// parameters are kept so filters carry over.
+func SetLinkHeader(w http.ResponseWriter, r *http.Request, next string) {
+	var links []string
+	if next != "" {
+		links = append(links, link(r, next, "next"))
+	}
+	if r.URL.Query().Get("cursor") != "" {
+		links = append(links, link(r, "", "first"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func link(r *http.Request, cursor, rel string) string {
+	q := r.URL.Query()
+	q.Del("cursor")
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	u := *r.URL
+	u.RawQuery = q.Encode()
+	return "<" + u.RequestURI() + `>; rel="` + rel + `"`
+}
@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	codec := NewCodec("secret")
+	for _, cur := range []Cursor{
+		{CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC), ID: uuid.New()},
+		{ID: uuid.New(), Key: "alice"},
+	} {
+		got, err := codec.Decode("users", codec.Encode("users", cur))
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if got.CreatedAt.UnixMicro() != cur.CreatedAt.UnixMicro() || got.ID != cur.ID || got.Key != cur.Key {
+			t.Fatalf("got %+v, want %+v", got, cur)
+		}
+	}
+}
+
+func TestCursorRejected(t *testing.T) {
+	codec := NewCodec("secret")
+	token := codec.Encode("users", Cursor{CreatedAt: time.Now(), ID: uuid.New()})
+
+	tampered := []byte(token)
+	tampered[0] ^= 1
+
+	cases := map[string]struct {
+		codec *Codec
+		scope string
+		token string
+	}{
+		"other scope":  {codec, "invitations", token},
+		"other secret": {NewCodec("other"), "users", token},
+		"tampered":     {codec, "users", string(tampered)},
+		"garbage":      {codec, "users", "not-a-cursor"},
+	}
+	for name, tc := range cases {
+		if _, err := tc.codec.Decode(tc.scope, tc.token); err != ErrInvalidCursor {
+			t.Errorf("%s: got %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
+
+func TestFromRequestLimits(t *testing.T) {
+	codec := NewCodec("secret")
+	for query, want := range map[string]int32{"": 20, "limit=5": 5, "limit=500": 100, "limit=-1": 20} {
+		p, err := codec.FromRequest(httptest.NewRequest("GET", "/users?"+query, nil), "users", DefaultOptions)
+		if err != nil {
+			t.Fatalf("%q: %v", query, err)
+		}
+		if p.Limit != want {
+			t.Errorf("%q: limit %d, want %d", query, p.Limit, want)
+		}
+	}
+}
+
+func TestTrimAndLinks(t *testing.T) {
+	p := Params{Limit: 2}
+	rows, more := Trim([]int{1, 2, 3}, p)
+	if len(rows) != 2 || !more {
+		t.Fatalf("Trim = %v, %v", rows, more)
+	}
+
+	r := httptest.NewRequest("GET", "/api/v1/users?username=al&cursor=abc", nil)
+	w := httptest.NewRecorder()
+	SetLinkHeader(w, r, "def")
+	want := `</api/v1/users?cursor=def&username=al>; rel="next", </api/v1/users?username=al>; rel="first"`
+	if got := w.Header().Get("Link"); got != want {
+		t.Fatalf("Link = %s\nwant   %s", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHasher_Argon2id(t *testing.T) {
+	h, err := NewHasher("argon2id", testArgon2Params, 10)
+	if err != nil {
+		t.Fatalf("NewHasher returned error: %v", err)
+	}
+
+	hash, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("unexpected hash format %q", hash)
+	}
+
+	match, rehash, err := h.Verify("correct horse", hash)
+	if err != nil || !match || rehash {
+		t.Fatalf("expected match without rehash, got match=%v rehash=%v err=%v", match, rehash, err)
+	}
+	if match, _, _ := h.Verify("wrong horse", hash); match {
+		t.Fatal("expected wrong password to fail")
+	}
+
+	stronger := *h
+	stronger.Argon2.Iterations = 2
+	if _, rehash, _ := stronger.Verify("correct horse", hash); !rehash {
+		t.Fatal("expected hash with old parameters to need a rehash")
+	}
+}
+
+func TestHasher_BcryptNeedsRehash(t *testing.T) {
+	legacy, _ := NewHasher("bcrypt", testArgon2Params, 4)
+	hash, err := legacy.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+
+	h, _ := NewHasher("argon2id", testArgon2Params, 10)
+	match, rehash, err := h.Verify("correct horse", hash)
+	if err != nil || !match || !rehash {
+		t.Fatalf("expected bcrypt hash to match and need a rehash, got match=%v rehash=%v err=%v", match, rehash, err)
+	}
+
+	if _, _, err := h.Verify("correct horse", "plain"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	hashOf := func(pw string) string {
+		sum := sha1.Sum([]byte(pw))
+		return strings.ToUpper(hex.EncodeToString(sum[:]))
+	}
+
+	lines := []string{hashOf("hunter2hunter2") + ":42", hashOf("tr0ub4dor&3") + ":7"}
+	if lines[0] > lines[1] {
+		lines[0], lines[1] = lines[1], lines[0]
+	}
+	corpus := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(corpus, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPolicy("", corpus)
+	if err != nil {
+		t.Fatalf("NewPolicy returned error: %v", err)
+	}
+
+	if err := p.Check("Password123"); !errors.Is(err, ErrCommonPassword) {
+		t.Fatalf("expected ErrCommonPassword, got %v", err)
+	}
+	if err := p.Check("hunter2hunter2"); !errors.Is(err, ErrBreachedPassword) {
+		t.Fatalf("expected ErrBreachedPassword, got %v", err)
+	}
+	if err := p.Check("a perfectly fine passphrase"); err != nil {
+		t.Fatalf("expected password to pass, got %v", err)
+	}
+}
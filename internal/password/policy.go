@@ -0,0 +1,195 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCommonPassword   = errors.New("password is too common")
+	ErrBreachedPassword = errors.New("password has appeared in a data breach")
+)
+
+// defaultCommonPasswords are always rejected; extend with a common-password file
+var defaultCommonPasswords = []string{
+	"password", "password1", "password123", "12345678", "123456789", "1234567890",
+	"qwerty123", "qwertyuiop", "iloveyou", "11111111", "00000000", "abc12345",
+	"letmein1", "welcome1", "sunshine", "football", "baseball", "superman",
+	"trustno1", "streamify",
+}
+
+// Policy rejects passwords that are known to attackers: common passwords and
+// passwords found in a breach corpus.
+type Policy struct {
+	common   map[string]struct{}
+	breached *breachCorpus
+}
+
+// NewPolicy builds a policy. commonFile is an optional newline-separated list of
+// passwords, breachedFile an optional breach corpus, see loadBreachCorpus.
+func NewPolicy(commonFile, breachedFile string) (*Policy, error) {
+	p := &Policy{common: map[string]struct{}{}}
+	for _, pw := range defaultCommonPasswords {
+		p.common[pw] = struct{}{}
+	}
+
+	if commonFile != "" {
+		if err := p.loadCommon(commonFile); err != nil {
+			return nil, fmt.Errorf("load common passwords: %w", err)
+		}
+	}
+
+	if breachedFile != "" {
+		corpus, err := loadBreachCorpus(breachedFile)
+		if err != nil {
+			return nil, fmt.Errorf("load breached passwords: %w", err)
+		}
+		p.breached = corpus
+	}
+
+	return p, nil
+}
+
+// Check returns ErrCommonPassword or ErrBreachedPassword when password must not be used
+func (p *Policy) Check(password string) error {
+	if _, ok := p.common[strings.ToLower(password)]; ok {
+		return ErrCommonPassword
+	}
+
+	if p.breached != nil {
+		count, err := p.breached.lookup(password)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrBreachedPassword
+		}
+	}
+	return nil
+}
+
+func (p *Policy) loadCommon(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.common[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+const (
+	prefixLen   = 5
+	prefixCount = 1 << 20 // 16^prefixLen
+)
+
+// breachCorpus looks passwords up in a local copy of a k-anonymity breach corpus:
+// a file of "SHA1:COUNT" lines sorted by hash, as produced by the Have I Been
+// Pwned downloader. Only the hash range sharing the password's 5 character
+// prefix is read for a lookup, the file itself stays on disk.
+type breachCorpus struct {
+	file *os.File
+	// offsets[i] is where the range for prefix i starts; offsets[prefixCount] is the file size
+	offsets []int64
+}
+
+func loadBreachCorpus(path string) (*breachCorpus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := make([]int64, prefixCount+1)
+	for i := range starts {
+		starts[i] = -1
+	}
+
+	r := bufio.NewReader(f)
+	var offset int64
+	last := -1
+	for {
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			if len(line) < prefixLen {
+				f.Close()
+				return nil, fmt.Errorf("malformed line at offset %d", offset)
+			}
+			prefix, perr := strconv.ParseUint(line[:prefixLen], 16, 32)
+			if perr != nil {
+				f.Close()
+				return nil, fmt.Errorf("malformed line at offset %d", offset)
+			}
+			if int(prefix) < last {
+				f.Close()
+				return nil, errors.New("corpus must be sorted by hash")
+			}
+			if int(prefix) != last {
+				starts[prefix] = offset
+				last = int(prefix)
+			}
+			offset += int64(len(line))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	// Prefixes without hashes get an empty range
+	starts[prefixCount] = offset
+	for i := prefixCount - 1; i >= 0; i-- {
+		if starts[i] < 0 {
+			starts[i] = starts[i+1]
+		}
+	}
+
+	return &breachCorpus{file: f, offsets: starts}, nil
+}
+
+// lookup returns how often password appears in the corpus
+func (c *breachCorpus) lookup(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	prefix, _ := strconv.ParseUint(hash[:prefixLen], 16, 32)
+	start, end := c.offsets[prefix], c.offsets[prefix+1]
+	if start == end {
+		return 0, nil
+	}
+
+	buf := make([]byte, end-start)
+	if _, err := c.file.ReadAt(buf, start); err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(buf), "\n") {
+		h, count, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(h, hash) {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+	return 0, nil
+}
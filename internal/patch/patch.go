@@ -0,0 +1,127 @@
+// Package patch decodes JSON Merge Patch (RFC 7396) documents, where an
+// absent member leaves a field untouched and an explicit null clears it.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// ContentType is the media type of merge patch documents
+const ContentType = "application/merge-patch+json"
+
+// maxBodySize bounds patch documents
+const maxBodySize = 1 << 20
+
+var (
+	// ErrUnsupportedMediaType is returned for bodies that aren't merge patch or plain JSON
+	ErrUnsupportedMediaType = errors.New("content type must be " + ContentType + " or application/json")
+	// ErrNotObject is returned when the patch document isn't a JSON object
+	ErrNotObject = errors.New("patch document must be a JSON object")
+)
+
+// Field is one member of a merge patch: absent, null or a value
+type Field[T any] struct {
+	// Set is true when the member was present in the document, including as null
+	Set bool
+	// Null is true when the member was an explicit null
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only called for members present in the document
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Null = true
+		var zero T
+		f.Value = zero
+		return nil
+	}
+	f.Null = false
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Present reports whether the member carries a value
+func (f Field[T]) Present() bool {
+	return f.Set && !f.Null
+}
+
+// Ptr returns the new value, nil when the member clears the field.
+// Only meaningful when Set is true.
+func (f Field[T]) Ptr() *T {
+	if f.Null {
+		return nil
+	}
+	v := f.Value
+	return &v
+}
+
+// Decode reads a merge patch document from r into dst, a struct of Fields.
+// Unknown members are rejected so typos don't silently do nothing.
+func Decode(r *http.Request, dst any) error {
+	body, err := Read(r)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
+
+// Read returns the raw merge patch document of r, for patches applied with Merge
+func Read(r *http.Request) ([]byte, error) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != ContentType && mediaType != "application/json") {
+			return nil, ErrUnsupportedMediaType
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil, ErrNotObject
+	}
+	return body, nil
+}
+
+// Merge applies a merge patch to a JSON document. Nested objects are merged
+// member by member, null removes a member and any other value replaces it.
+func Merge(target, patch []byte) ([]byte, error) {
+	var doc, p any
+	if err := json.Unmarshal(target, &doc); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(doc, p))
+}
+
+func mergeValue(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergeValue(t[k], v)
+	}
+	return t
+}
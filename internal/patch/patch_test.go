@@ -0,0 +1,77 @@
+package patch
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type profilePatch struct {
+	Bio       Field[string] `json:"bio"`
+	AvatarUrl Field[string] `json:"avatar_url"`
+	Private   Field[bool]   `json:"private"`
+}
+
+func decode(t *testing.T, body, contentType string) (profilePatch, error) {
+	t.Helper()
+	r := httptest.NewRequest("PATCH", "/users/me", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	var p profilePatch
+	err := Decode(r, &p)
+	return p, err
+}
+
+func TestDecodeAbsentNullAndValue(t *testing.T) {
+	p, err := decode(t, `{"bio": null, "private": true}`, ContentType)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !p.Bio.Set || !p.Bio.Null || p.Bio.Ptr() != nil {
+		t.Errorf("bio should be cleared: %+v", p.Bio)
+	}
+	if p.AvatarUrl.Set {
+		t.Errorf("avatar_url should be untouched: %+v", p.AvatarUrl)
+	}
+	if !p.Private.Present() || !p.Private.Value {
+		t.Errorf("private should be true: %+v", p.Private)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	cases := map[string]struct{ body, contentType string }{
+		"array":         {`[{"bio": "x"}]`, ContentType},
+		"unknown field": {`{"bios": "x"}`, ContentType},
+		"wrong type":    {`{"private": "yes"}`, ContentType},
+		"media type":    {`{"bio": "x"}`, "text/plain"},
+	}
+	for name, tc := range cases {
+		if _, err := decode(t, tc.body, tc.contentType); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	// Examples from RFC 7396, appendix A
+	cases := []struct{ target, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range cases {
+		got, err := Merge([]byte(tc.target), []byte(tc.patch))
+		if err != nil {
+			t.Fatalf("Merge(%s, %s): %v", tc.target, tc.patch, err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("Merge(%s, %s) = %s, want %s", tc.target, tc.patch, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package preferences defines the per-user settings document clients sync
+// across devices: language, playback and notification preferences.
+//
+// The document is versioned. Stored documents are decoded on top of the
+// defaults, so members added in later versions pick up their default value
+// and older documents keep working without a data migration.
+package preferences
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/techies/streamify/internal/patch"
+)
+
+// Version is the current version of the preferences document
+const Version = 1
+
+// Schema is the JSON schema of the current document version
+//
+//go:embed schema.json
+var Schema []byte
+
+// SchemaContentType is the media type Schema is served with
+const SchemaContentType = "application/schema+json"
+
+var (
+	// ErrUnsupportedVersion is returned for documents written by a newer version
+	ErrUnsupportedVersion = errors.New("unsupported preferences version")
+	// ErrReadOnlyVersion is returned when a patch tries to change the document version
+	ErrReadOnlyVersion = errors.New("version can't be changed")
+)
+
+var validate = validator.New()
+
+// Quality is an audio quality level, from lowest to highest
+type Quality string
+
+const (
+	QualityLow      Quality = "low"
+	QualityNormal   Quality = "normal"
+	QualityHigh     Quality = "high"
+	QualityLossless Quality = "lossless"
+)
+
+// Qualities lists the quality levels in the order of the schema enum
+var Qualities = []Quality{QualityLow, QualityNormal, QualityHigh, QualityLossless}
+
+// MaxCrossfadeSeconds bounds playback.crossfade_seconds
+const MaxCrossfadeSeconds = 12
+
+// Tier is what the user's plan entitles them to
+type Tier string
+
+const (
+	TierFree    Tier = "free"
+	TierPremium Tier = "premium"
+)
+
+type Preferences struct {
+	// Language is a BCP 47 tag for the app interface
+	Language      string        `json:"language"`
+	Playback      Playback      `json:"playback"`
+	Notifications Notifications `json:"notifications"`
+}
+
+type Playback struct {
+	StreamingQuality Quality `json:"streaming_quality"`
+	// CellularQuality applies instead of StreamingQuality on metered connections
+	CellularQuality  Quality `json:"cellular_quality"`
+	DownloadQuality  Quality `json:"download_quality"`
+	Autoplay         bool    `json:"autoplay"`
+	CrossfadeSeconds int     `json:"crossfade_seconds"`
+	Gapless          bool    `json:"gapless"`
+	NormalizeVolume  bool    `json:"normalize_volume"`
+}
+
+type Notifications struct {
+	NewReleases     bool `json:"new_releases"`
+	PlaylistUpdates bool `json:"playlist_updates"`
+	NewFollowers    bool `json:"new_followers"`
+	FollowRequests  bool `json:"follow_requests"`
+	ProductNews     bool `json:"product_news"`
+	// Email and Push switch whole channels off regardless of the topics above
+	Email bool `json:"email"`
+	Push  bool `json:"push"`
+}
+
+// Defaults are the preferences of users who never saved any
+func Defaults() Preferences {
+	return Preferences{
+		Language: "en",
+		Playback: Playback{
+			StreamingQuality: QualityHigh,
+			CellularQuality:  QualityNormal,
+			DownloadQuality:  QualityHigh,
+			Autoplay:         true,
+			CrossfadeSeconds: 0,
+			Gapless:          true,
+			NormalizeVolume:  true,
+		},
+		Notifications: Notifications{
+			NewReleases:     true,
+			PlaylistUpdates: true,
+			NewFollowers:    true,
+			FollowRequests:  true,
+			ProductNews:     false,
+			Email:           true,
+			Push:            true,
+		},
+	}
+}
+
+// Load decodes a stored document of the given version
+func Load(version int, doc []byte) (Preferences, error) {
+	if version > Version {
+		return Preferences{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	p := Defaults()
+	if err := json.Unmarshal(doc, &p); err != nil {
+		return Preferences{}, err
+	}
+	return p, nil
+}
+
+// Apply applies a JSON merge patch to p. Members set to null go back to their
+// default. A "version" member is accepted when it matches Version, so clients
+// can send back the document they read.
+func Apply(p Preferences, mergePatch []byte) (Preferences, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(mergePatch, &members); err != nil {
+		return Preferences{}, err
+	}
+	if v, ok := members["version"]; ok {
+		if strings.TrimSpace(string(v)) != fmt.Sprint(Version) {
+			return Preferences{}, ErrReadOnlyVersion
+		}
+		delete(members, "version")
+		var err error
+		if mergePatch, err = json.Marshal(members); err != nil {
+			return Preferences{}, err
+		}
+	}
+
+	current, err := json.Marshal(p)
+	if err != nil {
+		return Preferences{}, err
+	}
+	merged, err := patch.Merge(current, mergePatch)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	next := Defaults()
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&next); err != nil {
+		return Preferences{}, err
+	}
+	return next, nil
+}
+
+// Validate returns what is wrong with p, keyed by the member's path
+func Validate(p Preferences) map[string]string {
+	fields := map[string]string{}
+	if validate.Var(p.Language, "required,bcp47_language_tag") != nil {
+		fields["language"] = "must be a BCP 47 language tag"
+	}
+	quality := func(name string, q Quality) {
+		if !q.valid() {
+			fields[name] = "must be one of low, normal, high, lossless"
+		}
+	}
+	quality("playback.streaming_quality", p.Playback.StreamingQuality)
+	quality("playback.cellular_quality", p.Playback.CellularQuality)
+	quality("playback.download_quality", p.Playback.DownloadQuality)
+	if p.Playback.CrossfadeSeconds < 0 || p.Playback.CrossfadeSeconds > MaxCrossfadeSeconds {
+		fields["playback.crossfade_seconds"] = fmt.Sprintf("must be between 0 and %d", MaxCrossfadeSeconds)
+	}
+	return fields
+}
+
+// CheckTier returns the values of next the tier doesn't allow. Only values that
+// differ from prev are checked, so users who lose a plan can still change other
+// preferences.
+func CheckTier(prev, next Preferences, tier Tier) map[string]string {
+	fields := map[string]string{}
+	if tier == TierPremium {
+		return fields
+	}
+	quality := func(name string, old, q Quality) {
+		if q != old && q == QualityLossless {
+			fields[name] = "lossless quality requires a premium plan"
+		}
+	}
+	quality("playback.streaming_quality", prev.Playback.StreamingQuality, next.Playback.StreamingQuality)
+	quality("playback.cellular_quality", prev.Playback.CellularQuality, next.Playback.CellularQuality)
+	quality("playback.download_quality", prev.Playback.DownloadQuality, next.Playback.DownloadQuality)
+	return fields
+}
+
+func (q Quality) valid() bool {
+	for _, v := range Qualities {
+		if q == v {
+			return true
+		}
+	}
+	return false
+}
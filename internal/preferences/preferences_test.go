@@ -0,0 +1,124 @@
+package preferences
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type schemaNode struct {
+	Properties map[string]schemaNode `json:"properties"`
+	Default    any                   `json:"default"`
+	Const      any                   `json:"const"`
+}
+
+// The schema documents the defaults; both must list the same members and values
+func TestSchemaMatchesDefaults(t *testing.T) {
+	var schema schemaNode
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	if schema.Properties["version"].Const != float64(Version) {
+		t.Errorf("schema version = %v, want %d", schema.Properties["version"].Const, Version)
+	}
+
+	data, _ := json.Marshal(Defaults())
+	var defaults map[string]any
+	json.Unmarshal(data, &defaults)
+
+	var compare func(path string, node schemaNode, value any)
+	compare = func(path string, node schemaNode, value any) {
+		if obj, ok := value.(map[string]any); ok {
+			if len(obj) != len(node.Properties) {
+				t.Errorf("%s: %d members, schema has %d", path, len(obj), len(node.Properties))
+			}
+			for k, v := range obj {
+				child, ok := node.Properties[k]
+				if !ok {
+					t.Errorf("%s%s missing from schema", path, k)
+					continue
+				}
+				compare(path+k+".", child, v)
+			}
+			return
+		}
+		if node.Default != value {
+			t.Errorf("%s: schema default %v, want %v", path, node.Default, value)
+		}
+	}
+	delete(schema.Properties, "version")
+	compare("", schema, defaults)
+}
+
+func TestApply(t *testing.T) {
+	p := Defaults()
+	p.Playback.CrossfadeSeconds = 6
+
+	next, err := Apply(p, []byte(`{"version": 1, "language": "pt-BR", "playback": {"crossfade_seconds": null, "autoplay": false}}`))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if next.Language != "pt-BR" || next.Playback.Autoplay || next.Playback.CrossfadeSeconds != 0 {
+		t.Errorf("unexpected result: %+v", next)
+	}
+	if !next.Playback.Gapless || next.Playback.StreamingQuality != QualityHigh {
+		t.Errorf("untouched members changed: %+v", next.Playback)
+	}
+
+	if _, err := Apply(p, []byte(`{"playback": {"crossfade": 3}}`)); err == nil {
+		t.Error("unknown member should be rejected")
+	}
+	if _, err := Apply(p, []byte(`{"version": 2}`)); !errors.Is(err, ErrReadOnlyVersion) {
+		t.Errorf("version change: got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if fields := Validate(Defaults()); len(fields) != 0 {
+		t.Errorf("defaults are invalid: %v", fields)
+	}
+
+	p := Defaults()
+	p.Language = "not a tag"
+	p.Playback.StreamingQuality = "ultra"
+	p.Playback.CrossfadeSeconds = 13
+	fields := Validate(p)
+	for _, name := range []string{"language", "playback.streaming_quality", "playback.crossfade_seconds"} {
+		if fields[name] == "" {
+			t.Errorf("%s should be rejected: %v", name, fields)
+		}
+	}
+}
+
+func TestCheckTier(t *testing.T) {
+	prev := Defaults()
+	next := prev
+	next.Playback.DownloadQuality = QualityLossless
+
+	if fields := CheckTier(prev, next, TierFree); fields["playback.download_quality"] == "" {
+		t.Errorf("free users can't pick lossless: %v", fields)
+	}
+	if fields := CheckTier(prev, next, TierPremium); len(fields) != 0 {
+		t.Errorf("premium users can pick lossless: %v", fields)
+	}
+
+	// A lapsed plan leaves lossless in place until the user changes it
+	changed := next
+	changed.Language = "fr"
+	if fields := CheckTier(next, changed, TierFree); len(fields) != 0 {
+		t.Errorf("unchanged values shouldn't be checked: %v", fields)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load(1, []byte(`{"language": "de", "playback": {"autoplay": false}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if p.Language != "de" || p.Playback.Autoplay || !p.Notifications.Push {
+		t.Errorf("missing members should keep their defaults: %+v", p)
+	}
+	if _, err := Load(Version+1, []byte(`{}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("newer version: got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package registration
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Mode controls who may sign up
+type Mode string
+
+const (
+	ModeOpen       Mode = "open"
+	ModeInviteOnly Mode = "invite-only"
+	ModeClosed     Mode = "closed"
+)
+
+var (
+	ErrDomainNotAllowed = errors.New("email domain is not allowed")
+	ErrDisposableEmail  = errors.New("disposable email addresses are not allowed")
+)
+
+// Policy is the registration policy loaded at startup
+type Policy struct {
+	Mode Mode
+	// AllowedDomains restricts sign-ups to these email domains (and their subdomains) when not empty
+	AllowedDomains []string
+	// blockedDomains holds disposable email providers
+	blockedDomains map[string]struct{}
+}
+
+// NewPolicy builds a policy. disposableFile is an optional path to a newline-separated
+// list of disposable email domains; lines starting with '#' are ignored.
+func NewPolicy(mode string, allowedDomains []string, disposableFile string) (*Policy, error) {
+	p := &Policy{
+		Mode:           Mode(strings.ToLower(mode)),
+		blockedDomains: map[string]struct{}{},
+	}
+	if p.Mode == "" {
+		p.Mode = ModeOpen
+	}
+	switch p.Mode {
+	case ModeOpen, ModeInviteOnly, ModeClosed:
+	default:
+		return nil, fmt.Errorf("unknown registration mode %q", mode)
+	}
+
+	for _, d := range allowedDomains {
+		p.AllowedDomains = append(p.AllowedDomains, strings.ToLower(strings.TrimPrefix(d, "@")))
+	}
+
+	if disposableFile != "" {
+		if err := p.loadBlockedDomains(disposableFile); err != nil {
+			return nil, fmt.Errorf("load disposable email domains: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Policy) loadBlockedDomains(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.blockedDomains[line] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// InvitationRequired reports whether sign-ups need an invitation code
+func (p *Policy) InvitationRequired() bool {
+	return p.Mode == ModeInviteOnly
+}
+
+// CheckEmail validates the domain of a normalized email address
+func (p *Policy) CheckEmail(email string) error {
+	domain := email[strings.LastIndex(email, "@")+1:]
+
+	for d := domain; d != ""; d = parentDomain(d) {
+		if _, blocked := p.blockedDomains[d]; blocked {
+			return ErrDisposableEmail
+		}
+	}
+
+	if len(p.AllowedDomains) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowedDomains {
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return nil
+		}
+	}
+	return ErrDomainNotAllowed
+}
+
+// parentDomain strips the left-most label: "mail.example.com" -> "example.com"
+func parentDomain(domain string) string {
+	i := strings.Index(domain, ".")
+	if i < 0 {
+		return ""
+	}
+	return domain[i+1:]
+}
@@ -0,0 +1,49 @@
+package registration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy_CheckEmail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	if err := os.WriteFile(path, []byte("# disposable providers\nmailinator.com\n\ntempmail.io\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPolicy("invite-only", []string{"acme.com", "@example.org"}, path)
+	if err != nil {
+		t.Fatalf("NewPolicy returned error: %v", err)
+	}
+
+	tests := []struct {
+		email string
+		want  error
+	}{
+		{"jane@acme.com", nil},
+		{"jane@eu.acme.com", nil},
+		{"jane@example.org", nil},
+		{"jane@notacme.com", ErrDomainNotAllowed},
+		{"jane@gmail.com", ErrDomainNotAllowed},
+		{"jane@mailinator.com", ErrDisposableEmail},
+		{"jane@x.tempmail.io", ErrDisposableEmail},
+	}
+	for _, tt := range tests {
+		if err := p.CheckEmail(tt.email); !errors.Is(err, tt.want) {
+			t.Errorf("CheckEmail(%q) = %v, want %v", tt.email, err, tt.want)
+		}
+	}
+}
+
+func TestNewPolicy_RejectsUnknownMode(t *testing.T) {
+	if _, err := NewPolicy("waitlist", nil, ""); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+
+	p, err := NewPolicy("", nil, "")
+	if err != nil || p.Mode != ModeOpen {
+		t.Fatalf("expected empty mode to default to open, got %v, %v", p, err)
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/handler"
+	"github.com/techies/streamify/internal/middleware"
+)
+
+func albumRouter(h *handler.Handler, cfg *app.AppConfig) chi.Router {
+	r := chi.NewRouter()
+
+	// Album pages are public
+	r.Get("/", h.Album.ListAlbums)
+	r.Get("/{id}", h.Album.GetAlbum)
+
+	// Artist members in the artist's context, or admins
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(h.App.DB, cfg.JWTSecret))
+		r.Use(middleware.ConsentRequired(h.App.DB, "/api/v1/users/me/consents"))
+		r.Post("/", h.Album.CreateAlbum)
+		r.Patch("/{id}", h.Album.PatchAlbum)
+		r.Delete("/{id}", h.Album.DeleteAlbum)
+		r.Put("/{id}/tracks", h.Album.SetTracks)
+		r.Put("/{id}/cover", h.Album.UploadCover)
+	})
+
+	return r
+}
@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/handler"
+	"github.com/techies/streamify/internal/middleware"
+)
+
+func artistRouter(h *handler.Handler, cfg *app.AppConfig) chi.Router {
+	r := chi.NewRouter()
+
+	// Artist pages are public; signed-in callers also see whether they follow the artist
+	r.Get("/", h.Artist.ListArtists)
+	r.With(middleware.OptionalAuth(h.App.DB, cfg.JWTSecret)).Get("/{id}", h.Artist.GetArtist)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(h.App.DB, cfg.JWTSecret))
+		r.Use(middleware.ConsentRequired(h.App.DB, "/api/v1/users/me/consents"))
+
+		r.Get("/mine", h.Artist.ListMyArtists)
+		r.Delete("/context", h.Artist.ClearArtistContext)
+		r.Get("/invites", h.Artist.ListMyInvites)
+		r.Post("/invites/{inviteID}/accept", h.Artist.AcceptInvite)
+		r.Delete("/invites/{inviteID}", h.Artist.DeclineInvite)
+		r.Get("/verification-requests/{requestID}", h.Artist.GetVerificationRequest)
+		r.Post("/verification-requests/{requestID}/status", h.Artist.TransitionVerification)
+		r.Post("/verification-requests/{requestID}/comments", h.Artist.CommentOnVerification)
+
+		r.Post("/{id}/follow", h.Artist.FollowArtist)
+		r.Delete("/{id}/follow", h.Artist.UnfollowArtist)
+		r.Post("/{id}/claims", h.Artist.ClaimArtist)
+		r.Post("/{id}/context", h.Artist.SelectArtistContext)
+
+		// Artist members in the artist's context, or admins
+		r.Patch("/{id}", h.Artist.PatchArtist)
+		r.Put("/{id}/avatar", h.Artist.UploadAvatar)
+		r.Delete("/{id}/avatar", h.Artist.DeleteAvatar)
+		r.Get("/{id}/members", h.Artist.ListMembers)
+		r.Patch("/{id}/members/{userID}", h.Artist.UpdateMemberRole)
+		r.Delete("/{id}/members/{userID}", h.Artist.RemoveMember)
+		r.Get("/{id}/invites", h.Artist.ListMemberInvites)
+		r.Post("/{id}/invites", h.Artist.InviteMember)
+		r.Delete("/{id}/invites/{inviteID}", h.Artist.RevokeInvite)
+		r.Get("/{id}/verification-requests", h.Artist.ListArtistVerificationRequests)
+		r.Post("/{id}/verification-requests", h.Artist.SubmitVerification)
+
+		// Catalog management and the claim and verification review queues
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AdminOnly)
+			r.Post("/", h.Artist.CreateArtist)
+			r.Delete("/{id}", h.Artist.DeleteArtist)
+			r.Put("/{id}/verified", h.Artist.SetVerified)
+			r.Get("/claims", h.Artist.ListClaims)
+			r.Post("/claims/{claimID}/approve", h.Artist.ApproveClaim)
+			r.Post("/claims/{claimID}/reject", h.Artist.RejectClaim)
+			r.Get("/verification-requests", h.Artist.ListVerificationQueue)
+		})
+	})
+
+	return r
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/httprate"
 	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/challenge"
 	"github.com/techies/streamify/internal/handler"
 )
 
@@ -16,9 +17,13 @@ func authRouter(h *handler.Handler, cfg *app.AppConfig) chi.Router {
 	r.Use(httprate.LimitByIP(5, time.Minute))
 
 	r.Get("/verify", h.Auth.VerifyToken)
+	r.Get("/email/confirm", h.User.ConfirmEmailChange)
+	r.Get("/email/revoke", h.User.RevokeEmailChange)
 
-	r.Post("/register", h.Auth.Register)
-	r.Post("/login", h.Auth.Login)
+	r.Get("/registration-policy", h.Auth.RegistrationPolicy)
+	r.Get("/challenge", h.Auth.IssueChallenge)
+	r.With(cfg.Challenge.Require(challenge.RouteRegister)).Post("/register", h.Auth.Register)
+	r.With(cfg.Challenge.RequireAfterFailures(challenge.RouteLogin)).Post("/login", h.Auth.Login)
 	r.Post("/refresh", h.Token.RefreshToken)
 	r.Post("/logout", h.Token.Logout)
 	r.Post("/logout-all", h.Token.LogoutAllDevices)
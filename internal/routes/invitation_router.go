@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/techies/streamify/internal/handler"
+	"github.com/techies/streamify/internal/middleware"
+)
+
+func invitationRouter(h *handler.Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middleware.AdminOnly)
+
+	r.Get("/", h.Invitation.ListInvitations)
+	r.Post("/", h.Invitation.CreateInvitation)
+	r.Delete("/{id}", h.Invitation.RevokeInvitation)
+	r.Get("/{id}/redemptions", h.Invitation.ListRedemptions)
+
+	return r
+}
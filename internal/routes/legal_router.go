@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/handler"
+	"github.com/techies/streamify/internal/middleware"
+)
+
+func legalRouter(h *handler.Handler, cfg *app.AppConfig) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/documents", h.Legal.ListLegalDocuments)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(h.App.DB, cfg.JWTSecret))
+		r.Use(middleware.AdminOnly)
+		r.Post("/documents", h.Legal.CreateLegalDocument)
+	})
+
+	return r
+}
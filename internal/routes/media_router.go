@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/techies/streamify/internal/handler"
+	"github.com/techies/streamify/internal/middleware"
+)
+
+func mediaRouter(h *handler.Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.Media.ListMedia)
+	r.Get("/{id}", h.Media.GetMedia)
+
+	// Artist members in the artist's context, or admins
+	r.Post("/", h.Media.CreateMedia)
+	r.Patch("/{id}", h.Media.PatchMedia)
+
+	// Takedowns
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AdminOnly)
+		r.Put("/{id}/takedown", h.Media.TakeDown)
+		r.Delete("/{id}/takedown", h.Media.Restore)
+	})
+
+	return r
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/handler"
+	"github.com/techies/streamify/internal/middleware"
+)
+
+func profileRouter(h *handler.Handler, cfg *app.AppConfig) chi.Router {
+	r := chi.NewRouter()
+
+	// Profiles are public; signed-in callers may see more depending on the owner's settings
+	r.With(middleware.OptionalAuth(h.App.DB, cfg.JWTSecret)).Get("/{username}", h.User.GetPublicProfile)
+
+	return r
+}
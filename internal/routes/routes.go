@@ -13,6 +13,7 @@ import (
 	"github.com/techies/streamify/internal/app"
 	"github.com/techies/streamify/internal/handler"
 	internalMiddleware "github.com/techies/streamify/internal/middleware"
+	"github.com/techies/streamify/internal/storage"
 	"github.com/techies/streamify/internal/utils"
 )
 
@@ -40,8 +41,8 @@ func SetupRoutes(h *handler.Handler, cfg *app.AppConfig) http.Handler {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.AllowedOrigins, // Move these to your AppConfig
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Captcha-Token", "X-PoW-Solution"},
+		ExposedHeaders:   []string{"Link", "X-Challenge-Required"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -69,6 +70,11 @@ func SetupRoutes(h *handler.Handler, cfg *app.AppConfig) http.Handler {
 		`),
 	))
 
+	// Uploaded files, when they are kept on local disk
+	if local, ok := cfg.Storage.(*storage.Local); ok {
+		r.Handle("/media/*", http.StripPrefix("/media", local.Handler()))
+	}
+
 	// --- 5. VERSIONED API ---
 	r.Route("/api/v1", func(r chi.Router) {
 		// General API Rate Limiting
@@ -76,11 +82,19 @@ func SetupRoutes(h *handler.Handler, cfg *app.AppConfig) http.Handler {
 
 		// Authentication Domain
 		r.Mount("/auth", authRouter(h, cfg))
+		r.Mount("/legal", legalRouter(h, cfg))
+		r.Mount("/profiles", profileRouter(h, cfg))
+		r.Mount("/artists", artistRouter(h, cfg))
+		r.Mount("/albums", albumRouter(h, cfg))
 
 		// Protected Domain
 		r.Group(func(r chi.Router) {
 			r.Use(internalMiddleware.AuthMiddleware(h.App.DB, cfg.JWTSecret))
+			// Users who haven't accepted new mandatory terms can only review and accept them
+			r.Use(internalMiddleware.ConsentRequired(h.App.DB, "/api/v1/users/me/consents"))
 			r.Mount("/users", userRouter(h))
+			r.Mount("/invitations", invitationRouter(h))
+			r.Mount("/media", mediaRouter(h))
 		})
 	})
 
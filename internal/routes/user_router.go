@@ -1,7 +1,10 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/httprate"
 	"github.com/techies/streamify/internal/handler"
 	"github.com/techies/streamify/internal/middleware"
 )
@@ -9,12 +12,62 @@ import (
 func userRouter(h *handler.Handler) chi.Router {
 	r := chi.NewRouter()
 
-	r.Get("/", h.User.UserList)
+	r.With(middleware.AdminOnly).Get("/", h.User.UserList)
+	r.With(middleware.AdminOnly).Get("/export", h.User.ExportUsers)
+	r.With(middleware.AdminOnly).Post("/import", h.User.ImportUsers)
+	r.With(middleware.AdminOnly).Get("/bulk", h.User.ListBulkJobs)
+	r.With(middleware.AdminOnly).Post("/bulk", h.User.CreateBulkJob)
+	r.With(middleware.AdminOnly).Get("/bulk/{id}", h.User.GetBulkJob)
+	r.With(middleware.AdminOnly).Get("/bulk/{id}/items", h.User.ListBulkJobItems)
+	r.Patch("/me", h.User.PatchProfile)
+	r.Put("/me/avatar", h.User.UploadAvatar)
+	r.Delete("/me/avatar", h.User.DeleteAvatar)
+	r.Get("/me/email", h.User.GetEmailChange)
+	r.Post("/me/email", h.User.RequestEmailChange)
+	r.Delete("/me/email", h.User.CancelEmailChange)
+	r.Post("/me/phone/verify", h.User.StartPhoneVerification)
+	r.Post("/me/phone/verify/confirm", h.User.ConfirmPhoneVerification)
+	r.Put("/me/username", h.User.ChangeUsername)
+	r.Get("/me/content-settings", h.User.GetContentSettings)
+	r.Get("/me/privacy", h.User.GetPrivacySettings)
+	r.Patch("/me/privacy", h.User.PatchPrivacySettings)
+	r.Get("/me/preferences", h.User.GetPreferences)
+	r.Patch("/me/preferences", h.User.PatchPreferences)
+	r.Get("/me/preferences/schema", h.User.GetPreferencesSchema)
+	r.Get("/me/profiles", h.User.ListListenerProfiles)
+	r.Post("/me/profiles", h.User.CreateListenerProfile)
+	r.Put("/me/profiles/{id}", h.User.UpdateListenerProfile)
+	r.Delete("/me/profiles/{id}", h.User.DeleteListenerProfile)
+	// Slows down guessing profile PINs
+	r.With(httprate.LimitByIP(10, time.Minute)).Post("/me/profiles/{id}/select", h.User.SelectListenerProfile)
+	r.Get("/me/follow-requests", h.User.ListFollowRequests)
+	r.Post("/me/follow-requests/{id}/approve", h.User.ApproveFollowRequest)
+	r.Delete("/me/follow-requests/{id}", h.User.DeclineFollowRequest)
+	r.Delete("/me/followers/{id}", h.User.RemoveFollower)
+	r.Get("/me/blocks", h.User.ListBlockedUsers)
+	r.Get("/me/consents", h.Legal.ListConsents)
+	r.Post("/me/consents", h.Legal.GiveConsent)
+	r.Delete("/me/consents/{id}", h.Legal.WithdrawConsent)
+	r.Get("/username/{username}", h.User.GetUserByUsername)
+	r.Get("/owner/transfer", h.User.GetOwnershipTransfer)
+	r.With(middleware.OwnerOnly).Post("/owner/transfer", h.User.StartOwnershipTransfer)
+	r.Delete("/owner/transfer", h.User.CancelOwnershipTransfer)
+	r.With(middleware.AdminOnly).Post("/owner/transfer/accept", h.User.AcceptOwnershipTransfer)
+	r.With(middleware.OwnerOnly).Post("/admins/demote", h.User.DemoteAllAdmins)
 	r.Get("/{id}", h.User.GetUser)
 	r.Put("/{id}", h.User.UpdateProfile)
 	r.Put("/{id}/role", h.User.UpdateUserRole)
+	r.Get("/{id}/followers", h.User.ListFollowers)
+	r.Get("/{id}/following", h.User.ListFollowing)
+	r.Post("/{id}/follow", h.User.FollowUser)
+	r.Delete("/{id}/follow", h.User.UnfollowUser)
+	r.Post("/{id}/block", h.User.BlockUser)
+	r.Delete("/{id}/block", h.User.UnblockUser)
 	r.With(middleware.AdminOnly).Post("/{id}/lock", h.User.LockUser)
 	r.With(middleware.AdminOnly).Post("/{id}/unlock", h.User.UnLockUser)
+	r.With(middleware.AdminOnly).Get("/{id}/suspensions", h.User.ListSuspensions)
+	r.With(middleware.AdminOnly).Post("/{id}/suspensions", h.User.SuspendUser)
+	r.With(middleware.AdminOnly).Delete("/{id}/suspensions", h.User.LiftSuspension)
 	r.With(middleware.AdminOnly).Delete("/{id}", h.User.DeleteUser)
 	r.With(middleware.AdminOnly).Delete("/old-soft-deleted", h.User.PermanentlyDeleteOldSoftDeletedUsers)
 
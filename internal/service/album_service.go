@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type AlbumService struct {
+	BaseService
+	cfg *app.AppConfig
+}
+
+func NewAlbumService(db *database.Queries, cfg *app.AppConfig) *AlbumService {
+	return &AlbumService{
+		BaseService: NewBaseService(db),
+		cfg:         cfg,
+	}
+}
+
+type CreateAlbumParams struct {
+	ArtistID uuid.UUID
+	Title    string `validate:"required,max=100"`
+	// ReleaseDate is formatted as YYYY-MM-DD
+	ReleaseDate      *string `validate:"omitempty,datetime=2006-01-02"`
+	ReleaseType      string  `validate:"omitempty,oneof=album ep single compilation"`
+	Upc              *string `validate:"omitempty,numeric,min=12,max=13"`
+	Label            *string `validate:"omitempty,max=200"`
+	CopyrightLine    *string `validate:"omitempty,max=300"`
+	PhonographicLine *string `validate:"omitempty,max=300"`
+}
+
+// CreateAlbum adds an album to the artist's catalog; it defaults to the album release type
+func (s *AlbumService) CreateAlbum(ctx context.Context, actor ArtistActor, params CreateAlbumParams) (database.Album, *utils.AppError) {
+	params.Title = strings.TrimSpace(params.Title)
+	if err := validate.Struct(params); err != nil {
+		return database.Album{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, params.ArtistID, ArtistManageCatalog); appErr != nil {
+		return database.Album{}, appErr
+	}
+	if params.ReleaseType == "" {
+		params.ReleaseType = string(database.AlbumReleaseTypeAlbum)
+	}
+
+	album, err := s.DB.CreateAlbum(ctx, database.CreateAlbumParams{
+		ArtistID:         params.ArtistID,
+		Title:            params.Title,
+		ReleaseDate:      releaseDate(params.ReleaseDate),
+		ReleaseType:      database.AlbumReleaseType(params.ReleaseType),
+		Upc:              utils.ToNullString(params.Upc),
+		Label:            utils.ToNullString(params.Label),
+		CopyrightLine:    utils.ToNullString(params.CopyrightLine),
+		PhonographicLine: utils.ToNullString(params.PhonographicLine),
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.Album{}, upcTakenError()
+		}
+		if utils.IsForeignKeyViolation(err) {
+			return database.Album{}, artistNotFoundError()
+		}
+		return database.Album{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create album",
+			Err:     err,
+		}
+	}
+	return album, nil
+}
+
+// AlbumDetail is an album with its tracklist in play order
+type AlbumDetail struct {
+	Album      database.Album
+	ArtistName string
+	Tracks     []database.ListAlbumTracksRow
+	// Duration sums the track durations in seconds; tracks without one count as zero
+	Duration int64
+}
+
+// GetAlbum returns the album and its ordered tracklist
+func (s *AlbumService) GetAlbum(ctx context.Context, albumID uuid.UUID) (AlbumDetail, *utils.AppError) {
+	row, err := s.DB.GetAlbumWithArtist(ctx, albumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AlbumDetail{}, albumNotFoundError()
+	}
+	if err != nil {
+		return AlbumDetail{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	tracks, appErr := s.listTracks(ctx, albumID)
+	if appErr != nil {
+		return AlbumDetail{}, appErr
+	}
+	detail := AlbumDetail{Album: row.Album, ArtistName: row.ArtistName, Tracks: tracks}
+	for _, t := range tracks {
+		detail.Duration += int64(t.Duration.Int32)
+	}
+	return detail, nil
+}
+
+type ListArtistAlbumsResult struct {
+	Albums []database.ListArtistAlbumsRow
+	// Next is the cursor of the last album, nil on the last page
+	Next *pagination.Cursor
+}
+
+// ListArtistAlbums returns the artist's albums, newest first, optionally of one release type
+func (s *AlbumService) ListArtistAlbums(ctx context.Context, artistID uuid.UUID, releaseType string, page pagination.Params) (ListArtistAlbumsResult, *utils.AppError) {
+	var kind database.NullAlbumReleaseType
+	if releaseType != "" {
+		if validate.Var(releaseType, "oneof=album ep single compilation") != nil {
+			return ListArtistAlbumsResult{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "type must be album, ep, single or compilation",
+			}
+		}
+		kind = database.NullAlbumReleaseType{AlbumReleaseType: database.AlbumReleaseType(releaseType), Valid: true}
+	}
+
+	rows, err := s.DB.ListArtistAlbums(ctx, database.ListArtistAlbumsParams{
+		ArtistID:        artistID,
+		ReleaseType:     kind,
+		CursorCreatedAt: page.CursorCreatedAt(),
+		CursorID:        page.CursorID(),
+		Lim:             page.FetchLimit(),
+	})
+	if err != nil {
+		return ListArtistAlbumsResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch albums",
+			Err:     err,
+		}
+	}
+	rows, more := pagination.Trim(rows, page)
+	result := ListArtistAlbumsResult{Albums: rows}
+	if more {
+		last := rows[len(rows)-1].Album
+		result.Next = &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return result, nil
+}
+
+// AlbumPatch is a JSON merge patch of an album.
+// Absent fields are left alone and null clears a field; the title and release type can't be cleared.
+type AlbumPatch struct {
+	Title            patch.Field[string] `json:"title"`
+	ReleaseDate      patch.Field[string] `json:"release_date"`
+	ReleaseType      patch.Field[string] `json:"release_type"`
+	Upc              patch.Field[string] `json:"upc"`
+	Label            patch.Field[string] `json:"label"`
+	CopyrightLine    patch.Field[string] `json:"copyright_line"`
+	PhonographicLine patch.Field[string] `json:"phonographic_line"`
+}
+
+// albumPatchRules are the validator tags and messages for the patchable fields
+var albumPatchRules = []struct {
+	name, tag, message string
+	field              func(*AlbumPatch) *patch.Field[string]
+}{
+	{"title", "required,max=100", "must be 1 to 100 characters", func(p *AlbumPatch) *patch.Field[string] { return &p.Title }},
+	{"release_date", "datetime=2006-01-02", "must be formatted as YYYY-MM-DD", func(p *AlbumPatch) *patch.Field[string] { return &p.ReleaseDate }},
+	{"release_type", "oneof=album ep single compilation", "must be album, ep, single or compilation", func(p *AlbumPatch) *patch.Field[string] { return &p.ReleaseType }},
+	{"upc", "numeric,min=12,max=13", "must be a 12 or 13 digit barcode", func(p *AlbumPatch) *patch.Field[string] { return &p.Upc }},
+	{"label", "max=200", "must be at most 200 characters", func(p *AlbumPatch) *patch.Field[string] { return &p.Label }},
+	{"copyright_line", "max=300", "must be at most 300 characters", func(p *AlbumPatch) *patch.Field[string] { return &p.CopyrightLine }},
+	{"phonographic_line", "max=300", "must be at most 300 characters", func(p *AlbumPatch) *patch.Field[string] { return &p.PhonographicLine }},
+}
+
+// PatchAlbum applies a merge patch to an album. Validation errors are
+// reported per field in AppError.Fields.
+func (s *AlbumService) PatchAlbum(ctx context.Context, actor ArtistActor, albumID uuid.UUID, p AlbumPatch) (database.Album, *utils.AppError) {
+	current, appErr := s.getAlbum(ctx, albumID)
+	if appErr != nil {
+		return database.Album{}, appErr
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, current.ArtistID, ArtistManageCatalog); appErr != nil {
+		return database.Album{}, appErr
+	}
+	if p.Title.Present() {
+		p.Title.Value = strings.TrimSpace(p.Title.Value)
+	}
+
+	fields := map[string]string{}
+	if p.Title.Null {
+		fields["title"] = "can't be cleared"
+	}
+	if p.ReleaseType.Null {
+		fields["release_type"] = "can't be cleared"
+	}
+	for _, rule := range albumPatchRules {
+		f := rule.field(&p)
+		if f.Present() && validate.Var(f.Value, rule.tag) != nil {
+			fields[rule.name] = rule.message
+		}
+	}
+	if len(fields) > 0 {
+		return database.Album{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+
+	album, err := s.DB.PatchAlbum(ctx, database.PatchAlbumParams{
+		ID:                  albumID,
+		SetTitle:            p.Title.Set,
+		Title:               p.Title.Value,
+		SetReleaseDate:      p.ReleaseDate.Set,
+		ReleaseDate:         releaseDate(p.ReleaseDate.Ptr()),
+		SetReleaseType:      p.ReleaseType.Set,
+		ReleaseType:         database.AlbumReleaseType(p.ReleaseType.Value),
+		SetUpc:              p.Upc.Set,
+		Upc:                 utils.ToNullString(p.Upc.Ptr()),
+		SetLabel:            p.Label.Set,
+		Label:               utils.ToNullString(p.Label.Ptr()),
+		SetCopyrightLine:    p.CopyrightLine.Set,
+		CopyrightLine:       utils.ToNullString(p.CopyrightLine.Ptr()),
+		SetPhonographicLine: p.PhonographicLine.Set,
+		PhonographicLine:    utils.ToNullString(p.PhonographicLine.Ptr()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Album{}, albumNotFoundError()
+	}
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.Album{}, upcTakenError()
+		}
+		return database.Album{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update album",
+			Err:     err,
+		}
+	}
+	return album, nil
+}
+
+type AlbumTrackParams struct {
+	SongID uuid.UUID
+	// DiscNumber defaults to 1
+	DiscNumber  int32 `validate:"omitempty,min=1,max=99"`
+	TrackNumber int32 `validate:"required,min=1,max=999"`
+}
+
+type SetAlbumTracksParams struct {
+	Tracks []AlbumTrackParams `validate:"max=500,dive"`
+}
+
+// SetTracks replaces the album's tracklist. The songs must be credited to the
+// album's artist; songs that were on the album but aren't listed are detached.
+func (s *AlbumService) SetTracks(ctx context.Context, actor ArtistActor, albumID uuid.UUID, params SetAlbumTracksParams) ([]database.ListAlbumTracksRow, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	type position struct{ disc, track int32 }
+	songIDs := make([]uuid.UUID, 0, len(params.Tracks))
+	seenSongs := map[uuid.UUID]bool{}
+	seenPositions := map[position]bool{}
+	for i, t := range params.Tracks {
+		if t.DiscNumber == 0 {
+			params.Tracks[i].DiscNumber = 1
+		}
+		pos := position{params.Tracks[i].DiscNumber, t.TrackNumber}
+		switch {
+		case t.SongID == uuid.Nil:
+			return nil, &utils.AppError{Code: http.StatusBadRequest, Message: "Every track needs a song_id"}
+		case seenSongs[t.SongID]:
+			return nil, &utils.AppError{Code: http.StatusBadRequest, Message: "A song can only appear once on an album"}
+		case seenPositions[pos]:
+			return nil, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Disc %d has more than one track %d", pos.disc, pos.track),
+			}
+		}
+		seenSongs[t.SongID] = true
+		seenPositions[pos] = true
+		songIDs = append(songIDs, t.SongID)
+	}
+
+	album, appErr := s.getAlbum(ctx, albumID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, album.ArtistID, ArtistManageCatalog); appErr != nil {
+		return nil, appErr
+	}
+
+	if len(songIDs) > 0 {
+		owned, err := s.DB.ListArtistSongIDs(ctx, database.ListArtistSongIDsParams{
+			Ids:      songIDs,
+			ArtistID: uuid.NullUUID{UUID: album.ArtistID, Valid: true},
+		})
+		if err != nil {
+			return nil, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+		if len(owned) != len(songIDs) {
+			return nil, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Tracks must be songs credited to the album's artist",
+			}
+		}
+	}
+
+	albumRef := uuid.NullUUID{UUID: albumID, Valid: true}
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		// Detach first so the new numbering can't collide with the old one
+		if err := q.DetachAlbumTracks(ctx, albumRef); err != nil {
+			return err
+		}
+		for _, t := range params.Tracks {
+			if err := q.SetSongTrack(ctx, database.SetSongTrackParams{
+				ID:          t.SongID,
+				AlbumID:     albumRef,
+				DiscNumber:  t.DiscNumber,
+				TrackNumber: sql.NullInt32{Int32: t.TrackNumber, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if utils.IsForeignKeyViolation(err) {
+			return nil, albumNotFoundError()
+		}
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update tracklist",
+			Err:     err,
+		}
+	}
+	return s.listTracks(ctx, albumID)
+}
+
+// DeleteAlbum removes an album. An album that still has tracks is only
+// deleted with detachTracks, which keeps the songs as standalone releases.
+func (s *AlbumService) DeleteAlbum(ctx context.Context, actor ArtistActor, albumID uuid.UUID, detachTracks bool) *utils.AppError {
+	current, appErr := s.getAlbum(ctx, albumID)
+	if appErr != nil {
+		return appErr
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, current.ArtistID, ArtistManageCatalog); appErr != nil {
+		return appErr
+	}
+
+	albumRef := uuid.NullUUID{UUID: albumID, Valid: true}
+	count, err := s.DB.CountAlbumTracks(ctx, albumRef)
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if count > 0 && !detachTracks {
+		return albumHasTracksError(count)
+	}
+
+	var n int64
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if detachTracks {
+			if err := q.DetachAlbumTracks(ctx, albumRef); err != nil {
+				return err
+			}
+		}
+		var err error
+		n, err = q.DeleteAlbum(ctx, albumID)
+		return err
+	})
+	if err != nil {
+		// Tracks were added after the count
+		if utils.IsForeignKeyViolation(err) {
+			return albumHasTracksError(count)
+		}
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to delete album",
+			Err:     err,
+		}
+	}
+	if n == 0 {
+		return albumNotFoundError()
+	}
+
+	if current.CoverKey.Valid {
+		deleteImage(ctx, s.cfg, current.CoverKey.String)
+	}
+	return nil
+}
+
+// UploadCover replaces the album's cover with renditions of the uploaded image
+func (s *AlbumService) UploadCover(ctx context.Context, actor ArtistActor, albumID uuid.UUID, data []byte) (database.Album, *utils.AppError) {
+	current, appErr := s.getAlbum(ctx, albumID)
+	if appErr != nil {
+		return database.Album{}, appErr
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, current.ArtistID, ArtistManageCatalog); appErr != nil {
+		return database.Album{}, appErr
+	}
+
+	key, url, appErr := storeImage(ctx, s.cfg, "covers/"+albumID.String(), data)
+	if appErr != nil {
+		return database.Album{}, appErr
+	}
+
+	album, err := s.DB.SetAlbumCover(ctx, database.SetAlbumCoverParams{
+		ID:       albumID,
+		CoverUrl: sql.NullString{String: url, Valid: true},
+		CoverKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		if current.CoverKey.String != key {
+			deleteImage(ctx, s.cfg, key)
+		}
+		return database.Album{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update cover",
+			Err:     err,
+		}
+	}
+
+	if current.CoverKey.Valid && current.CoverKey.String != key {
+		deleteImage(ctx, s.cfg, current.CoverKey.String)
+	}
+	return album, nil
+}
+
+func (s *AlbumService) getAlbum(ctx context.Context, albumID uuid.UUID) (database.Album, *utils.AppError) {
+	album, err := s.DB.GetAlbumByID(ctx, albumID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Album{}, albumNotFoundError()
+	}
+	if err != nil {
+		return database.Album{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return album, nil
+}
+
+func (s *AlbumService) listTracks(ctx context.Context, albumID uuid.UUID) ([]database.ListAlbumTracksRow, *utils.AppError) {
+	tracks, err := s.DB.ListAlbumTracks(ctx, uuid.NullUUID{UUID: albumID, Valid: true})
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch tracklist",
+			Err:     err,
+		}
+	}
+	return tracks, nil
+}
+
+// releaseDate converts a validated YYYY-MM-DD date; nil clears it
+func releaseDate(value *string) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, *value)
+	if err != nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
+
+func albumNotFoundError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusNotFound,
+		Message: "Album not found",
+	}
+}
+
+func albumHasTracksError(count int64) *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusConflict,
+		Message: fmt.Sprintf("Album still has %d tracks; delete with detach_tracks=true to keep them as standalone songs", count),
+	}
+}
+
+func upcTakenError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusConflict,
+		Message: "Another album already uses this UPC",
+	}
+}
@@ -0,0 +1,680 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/handler/token"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// artistInviteTTL is how long an invite into an artist can be accepted
+const artistInviteTTL = 14 * 24 * time.Hour
+
+// ArtistActor is the caller acting on an artist
+type ArtistActor struct {
+	UserID uuid.UUID
+	// Admin callers manage every artist without being members
+	Admin bool
+	// ArtistID is the artist the access token acts for, uuid.Nil outside an artist context
+	ArtistID uuid.UUID
+}
+
+// ArtistPermission is something artist members may do.
+// Each role has the permissions of the roles below it.
+type ArtistPermission int
+
+const (
+	// ArtistManageCatalog covers albums and tracks; editors and up
+	ArtistManageCatalog ArtistPermission = iota
+	// ArtistEditProfile covers the artist's name, bio and avatar; managers and up
+	ArtistEditProfile
+	// ArtistManageMembers covers invites, roles and removing members; owners only
+	ArtistManageMembers
+)
+
+var artistPermissionRole = map[ArtistPermission]database.ArtistMemberRole{
+	ArtistManageCatalog: database.ArtistMemberRoleEditor,
+	ArtistEditProfile:   database.ArtistMemberRoleManager,
+	ArtistManageMembers: database.ArtistMemberRoleOwner,
+}
+
+func artistRoleRank(role database.ArtistMemberRole) int {
+	switch role {
+	case database.ArtistMemberRoleOwner:
+		return 3
+	case database.ArtistMemberRoleManager:
+		return 2
+	case database.ArtistMemberRoleEditor:
+		return 1
+	}
+	return 0
+}
+
+// authorizeArtist checks that the actor may act on the artist. Members need a
+// token in the artist's context and a role that grants perm; admins always pass.
+// Membership is read from the database so removed members lose access at once.
+func authorizeArtist(ctx context.Context, db *database.Queries, actor ArtistActor, artistID uuid.UUID, perm ArtistPermission) *utils.AppError {
+	if actor.Admin {
+		return nil
+	}
+	if actor.ArtistID != artistID {
+		return &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Switch to this artist's context to manage it",
+		}
+	}
+
+	member, err := db.GetArtistMember(ctx, database.GetArtistMemberParams{ArtistID: artistID, UserID: actor.UserID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "You are not a member of this artist",
+		}
+	}
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if artistRoleRank(member.Role) < artistRoleRank(artistPermissionRole[perm]) {
+		return &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Your role in this artist doesn't allow this",
+		}
+	}
+	return nil
+}
+
+// requireArtistMember lets admins and members in any role through, with or
+// without the artist context. It guards reads, writes go through authorizeArtist.
+func requireArtistMember(ctx context.Context, db *database.Queries, actor ArtistActor, artistID uuid.UUID) *utils.AppError {
+	if actor.Admin {
+		return nil
+	}
+	_, err := db.GetArtistMember(ctx, database.GetArtistMemberParams{ArtistID: artistID, UserID: actor.UserID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "You are not a member of this artist",
+		}
+	}
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// ClaimArtist asks the admins to make the user the owner of an artist nobody owns yet
+func (s *ArtistService) ClaimArtist(ctx context.Context, userID, artistID uuid.UUID, message *string) (database.ArtistClaim, *utils.AppError) {
+	if message != nil {
+		trimmed := strings.TrimSpace(*message)
+		message = &trimmed
+		if validate.Var(trimmed, "max=2000") != nil {
+			return database.ArtistClaim{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Fields:  map[string]string{"message": "must be at most 2000 characters"},
+			}
+		}
+	}
+
+	if _, appErr := s.getArtist(ctx, artistID); appErr != nil {
+		return database.ArtistClaim{}, appErr
+	}
+	owned, err := s.DB.ArtistHasOwner(ctx, artistID)
+	if err != nil {
+		return database.ArtistClaim{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if owned {
+		return database.ArtistClaim{}, artistOwnedError()
+	}
+
+	claim, err := s.DB.CreateArtistClaim(ctx, database.CreateArtistClaimParams{
+		ArtistID: artistID,
+		UserID:   userID,
+		Message:  utils.ToNullString(message),
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.ArtistClaim{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "You already have a pending claim on this artist",
+			}
+		}
+		return database.ArtistClaim{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to submit claim",
+			Err:     err,
+		}
+	}
+	return claim, nil
+}
+
+type ListArtistClaimsResult struct {
+	Claims []database.ListPendingArtistClaimsRow
+	// Next is the cursor of the last claim, nil on the last page
+	Next *pagination.Cursor
+}
+
+// ListPendingClaims returns the claims waiting for review, oldest first
+func (s *ArtistService) ListPendingClaims(ctx context.Context, page pagination.Params) (ListArtistClaimsResult, *utils.AppError) {
+	rows, err := s.DB.ListPendingArtistClaims(ctx, database.ListPendingArtistClaimsParams{
+		CursorCreatedAt: page.CursorCreatedAt(),
+		CursorID:        page.CursorID(),
+		Lim:             page.FetchLimit(),
+	})
+	if err != nil {
+		return ListArtistClaimsResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch claims",
+			Err:     err,
+		}
+	}
+	rows, more := pagination.Trim(rows, page)
+	result := ListArtistClaimsResult{Claims: rows}
+	if more {
+		last := rows[len(rows)-1].ArtistClaim
+		result.Next = &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return result, nil
+}
+
+// ReviewClaim approves or rejects a pending claim. Approving makes the claimant
+// the artist's owner and rejects the other claims on it.
+func (s *ArtistService) ReviewClaim(ctx context.Context, adminID, claimID uuid.UUID, approve bool, note *string) (database.ArtistClaim, *utils.AppError) {
+	status := database.ArtistClaimStatusRejected
+	if approve {
+		status = database.ArtistClaimStatusApproved
+	}
+
+	var claim database.ArtistClaim
+	errOwned := errors.New("artist already has an owner")
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		var err error
+		claim, err = q.ReviewArtistClaim(ctx, database.ReviewArtistClaimParams{
+			ID:         claimID,
+			Status:     status,
+			ReviewedBy: uuid.NullUUID{UUID: adminID, Valid: true},
+			ReviewNote: utils.ToNullString(note),
+		})
+		if err != nil || !approve {
+			return err
+		}
+
+		owners, err := q.LockArtistOwners(ctx, claim.ArtistID)
+		if err != nil {
+			return err
+		}
+		if len(owners) > 0 {
+			return errOwned
+		}
+		if _, err := q.AddArtistMember(ctx, database.AddArtistMemberParams{
+			ArtistID: claim.ArtistID,
+			UserID:   claim.UserID,
+			Role:     database.ArtistMemberRoleOwner,
+		}); err != nil {
+			return err
+		}
+		return q.RejectOtherArtistClaims(ctx, database.RejectOtherArtistClaimsParams{
+			ArtistID:   claim.ArtistID,
+			ID:         claim.ID,
+			ReviewedBy: uuid.NullUUID{UUID: adminID, Valid: true},
+		})
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, getErr := s.DB.GetArtistClaim(ctx, claimID); getErr == nil {
+			return database.ArtistClaim{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Claim was already reviewed",
+			}
+		}
+		return database.ArtistClaim{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Claim not found",
+		}
+	}
+	if errors.Is(err, errOwned) {
+		return database.ArtistClaim{}, artistOwnedError()
+	}
+	if err != nil {
+		return database.ArtistClaim{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to review claim",
+			Err:     err,
+		}
+	}
+	return claim, nil
+}
+
+// ListMembers returns the artist's members; any member and admins may see them
+func (s *ArtistService) ListMembers(ctx context.Context, actor ArtistActor, artistID uuid.UUID) ([]database.ListArtistMembersRow, *utils.AppError) {
+	if _, appErr := s.getArtist(ctx, artistID); appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := requireArtistMember(ctx, s.DB, actor, artistID); appErr != nil {
+		return nil, appErr
+	}
+
+	rows, err := s.DB.ListArtistMembers(ctx, artistID)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch members",
+			Err:     err,
+		}
+	}
+	return rows, nil
+}
+
+// ListUserArtists returns the artists the user is a member of
+func (s *ArtistService) ListUserArtists(ctx context.Context, userID uuid.UUID) ([]database.ListUserArtistMembershipsRow, *utils.AppError) {
+	rows, err := s.DB.ListUserArtistMemberships(ctx, userID)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch artists",
+			Err:     err,
+		}
+	}
+	return rows, nil
+}
+
+type InviteArtistMemberParams struct {
+	Username string `validate:"required,max=50"`
+	Role     string `validate:"required,oneof=owner manager editor"`
+}
+
+// InviteMember invites an existing user into the artist; inviting them again replaces the invite
+func (s *ArtistService) InviteMember(ctx context.Context, actor ArtistActor, artistID uuid.UUID, params InviteArtistMemberParams) (database.ArtistMemberInvite, *utils.AppError) {
+	params.Username = strings.TrimSpace(params.Username)
+	if err := validate.Struct(params); err != nil {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistManageMembers); appErr != nil {
+		return database.ArtistMemberInvite{}, appErr
+	}
+	if _, appErr := s.getArtist(ctx, artistID); appErr != nil {
+		return database.ArtistMemberInvite{}, appErr
+	}
+
+	user, err := s.DB.GetUserByUsername(ctx, params.Username)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && (user.Status == "deleted" || user.IsLocked) {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+	if err != nil {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	_, err = s.DB.GetArtistMember(ctx, database.GetArtistMemberParams{ArtistID: artistID, UserID: user.ID})
+	if err == nil {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "User is already a member of this artist",
+		}
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	invite, err := s.DB.CreateArtistMemberInvite(ctx, database.CreateArtistMemberInviteParams{
+		ArtistID:  artistID,
+		UserID:    user.ID,
+		Role:      database.ArtistMemberRole(params.Role),
+		InvitedBy: uuid.NullUUID{UUID: actor.UserID, Valid: true},
+		ExpiresAt: time.Now().Add(artistInviteTTL),
+	})
+	if err != nil {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create invite",
+			Err:     err,
+		}
+	}
+	return invite, nil
+}
+
+// ListMemberInvites returns the artist's open invites
+func (s *ArtistService) ListMemberInvites(ctx context.Context, actor ArtistActor, artistID uuid.UUID) ([]database.ListArtistMemberInvitesRow, *utils.AppError) {
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistManageMembers); appErr != nil {
+		return nil, appErr
+	}
+	rows, err := s.DB.ListArtistMemberInvites(ctx, artistID)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch invites",
+			Err:     err,
+		}
+	}
+	return rows, nil
+}
+
+// RevokeInvite withdraws an open invite of the artist
+func (s *ArtistService) RevokeInvite(ctx context.Context, actor ArtistActor, artistID, inviteID uuid.UUID) *utils.AppError {
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistManageMembers); appErr != nil {
+		return appErr
+	}
+	invite, appErr := s.getInvite(ctx, inviteID)
+	if appErr != nil {
+		return appErr
+	}
+	if invite.ArtistID != artistID {
+		return inviteNotFoundError()
+	}
+	return s.deleteInvite(ctx, inviteID)
+}
+
+// ListUserInvites returns the open invites addressed to the user
+func (s *ArtistService) ListUserInvites(ctx context.Context, userID uuid.UUID) ([]database.ListUserArtistInvitesRow, *utils.AppError) {
+	rows, err := s.DB.ListUserArtistInvites(ctx, userID)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch invites",
+			Err:     err,
+		}
+	}
+	return rows, nil
+}
+
+// AcceptInvite makes the user a member of the inviting artist with the invited role
+func (s *ArtistService) AcceptInvite(ctx context.Context, userID, inviteID uuid.UUID) (database.ArtistMember, *utils.AppError) {
+	invite, appErr := s.getInvite(ctx, inviteID)
+	if appErr != nil {
+		return database.ArtistMember{}, appErr
+	}
+	if invite.UserID != userID || time.Now().After(invite.ExpiresAt) {
+		return database.ArtistMember{}, inviteNotFoundError()
+	}
+
+	var member database.ArtistMember
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		n, err := q.DeleteArtistMemberInvite(ctx, inviteID)
+		if err != nil {
+			return err
+		}
+		// Revoked or accepted in the meantime
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		member, err = q.AddArtistMember(ctx, database.AddArtistMemberParams{
+			ArtistID: invite.ArtistID,
+			UserID:   userID,
+			Role:     invite.Role,
+		})
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.ArtistMember{}, inviteNotFoundError()
+	}
+	if err != nil {
+		return database.ArtistMember{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to accept invite",
+			Err:     err,
+		}
+	}
+	return member, nil
+}
+
+// DeclineInvite deletes an invite addressed to the user
+func (s *ArtistService) DeclineInvite(ctx context.Context, userID, inviteID uuid.UUID) *utils.AppError {
+	invite, appErr := s.getInvite(ctx, inviteID)
+	if appErr != nil {
+		return appErr
+	}
+	if invite.UserID != userID {
+		return inviteNotFoundError()
+	}
+	return s.deleteInvite(ctx, inviteID)
+}
+
+// UpdateMemberRole changes a member's role; the last owner can't be demoted
+func (s *ArtistService) UpdateMemberRole(ctx context.Context, actor ArtistActor, artistID, userID uuid.UUID, role string) (database.ArtistMember, *utils.AppError) {
+	if validate.Var(role, "required,oneof=owner manager editor") != nil {
+		return database.ArtistMember{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  map[string]string{"role": "must be owner, manager or editor"},
+		}
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistManageMembers); appErr != nil {
+		return database.ArtistMember{}, appErr
+	}
+
+	var member database.ArtistMember
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		owners, err := q.LockArtistOwners(ctx, artistID)
+		if err != nil {
+			return err
+		}
+		if database.ArtistMemberRole(role) != database.ArtistMemberRoleOwner && isLastOwner(owners, userID) {
+			return errLastArtistOwner
+		}
+		member, err = q.UpdateArtistMemberRole(ctx, database.UpdateArtistMemberRoleParams{
+			ArtistID: artistID,
+			UserID:   userID,
+			Role:     database.ArtistMemberRole(role),
+		})
+		return err
+	})
+	if appErr := memberChangeError(err, "Failed to update member"); appErr != nil {
+		return database.ArtistMember{}, appErr
+	}
+	return member, nil
+}
+
+// RemoveMember removes a member from the artist. Owners remove anyone and
+// members may leave on their own; the last owner can't go.
+func (s *ArtistService) RemoveMember(ctx context.Context, actor ArtistActor, artistID, userID uuid.UUID) *utils.AppError {
+	if actor.UserID != userID {
+		if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistManageMembers); appErr != nil {
+			return appErr
+		}
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		owners, err := q.LockArtistOwners(ctx, artistID)
+		if err != nil {
+			return err
+		}
+		if isLastOwner(owners, userID) {
+			return errLastArtistOwner
+		}
+		n, err := q.DeleteArtistMember(ctx, database.DeleteArtistMemberParams{ArtistID: artistID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		// Refreshed tokens must not act for the artist anymore
+		return q.ClearArtistFromUserSessions(ctx, database.ClearArtistFromUserSessionsParams{
+			UserID:   userID,
+			ArtistID: uuid.NullUUID{UUID: artistID, Valid: true},
+		})
+	})
+	return memberChangeError(err, "Failed to remove member")
+}
+
+type SelectArtistContextParams struct {
+	UserID    uuid.UUID `validate:"required"`
+	SessionID uuid.UUID `validate:"required"`
+	// ArtistID is uuid.Nil to leave the artist context
+	ArtistID uuid.UUID
+}
+
+type SelectArtistContextResult struct {
+	// Member is the caller's membership, nil when leaving the artist context
+	Member      *database.ArtistMember
+	AccessToken string
+}
+
+// SelectArtistContext switches the session to act for an artist the user is a member of
+// and issues an access token carrying it. Refreshed tokens keep the artist until another
+// one is selected or the membership ends.
+func (s *ArtistService) SelectArtistContext(ctx context.Context, params SelectArtistContextParams) (SelectArtistContextResult, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return SelectArtistContextResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	var result SelectArtistContextResult
+	if params.ArtistID != uuid.Nil {
+		member, err := s.DB.GetArtistMember(ctx, database.GetArtistMemberParams{ArtistID: params.ArtistID, UserID: params.UserID})
+		if errors.Is(err, sql.ErrNoRows) {
+			return SelectArtistContextResult{}, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "You are not a member of this artist",
+			}
+		}
+		if err != nil {
+			return SelectArtistContextResult{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+		result.Member = &member
+	}
+
+	user, err := s.DB.GetUserById(ctx, params.UserID)
+	if err != nil {
+		return SelectArtistContextResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to load user",
+			Err:     err,
+		}
+	}
+
+	session, err := s.DB.SetSessionArtist(ctx, database.SetSessionArtistParams{
+		ID:       params.SessionID,
+		ArtistID: uuid.NullUUID{UUID: params.ArtistID, Valid: params.ArtistID != uuid.Nil},
+	})
+	if err != nil {
+		return SelectArtistContextResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update session",
+			Err:     err,
+		}
+	}
+
+	result.AccessToken, err = utils.GenerateToken(user.ID, session.ID, token.AccessTokenTTL, s.cfg.JWTSecret, user.Role, user.FirstName.String, user.LastName.String, user.PhoneNumber.String, user.Email, session.ProfileID.UUID, params.ArtistID)
+	if err != nil {
+		return SelectArtistContextResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to generate access token",
+			Err:     err,
+		}
+	}
+	return result, nil
+}
+
+var errLastArtistOwner = errors.New("last owner of the artist")
+
+func isLastOwner(owners []uuid.UUID, userID uuid.UUID) bool {
+	return len(owners) == 1 && owners[0] == userID
+}
+
+func memberChangeError(err error, msg string) *utils.AppError {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sql.ErrNoRows):
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Member not found",
+		}
+	case errors.Is(err, errLastArtistOwner):
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "An artist needs at least one owner; make someone else owner first",
+		}
+	}
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func (s *ArtistService) getInvite(ctx context.Context, inviteID uuid.UUID) (database.ArtistMemberInvite, *utils.AppError) {
+	invite, err := s.DB.GetArtistMemberInvite(ctx, inviteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.ArtistMemberInvite{}, inviteNotFoundError()
+	}
+	if err != nil {
+		return database.ArtistMemberInvite{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return invite, nil
+}
+
+func (s *ArtistService) deleteInvite(ctx context.Context, inviteID uuid.UUID) *utils.AppError {
+	n, err := s.DB.DeleteArtistMemberInvite(ctx, inviteID)
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to delete invite",
+			Err:     err,
+		}
+	}
+	if n == 0 {
+		return inviteNotFoundError()
+	}
+	return nil
+}
+
+func inviteNotFoundError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusNotFound,
+		Message: "Invite not found or expired",
+	}
+}
+
+func artistOwnedError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusConflict,
+		Message: "Artist already has an owner; ask them for an invite",
+	}
+}
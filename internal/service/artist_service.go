@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/utils"
+)
+
+type ArtistService struct {
+	BaseService
+	cfg *app.AppConfig
+}
+
+func NewArtistService(db *database.Queries, cfg *app.AppConfig) *ArtistService {
+	return &ArtistService{
+		BaseService: NewBaseService(db),
+		cfg:         cfg,
+	}
+}
+
+// ArtistWithStats is an artist with its aggregated audience numbers
+type ArtistWithStats struct {
+	Artist        database.Artist
+	FollowerCount int64
+	// PlayCount sums the plays of all media credited to the artist
+	PlayCount int64
+	// Following is whether the viewer follows the artist; only set for single artists
+	Following bool
+}
+
+type CreateArtistParams struct {
+	Name      string  `validate:"required,max=200"`
+	Bio       *string `validate:"omitempty,max=2000"`
+	AvatarUrl *string `validate:"omitempty,http_url,max=2048"`
+}
+
+func (s *ArtistService) CreateArtist(ctx context.Context, params CreateArtistParams) (database.Artist, *utils.AppError) {
+	params.Name = strings.TrimSpace(params.Name)
+	if err := validate.Struct(params); err != nil {
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	artist, err := s.DB.CreateArtist(ctx, database.CreateArtistParams{
+		Name:      params.Name,
+		Bio:       utils.ToNullString(params.Bio),
+		AvatarUrl: utils.ToNullString(params.AvatarUrl),
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.Artist{}, artistNameTakenError()
+		}
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create artist",
+			Err:     err,
+		}
+	}
+	return artist, nil
+}
+
+// GetArtist returns the artist with its counts as seen by viewerID
+func (s *ArtistService) GetArtist(ctx context.Context, artistID, viewerID uuid.UUID) (ArtistWithStats, *utils.AppError) {
+	row, err := s.DB.GetArtistWithStats(ctx, database.GetArtistWithStatsParams{
+		ID:       artistID,
+		ViewerID: viewerID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ArtistWithStats{}, artistNotFoundError()
+	}
+	if err != nil {
+		return ArtistWithStats{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch artist",
+			Err:     err,
+		}
+	}
+	return ArtistWithStats{
+		Artist:        row.Artist,
+		FollowerCount: row.FollowerCount,
+		PlayCount:     row.PlayCount,
+		Following:     row.Following,
+	}, nil
+}
+
+// Artist list sort orders
+const (
+	ArtistSortName      = "name"
+	ArtistSortRelevance = "relevance"
+)
+
+type ListArtistsParams struct {
+	Page     pagination.Params
+	Query    string `validate:"max=100"`
+	Verified *bool
+	// Sort defaults to relevance when searching and name otherwise
+	Sort string `validate:"omitempty,oneof=name relevance"`
+}
+
+type ListArtistsResult struct {
+	Artists []ArtistWithStats
+	// Next is the cursor of the last artist, nil on the last page
+	Next *pagination.Cursor
+}
+
+func (s *ArtistService) ListArtists(ctx context.Context, params ListArtistsParams) (ListArtistsResult, *utils.AppError) {
+	params.Query = strings.TrimSpace(params.Query)
+	if err := validate.Struct(params); err != nil {
+		return ListArtistsResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if params.Sort == "" {
+		params.Sort = ArtistSortName
+		if params.Query != "" {
+			params.Sort = ArtistSortRelevance
+		}
+	}
+	if params.Sort == ArtistSortRelevance && params.Query == "" {
+		return ListArtistsResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Sorting by relevance requires a search query",
+		}
+	}
+
+	var result ListArtistsResult
+	var err error
+	if params.Sort == ArtistSortRelevance {
+		result.Artists, result.Next, err = s.searchArtistsByRelevance(ctx, params)
+	} else {
+		result.Artists, result.Next, err = s.listArtistsByName(ctx, params)
+	}
+	if err != nil {
+		return ListArtistsResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch artists",
+			Err:     err,
+		}
+	}
+	return result, nil
+}
+
+func (s *ArtistService) listArtistsByName(ctx context.Context, params ListArtistsParams) ([]ArtistWithStats, *pagination.Cursor, error) {
+	rows, err := s.DB.ListArtistsByName(ctx, database.ListArtistsByNameParams{
+		Q:         sql.NullString{String: escapeLike(params.Query), Valid: params.Query != ""},
+		Verified:  utils.ToNullBool(params.Verified),
+		CursorKey: params.Page.CursorKey(),
+		CursorID:  params.Page.CursorID(),
+		Lim:       params.Page.FetchLimit(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, more := pagination.Trim(rows, params.Page)
+	artists := make([]ArtistWithStats, len(rows))
+	for i, row := range rows {
+		artists[i] = ArtistWithStats{Artist: row.Artist, FollowerCount: row.FollowerCount, PlayCount: row.PlayCount}
+	}
+	if !more {
+		return artists, nil, nil
+	}
+	last := rows[len(rows)-1].Artist
+	return artists, &pagination.Cursor{Key: strings.ToLower(last.Name), ID: last.ID}, nil
+}
+
+func (s *ArtistService) searchArtistsByRelevance(ctx context.Context, params ListArtistsParams) ([]ArtistWithStats, *pagination.Cursor, error) {
+	arg := database.SearchArtistsByRelevanceParams{
+		Q:        escapeLike(params.Query),
+		Verified: utils.ToNullBool(params.Verified),
+		CursorID: params.Page.CursorID(),
+		Lim:      params.Page.FetchLimit(),
+	}
+	if params.Page.After != nil {
+		rank, err := strconv.ParseFloat(params.Page.After.Key, 32)
+		if err != nil {
+			return nil, nil, err
+		}
+		arg.CursorRank = sql.NullFloat64{Float64: rank, Valid: true}
+	}
+
+	rows, err := s.DB.SearchArtistsByRelevance(ctx, arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, more := pagination.Trim(rows, params.Page)
+	artists := make([]ArtistWithStats, len(rows))
+	for i, row := range rows {
+		artists[i] = ArtistWithStats{Artist: row.Artist, FollowerCount: row.FollowerCount, PlayCount: row.PlayCount}
+	}
+	if !more {
+		return artists, nil, nil
+	}
+	last := rows[len(rows)-1]
+	// Shortest text that parses back to the same float32
+	key := strconv.FormatFloat(float64(last.Rank), 'g', -1, 32)
+	return artists, &pagination.Cursor{Key: key, ID: last.Artist.ID}, nil
+}
+
+// ArtistPatch is a JSON merge patch of an artist.
+// Absent fields are left alone and null clears a field; the name can't be cleared.
+type ArtistPatch struct {
+	Name      patch.Field[string] `json:"name"`
+	Bio       patch.Field[string] `json:"bio"`
+	AvatarUrl patch.Field[string] `json:"avatar_url"`
+}
+
+// artistPatchRules are the validator tags and messages for the patchable fields
+var artistPatchRules = []struct {
+	name, tag, message string
+	field              func(*ArtistPatch) *patch.Field[string]
+}{
+	{"name", "required,max=200", "must be 1 to 200 characters", func(p *ArtistPatch) *patch.Field[string] { return &p.Name }},
+	{"bio", "max=2000", "must be at most 2000 characters", func(p *ArtistPatch) *patch.Field[string] { return &p.Bio }},
+	{"avatar_url", "http_url,max=2048", "must be an http or https URL", func(p *ArtistPatch) *patch.Field[string] { return &p.AvatarUrl }},
+}
+
+// PatchArtist applies a merge patch to an artist. Validation errors are
+// reported per field in AppError.Fields.
+func (s *ArtistService) PatchArtist(ctx context.Context, actor ArtistActor, artistID uuid.UUID, p ArtistPatch) (database.Artist, *utils.AppError) {
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistEditProfile); appErr != nil {
+		return database.Artist{}, appErr
+	}
+	if p.Name.Present() {
+		p.Name.Value = strings.TrimSpace(p.Name.Value)
+	}
+
+	fields := map[string]string{}
+	if p.Name.Null {
+		fields["name"] = "can't be cleared"
+	}
+	for _, rule := range artistPatchRules {
+		f := rule.field(&p)
+		if f.Present() && validate.Var(f.Value, rule.tag) != nil {
+			fields[rule.name] = rule.message
+		}
+	}
+	if len(fields) > 0 {
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+
+	current, appErr := s.getArtist(ctx, artistID)
+	if appErr != nil {
+		return database.Artist{}, appErr
+	}
+
+	artist, err := s.DB.PatchArtist(ctx, database.PatchArtistParams{
+		ID:           artistID,
+		SetName:      p.Name.Set,
+		Name:         p.Name.Value,
+		SetBio:       p.Bio.Set,
+		Bio:          utils.ToNullString(p.Bio.Ptr()),
+		SetAvatarUrl: p.AvatarUrl.Set,
+		AvatarUrl:    utils.ToNullString(p.AvatarUrl.Ptr()),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Artist{}, artistNotFoundError()
+	}
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.Artist{}, artistNameTakenError()
+		}
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update artist",
+			Err:     err,
+		}
+	}
+
+	// Uploaded renditions are no longer referenced once the URL is set by hand
+	if p.AvatarUrl.Set && current.AvatarKey.Valid {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+	return artist, nil
+}
+
+// SetVerified grants or removes the artist's verified badge
+func (s *ArtistService) SetVerified(ctx context.Context, artistID uuid.UUID, verified bool) (database.Artist, *utils.AppError) {
+	artist, err := s.DB.SetArtistVerified(ctx, database.SetArtistVerifiedParams{ID: artistID, Verified: verified})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Artist{}, artistNotFoundError()
+	}
+	if err != nil {
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update artist",
+			Err:     err,
+		}
+	}
+	return artist, nil
+}
+
+// UploadAvatar replaces the artist's avatar with renditions of the uploaded image
+func (s *ArtistService) UploadAvatar(ctx context.Context, actor ArtistActor, artistID uuid.UUID, data []byte) (database.Artist, *utils.AppError) {
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistEditProfile); appErr != nil {
+		return database.Artist{}, appErr
+	}
+	current, appErr := s.getArtist(ctx, artistID)
+	if appErr != nil {
+		return database.Artist{}, appErr
+	}
+
+	key, url, appErr := storeImage(ctx, s.cfg, "avatars/artists/"+artistID.String(), data)
+	if appErr != nil {
+		return database.Artist{}, appErr
+	}
+
+	artist, err := s.DB.SetArtistAvatar(ctx, database.SetArtistAvatarParams{
+		ID:        artistID,
+		AvatarUrl: sql.NullString{String: url, Valid: true},
+		AvatarKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		if current.AvatarKey.String != key {
+			deleteImage(ctx, s.cfg, key)
+		}
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update avatar",
+			Err:     err,
+		}
+	}
+
+	if current.AvatarKey.Valid && current.AvatarKey.String != key {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+	return artist, nil
+}
+
+// DeleteAvatar removes the artist's avatar and any uploaded renditions
+func (s *ArtistService) DeleteAvatar(ctx context.Context, actor ArtistActor, artistID uuid.UUID) (database.Artist, *utils.AppError) {
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistEditProfile); appErr != nil {
+		return database.Artist{}, appErr
+	}
+	current, appErr := s.getArtist(ctx, artistID)
+	if appErr != nil {
+		return database.Artist{}, appErr
+	}
+
+	artist, err := s.DB.SetArtistAvatar(ctx, database.SetArtistAvatarParams{ID: artistID})
+	if err != nil {
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to remove avatar",
+			Err:     err,
+		}
+	}
+
+	if current.AvatarKey.Valid {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+	return artist, nil
+}
+
+// DeleteArtist removes an artist that has no albums or media left.
+// Deleting would cascade to the albums and leave the media uncredited,
+// so the catalog has to be moved or removed first.
+func (s *ArtistService) DeleteArtist(ctx context.Context, artistID uuid.UUID) *utils.AppError {
+	current, appErr := s.getArtist(ctx, artistID)
+	if appErr != nil {
+		return appErr
+	}
+
+	catalog, err := s.DB.CountArtistCatalog(ctx, artistID)
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if catalog.Albums > 0 || catalog.Media > 0 {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Artist still has albums or media; remove or reassign them first",
+		}
+	}
+
+	n, err := s.DB.DeleteArtist(ctx, artistID)
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to delete artist",
+			Err:     err,
+		}
+	}
+	if n == 0 {
+		return artistNotFoundError()
+	}
+
+	if current.AvatarKey.Valid {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+	return nil
+}
+
+// Follow makes the user follow the artist; following twice is a no-op
+func (s *ArtistService) Follow(ctx context.Context, userID, artistID uuid.UUID) *utils.AppError {
+	if _, appErr := s.getArtist(ctx, artistID); appErr != nil {
+		return appErr
+	}
+	if err := s.DB.FollowArtist(ctx, database.FollowArtistParams{FollowerID: userID, ArtistID: artistID}); err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to follow artist",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// Unfollow stops the user following the artist
+func (s *ArtistService) Unfollow(ctx context.Context, userID, artistID uuid.UUID) *utils.AppError {
+	n, err := s.DB.UnfollowArtist(ctx, database.UnfollowArtistParams{FollowerID: userID, ArtistID: artistID})
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to unfollow artist",
+			Err:     err,
+		}
+	}
+	if n == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "You don't follow this artist",
+		}
+	}
+	return nil
+}
+
+func (s *ArtistService) getArtist(ctx context.Context, artistID uuid.UUID) (database.Artist, *utils.AppError) {
+	artist, err := s.DB.GetArtistByID(ctx, artistID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Artist{}, artistNotFoundError()
+	}
+	if err != nil {
+		return database.Artist{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return artist, nil
+}
+
+func artistNotFoundError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusNotFound,
+		Message: "Artist not found",
+	}
+}
+
+func artistNameTakenError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusConflict,
+		Message: "An artist with this name already exists",
+		Fields:  map[string]string{"name": "is already taken"},
+	}
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/mailer"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// verificationMove is a status change of a verification request
+type verificationMove struct {
+	from, to database.VerificationStatus
+}
+
+// verificationMoves are the allowed status changes. Admins review; members
+// answer requests that need more information. Approved and rejected are final.
+var verificationMoves = map[verificationMove]struct {
+	admin        bool
+	needsComment bool
+}{
+	{database.VerificationStatusPending, database.VerificationStatusNeedsInfo}:  {admin: true, needsComment: true},
+	{database.VerificationStatusPending, database.VerificationStatusApproved}:   {admin: true},
+	{database.VerificationStatusPending, database.VerificationStatusRejected}:   {admin: true, needsComment: true},
+	{database.VerificationStatusNeedsInfo, database.VerificationStatusPending}:  {admin: false},
+	{database.VerificationStatusNeedsInfo, database.VerificationStatusApproved}: {admin: true},
+	{database.VerificationStatusNeedsInfo, database.VerificationStatusRejected}: {admin: true, needsComment: true},
+}
+
+func verificationOpen(status database.VerificationStatus) bool {
+	return status == database.VerificationStatusPending || status == database.VerificationStatusNeedsInfo
+}
+
+type SubmitVerificationParams struct {
+	EvidenceLinks []string `validate:"required,min=1,max=10,dive,http_url,max=2048"`
+	Note          *string  `validate:"omitempty,max=2000"`
+}
+
+// SubmitVerification opens a verification request for the artist
+func (s *ArtistService) SubmitVerification(ctx context.Context, actor ArtistActor, artistID uuid.UUID, params SubmitVerificationParams) (database.ArtistVerificationRequest, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, artistID, ArtistEditProfile); appErr != nil {
+		return database.ArtistVerificationRequest{}, appErr
+	}
+	artist, appErr := s.getArtist(ctx, artistID)
+	if appErr != nil {
+		return database.ArtistVerificationRequest{}, appErr
+	}
+	if artist.Verified {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Artist is already verified",
+		}
+	}
+
+	var req database.ArtistVerificationRequest
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		var err error
+		req, err = q.CreateVerificationRequest(ctx, database.CreateVerificationRequestParams{
+			ArtistID:      artistID,
+			SubmittedBy:   uuid.NullUUID{UUID: actor.UserID, Valid: true},
+			EvidenceLinks: params.EvidenceLinks,
+			Note:          utils.ToNullString(params.Note),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.AddVerificationEvent(ctx, database.AddVerificationEventParams{
+			RequestID: req.ID,
+			ActorID:   uuid.NullUUID{UUID: actor.UserID, Valid: true},
+			ToStatus:  database.NullVerificationStatus{VerificationStatus: req.Status, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.ArtistVerificationRequest{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Artist already has an open verification request",
+			}
+		}
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to submit verification request",
+			Err:     err,
+		}
+	}
+	return req, nil
+}
+
+// VerificationRequest is a verification request with its history
+type VerificationRequest struct {
+	Request database.ArtistVerificationRequest
+	Events  []database.ListVerificationEventsRow
+}
+
+// GetVerificationRequest returns a request and its history to admins and the artist's members
+func (s *ArtistService) GetVerificationRequest(ctx context.Context, actor ArtistActor, requestID uuid.UUID) (VerificationRequest, *utils.AppError) {
+	req, appErr := s.getVerificationRequest(ctx, requestID)
+	if appErr != nil {
+		return VerificationRequest{}, appErr
+	}
+	if appErr := requireArtistMember(ctx, s.DB, actor, req.ArtistID); appErr != nil {
+		return VerificationRequest{}, appErr
+	}
+
+	events, err := s.DB.ListVerificationEvents(ctx, requestID)
+	if err != nil {
+		return VerificationRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch request history",
+			Err:     err,
+		}
+	}
+	return VerificationRequest{Request: req, Events: events}, nil
+}
+
+// ListArtistVerificationRequests returns the artist's requests, newest first
+func (s *ArtistService) ListArtistVerificationRequests(ctx context.Context, actor ArtistActor, artistID uuid.UUID) ([]database.ArtistVerificationRequest, *utils.AppError) {
+	if appErr := requireArtistMember(ctx, s.DB, actor, artistID); appErr != nil {
+		return nil, appErr
+	}
+	reqs, err := s.DB.ListArtistVerificationRequests(ctx, artistID)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch verification requests",
+			Err:     err,
+		}
+	}
+	return reqs, nil
+}
+
+type ListVerificationQueueResult struct {
+	Requests []database.ListVerificationQueueRow
+	// Next is the cursor of the last request, nil on the last page
+	Next *pagination.Cursor
+}
+
+// ListVerificationQueue returns the requests in a status, least recently updated first
+func (s *ArtistService) ListVerificationQueue(ctx context.Context, status string, page pagination.Params) (ListVerificationQueueResult, *utils.AppError) {
+	if status == "" {
+		status = string(database.VerificationStatusPending)
+	}
+	if validate.Var(status, "oneof=pending needs_info approved rejected") != nil {
+		return ListVerificationQueueResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "status must be pending, needs_info, approved or rejected",
+		}
+	}
+
+	rows, err := s.DB.ListVerificationQueue(ctx, database.ListVerificationQueueParams{
+		Status:          database.VerificationStatus(status),
+		CursorCreatedAt: page.CursorCreatedAt(),
+		CursorID:        page.CursorID(),
+		Lim:             page.FetchLimit(),
+	})
+	if err != nil {
+		return ListVerificationQueueResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch verification requests",
+			Err:     err,
+		}
+	}
+	rows, more := pagination.Trim(rows, page)
+	result := ListVerificationQueueResult{Requests: rows}
+	if more {
+		last := rows[len(rows)-1].ArtistVerificationRequest
+		// The queue is ordered by updated_at; the cursor's CreatedAt carries it
+		result.Next = &pagination.Cursor{CreatedAt: last.UpdatedAt, ID: last.ID}
+	}
+	return result, nil
+}
+
+type VerificationTransitionParams struct {
+	Status  string  `validate:"required,oneof=pending needs_info approved rejected"`
+	Comment *string `validate:"omitempty,max=2000"`
+	// EvidenceLinks replaces the links when members answer a needs_info request
+	EvidenceLinks []string `validate:"omitempty,max=10,dive,http_url,max=2048"`
+}
+
+// TransitionVerification moves a request to another status. Approving marks
+// the artist verified; the submitter is emailed about every admin decision.
+func (s *ArtistService) TransitionVerification(ctx context.Context, actor ArtistActor, requestID uuid.UUID, params VerificationTransitionParams) (database.ArtistVerificationRequest, *utils.AppError) {
+	if params.Comment != nil {
+		trimmed := strings.TrimSpace(*params.Comment)
+		params.Comment = &trimmed
+		if trimmed == "" {
+			params.Comment = nil
+		}
+	}
+	if err := validate.Struct(params); err != nil {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	current, appErr := s.getVerificationRequest(ctx, requestID)
+	if appErr != nil {
+		return database.ArtistVerificationRequest{}, appErr
+	}
+	to := database.VerificationStatus(params.Status)
+	rule, ok := verificationMoves[verificationMove{current.Status, to}]
+	if !ok {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("A %s request can't become %s", current.Status, to),
+		}
+	}
+	if rule.admin && !actor.Admin {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Admin access required",
+		}
+	}
+	if !rule.admin {
+		if appErr := authorizeArtist(ctx, s.DB, actor, current.ArtistID, ArtistEditProfile); appErr != nil {
+			return database.ArtistVerificationRequest{}, appErr
+		}
+	}
+	if rule.needsComment && params.Comment == nil {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  map[string]string{"comment": "is required to tell the artist why"},
+		}
+	}
+	if len(params.EvidenceLinks) > 0 && rule.admin {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Only the artist can change the evidence links",
+		}
+	}
+
+	var req database.ArtistVerificationRequest
+	errMoved := errors.New("request changed status")
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		locked, err := q.GetVerificationRequestForUpdate(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != current.Status {
+			return errMoved
+		}
+		req, err = q.UpdateVerificationRequestStatus(ctx, database.UpdateVerificationRequestStatusParams{
+			ID:            requestID,
+			Status:        to,
+			EvidenceLinks: params.EvidenceLinks,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := q.AddVerificationEvent(ctx, database.AddVerificationEventParams{
+			RequestID:  requestID,
+			ActorID:    uuid.NullUUID{UUID: actor.UserID, Valid: true},
+			FromStatus: database.NullVerificationStatus{VerificationStatus: current.Status, Valid: true},
+			ToStatus:   database.NullVerificationStatus{VerificationStatus: to, Valid: true},
+			Comment:    utils.ToNullString(params.Comment),
+		}); err != nil {
+			return err
+		}
+		if to != database.VerificationStatusApproved {
+			return nil
+		}
+		_, err = q.SetArtistVerified(ctx, database.SetArtistVerifiedParams{ID: req.ArtistID, Verified: true})
+		return err
+	})
+	if errors.Is(err, errMoved) {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Request was updated in the meantime; reload it and try again",
+		}
+	}
+	if err != nil {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update verification request",
+			Err:     err,
+		}
+	}
+
+	if rule.admin {
+		s.notifyVerificationSubmitter(ctx, req, actor.UserID, params.Comment)
+	}
+	return req, nil
+}
+
+// CommentOnVerification adds a comment to an open request without changing its status
+func (s *ArtistService) CommentOnVerification(ctx context.Context, actor ArtistActor, requestID uuid.UUID, comment string) (database.ArtistVerificationEvent, *utils.AppError) {
+	comment = strings.TrimSpace(comment)
+	if validate.Var(comment, "required,max=2000") != nil {
+		return database.ArtistVerificationEvent{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  map[string]string{"comment": "must be 1 to 2000 characters"},
+		}
+	}
+
+	req, appErr := s.getVerificationRequest(ctx, requestID)
+	if appErr != nil {
+		return database.ArtistVerificationEvent{}, appErr
+	}
+	if appErr := requireArtistMember(ctx, s.DB, actor, req.ArtistID); appErr != nil {
+		return database.ArtistVerificationEvent{}, appErr
+	}
+	if !verificationOpen(req.Status) {
+		return database.ArtistVerificationEvent{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Request is closed",
+		}
+	}
+
+	event, err := s.DB.AddVerificationEvent(ctx, database.AddVerificationEventParams{
+		RequestID: requestID,
+		ActorID:   uuid.NullUUID{UUID: actor.UserID, Valid: true},
+		Comment:   sql.NullString{String: comment, Valid: true},
+	})
+	if err != nil {
+		return database.ArtistVerificationEvent{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to add comment",
+			Err:     err,
+		}
+	}
+
+	if actor.Admin {
+		s.notifyVerificationSubmitter(ctx, req, actor.UserID, &comment)
+	}
+	return event, nil
+}
+
+// notifyVerificationSubmitter emails the member who submitted the request about a review.
+// Failures are only logged, the request's history shows the update either way.
+func (s *ArtistService) notifyVerificationSubmitter(ctx context.Context, req database.ArtistVerificationRequest, actorID uuid.UUID, comment *string) {
+	if !req.SubmittedBy.Valid || req.SubmittedBy.UUID == actorID {
+		return
+	}
+	user, err := s.DB.GetUserById(ctx, req.SubmittedBy.UUID)
+	if err != nil {
+		logger.Error(ctx, "notifyVerificationSubmitter: failed to load submitter", err, "request_id", req.ID)
+		return
+	}
+	artist, err := s.DB.GetArtistByID(ctx, req.ArtistID)
+	if err != nil {
+		logger.Error(ctx, "notifyVerificationSubmitter: failed to load artist", err, "request_id", req.ID)
+		return
+	}
+
+	var subject, body string
+	switch req.Status {
+	case database.VerificationStatusNeedsInfo:
+		subject = "More information needed to verify " + artist.Name
+		body = "The reviewers need more information before they can verify " + artist.Name + "."
+	case database.VerificationStatusApproved:
+		subject = artist.Name + " is now verified"
+		body = "Your verification request was approved and " + artist.Name + " now shows the verified badge."
+	case database.VerificationStatusRejected:
+		subject = "Verification of " + artist.Name + " was declined"
+		body = "Your verification request for " + artist.Name + " was declined."
+	default:
+		subject = "New comment on the verification of " + artist.Name
+		body = "A reviewer commented on your verification request for " + artist.Name + "."
+	}
+	if comment != nil {
+		body += "\n\n" + *comment
+	}
+	body += fmt.Sprintf("\n\n%s/artists/%s/verification", s.cfg.FrontendURL, artist.ID)
+
+	if err := s.cfg.Mailer.Send(ctx, mailer.Message{To: user.Email, Subject: subject, Body: body}); err != nil {
+		logger.Error(ctx, "notifyVerificationSubmitter: failed to send email", err, "request_id", req.ID)
+	}
+}
+
+func (s *ArtistService) getVerificationRequest(ctx context.Context, requestID uuid.UUID) (database.ArtistVerificationRequest, *utils.AppError) {
+	req, err := s.DB.GetVerificationRequest(ctx, requestID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Verification request not found",
+		}
+	}
+	if err != nil {
+		return database.ArtistVerificationRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return req, nil
+}
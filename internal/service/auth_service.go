@@ -5,14 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/techies/streamify/internal/app"
 	"github.com/techies/streamify/internal/database"
 	"github.com/techies/streamify/internal/handler/token"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/password"
 	"github.com/techies/streamify/internal/utils"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
@@ -28,12 +30,22 @@ func NewAuthService(db *database.Queries, cfg *app.AppConfig) *AuthService {
 }
 
 type RegisterParams struct {
-	Username string `validate:"required,min=3,max=30"`
-	Email    string `validate:"required,email"`
-	Password string `validate:"required,min=8"`
+	Username       string `validate:"required,min=3,max=30"`
+	Email          string `validate:"required,email"`
+	Password       string `validate:"required,min=8"`
+	InvitationCode string
+	// AcceptedDocuments are the legal document IDs the user agreed to
+	AcceptedDocuments []uuid.UUID
+	MarketingConsent  bool
+	// BirthDate (YYYY-MM-DD) and Country (ISO 3166-1 alpha-2) drive age gating
+	BirthDate string `validate:"omitempty,datetime=2006-01-02"`
+	Country   string `validate:"omitempty,iso3166_1_alpha2"`
+	IP        string
+	UserAgent string
 }
 
 func (s *AuthService) Register(ctx context.Context, params RegisterParams) (database.User, *utils.AppError) {
+	params.Country = strings.ToUpper(strings.TrimSpace(params.Country))
 	if err := validate.Struct(params); err != nil {
 		return database.User{}, &utils.AppError{
 			Code:    http.StatusBadRequest,
@@ -42,8 +54,27 @@ func (s *AuthService) Register(ctx context.Context, params RegisterParams) (data
 		}
 	}
 
+	if err := s.cfg.PasswordPolicy.Check(params.Password); err != nil {
+		if errors.Is(err, password.ErrCommonPassword) || errors.Is(err, password.ErrBreachedPassword) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Password is not allowed: " + err.Error(),
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to check password",
+			Err:     err,
+		}
+	}
+
 	email := utils.NormalizeEmail(params.Email)
 
+	invitation, appErr := s.checkRegistrationPolicy(ctx, email, params.InvitationCode)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
 	// Check availability
 	exists, err := s.DB.GetUserByEmail(ctx, email)
 	if err == nil && exists.ID != uuid.Nil {
@@ -53,7 +84,29 @@ func (s *AuthService) Register(ctx context.Context, params RegisterParams) (data
 		}
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
+	if appErr := checkUsername(ctx, s.DB, s.cfg, params.Username, uuid.Nil); appErr != nil {
+		return database.User{}, appErr
+	}
+
+	var birthDate time.Time
+	if params.BirthDate != "" {
+		if birthDate, appErr = parseBirthDate(params.BirthDate); appErr != nil {
+			return database.User{}, appErr
+		}
+		if !s.cfg.AgeRules.CanRegister(birthDate, params.Country, time.Now()) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "You are not old enough to create an account",
+			}
+		}
+	}
+
+	requiredDocs, appErr := checkRequiredConsents(ctx, s.DB, params.AcceptedDocuments)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	hashedPassword, err := s.cfg.Passwords.Hash(params.Password)
 	if err != nil {
 		return database.User{}, &utils.AppError{
 			Code:    http.StatusInternalServerError,
@@ -65,15 +118,93 @@ func (s *AuthService) Register(ctx context.Context, params RegisterParams) (data
 	vToken, _ := token.GenerateSecureToken(32)
 	vExpires := time.Now().Add(24 * time.Hour)
 
-	user, err := s.DB.CreateUser(ctx, database.CreateUserParams{
-		Username:              params.Username,
-		Email:                 email,
-		PasswordHash:          string(hashedPassword),
-		VerificationToken:     sql.NullString{String: vToken, Valid: true},
-		VerificationExpiresAt: sql.NullTime{Time: vExpires, Valid: true},
+	var user database.User
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if invitation != nil {
+			if err := consumeInvitation(ctx, q, invitation.ID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		user, err = q.CreateUser(ctx, database.CreateUserParams{
+			Username:              params.Username,
+			Email:                 email,
+			PasswordHash:          hashedPassword,
+			VerificationToken:     sql.NullString{String: vToken, Valid: true},
+			VerificationExpiresAt: sql.NullTime{Time: vExpires, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := createDefaultProfile(ctx, q, user); err != nil {
+			return err
+		}
+
+		// Pre-provisioned accounts get the role they were invited with
+		if invitation != nil && invitation.Role.Valid && invitation.Role.UserRole != user.Role {
+			if err := q.UpdateUserRole(ctx, database.UpdateUserRoleParams{ID: user.ID, Role: invitation.Role.UserRole}); err != nil {
+				return err
+			}
+			user.Role = invitation.Role.UserRole
+		}
+
+		if params.BirthDate != "" || params.Country != "" {
+			if err := q.UpsertUserProfile(ctx, database.UpsertUserProfileParams{
+				UserID:    user.ID,
+				Country:   sql.NullString{String: params.Country, Valid: params.Country != ""},
+				BirthDate: sql.NullTime{Time: birthDate, Valid: params.BirthDate != ""},
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, doc := range requiredDocs {
+			if _, err := createConsent(ctx, q, GiveConsentParams{
+				UserID:     user.ID,
+				DocumentID: doc.ID,
+				Kind:       doc.Kind,
+				IP:         params.IP,
+				UserAgent:  params.UserAgent,
+			}); err != nil {
+				return err
+			}
+		}
+		if params.MarketingConsent {
+			if _, err := createConsent(ctx, q, GiveConsentParams{
+				UserID:    user.ID,
+				Kind:      ConsentMarketing,
+				IP:        params.IP,
+				UserAgent: params.UserAgent,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if invitation == nil {
+			return nil
+		}
+		return q.CreateInvitationRedemption(ctx, database.CreateInvitationRedemptionParams{
+			InvitationID: invitation.ID,
+			UserID:       user.ID,
+		})
 	})
 
 	if err != nil {
+		if errors.Is(err, errInvitationUnavailable) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "Invitation is no longer valid",
+			}
+		}
+		// Lost a race against a concurrent registration
+		if utils.IsUniqueViolation(err) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Email or username already registered",
+			}
+		}
 		return database.User{}, &utils.AppError{
 			Code:    http.StatusInternalServerError,
 			Message: "Registration failed",
@@ -121,27 +252,40 @@ func (s *AuthService) Login(ctx context.Context, params LoginParams) (LoginResul
 		}
 	}
 
-	if user.IsLocked {
+	if !user.IsVerified {
 		return LoginResult{}, &utils.AppError{
 			Code:    http.StatusUnauthorized,
-			Message: "User account is locked",
+			Message: "Please verify your email before logging in",
 		}
 	}
 
-	if !user.IsVerified {
+	match, needsRehash, err := s.cfg.Passwords.Verify(params.Password, user.PasswordHash)
+	if err != nil {
 		return LoginResult{}, &utils.AppError{
-			Code:    http.StatusUnauthorized,
-			Message: "Please verify your email before logging in",
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to verify password",
+			Err:     err,
 		}
 	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(params.Password)); err != nil {
+	if !match {
 		return LoginResult{}, &utils.AppError{
 			Code:    http.StatusUnauthorized,
 			Message: "Invalid email or password",
 		}
 	}
 
+	// Checked after the password so the suspension message is only shown to the account owner
+	if user.IsLocked {
+		if appErr := s.checkSuspension(ctx, user); appErr != nil {
+			return LoginResult{}, appErr
+		}
+	}
+
+	// Upgrade hashes made with an older algorithm or weaker parameters while we have the password
+	if needsRehash {
+		s.rehashPassword(ctx, user.ID, params.Password)
+	}
+
 	refreshToken, _ := token.GenerateSecureToken(token.RefreshTokenLen)
 	session, err := s.DB.CreateSession(ctx, database.CreateSessionParams{
 		UserID:       user.ID,
@@ -158,7 +302,7 @@ func (s *AuthService) Login(ctx context.Context, params LoginParams) (LoginResul
 		}
 	}
 
-	accessToken, err := utils.GenerateToken(user.ID, session.ID, token.AccessTokenTTL, s.cfg.JWTSecret, user.Role, user.FirstName.String, user.LastName.String, user.PhoneNumber.String, user.Email)
+	accessToken, err := utils.GenerateToken(user.ID, session.ID, token.AccessTokenTTL, s.cfg.JWTSecret, user.Role, user.FirstName.String, user.LastName.String, user.PhoneNumber.String, user.Email, uuid.Nil, uuid.Nil)
 	if err != nil {
 		return LoginResult{}, &utils.AppError{
 			Code:    http.StatusInternalServerError,
@@ -173,3 +317,19 @@ func (s *AuthService) Login(ctx context.Context, params LoginParams) (LoginResul
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+// rehashPassword replaces the stored hash with one made with the current settings.
+// Failing is not fatal: the old hash keeps working and is retried on next login.
+func (s *AuthService) rehashPassword(ctx context.Context, userID uuid.UUID, plain string) {
+	hash, err := s.cfg.Passwords.Hash(plain)
+	if err != nil {
+		logger.Warn(ctx, "Login: failed to rehash password", "user_id", userID, "error", err)
+		return
+	}
+	if err := s.DB.UpdateUserPassword(ctx, database.UpdateUserPasswordParams{
+		ID:           userID,
+		PasswordHash: hash,
+	}); err != nil {
+		logger.Warn(ctx, "Login: failed to store rehashed password", "user_id", userID, "error", err)
+	}
+}
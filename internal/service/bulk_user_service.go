@@ -0,0 +1,536 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/mailer"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// Bulk job actions
+const (
+	BulkActionLock    = "lock"
+	BulkActionUnlock  = "unlock"
+	BulkActionSetRole = "set_role"
+	BulkActionDelete  = "delete"
+	BulkActionLogout  = "logout"
+	BulkActionImport  = "import"
+)
+
+const (
+	// MaxBulkTargets bounds how many users or CSV rows one job can cover
+	MaxBulkTargets = 10000
+	// ImportInvitationTTL is how long invitations sent by CSV import stay valid
+	ImportInvitationTTL = 14 * 24 * time.Hour
+	// bulkJobStaleAfter is how long a running job can go without a heartbeat before another runner resumes it
+	bulkJobStaleAfter = 5 * time.Minute
+	// bulkJobBatchSize is how many items are processed between heartbeats
+	bulkJobBatchSize = 100
+	// exportPageSize is how many users are read at a time while exporting
+	exportPageSize = 500
+)
+
+// UserFilter selects users like the admin user list does
+type UserFilter struct {
+	Query       string `validate:"max=100"`
+	PhoneNumber string `validate:"max=20"`
+	Role        string `validate:"omitempty,oneof=user customer admin owner"`
+	Status      string `validate:"max=20"`
+	Verified    *bool
+	Locked      *bool
+}
+
+func (f UserFilter) params() database.GetUsersParams {
+	return database.GetUsersParams{
+		Q:                 sql.NullString{String: escapeLike(f.Query), Valid: f.Query != ""},
+		SearchPhoneNumber: sql.NullString{String: escapeLike(f.PhoneNumber), Valid: f.PhoneNumber != ""},
+		Role:              database.NullUserRole{UserRole: database.UserRole(f.Role), Valid: f.Role != ""},
+		Status:            sql.NullString{String: f.Status, Valid: f.Status != ""},
+		IsVerified:        utils.ToNullBool(f.Verified),
+		IsLocked:          utils.ToNullBool(f.Locked),
+	}
+}
+
+// eachUser calls fn for every user matching the filter, newest first
+func (s *UserService) eachUser(ctx context.Context, f UserFilter, fn func(database.User) error) error {
+	arg := f.params()
+	arg.Limit = exportPageSize
+	for {
+		users, err := s.DB.GetUsers(ctx, arg)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			if err := fn(u); err != nil {
+				return err
+			}
+		}
+		if len(users) < exportPageSize {
+			return nil
+		}
+		last := users[len(users)-1]
+		arg.CursorCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+		arg.CursorID = uuid.NullUUID{UUID: last.ID, Valid: true}
+	}
+}
+
+// ExportUsers calls fn for every user matching the filter, newest first
+func (s *UserService) ExportUsers(ctx context.Context, f UserFilter, fn func(database.User) error) *utils.AppError {
+	f.Query = strings.TrimSpace(f.Query)
+	if err := validate.Struct(f); err != nil {
+		return &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if err := s.eachUser(ctx, f, fn); err != nil {
+		return bulkError("Failed to export users", err)
+	}
+	return nil
+}
+
+type CreateBulkUserJobParams struct {
+	CreatedBy uuid.UUID `validate:"required"`
+	Action    string    `validate:"required,oneof=lock unlock set_role delete logout"`
+	// Role is the role set_role assigns; owner can only be handed over by ownership transfer
+	Role string `validate:"required_if=Action set_role,omitempty,oneof=user customer admin"`
+	// Either UserIDs or Filter selects the users
+	UserIDs []uuid.UUID `validate:"max=10000"`
+	Filter  *UserFilter
+	DryRun  bool
+}
+
+// CreateBulkUserJob queues an action over a list of users or everyone matching a filter.
+// Filters are resolved now, so users who match later aren't affected.
+func (s *UserService) CreateBulkUserJob(ctx context.Context, params CreateBulkUserJobParams) (database.GetBulkUserJobRow, *utils.AppError) {
+	if params.Filter != nil {
+		params.Filter.Query = strings.TrimSpace(params.Filter.Query)
+	}
+	if err := validate.Struct(params); err != nil {
+		return database.GetBulkUserJobRow{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if (len(params.UserIDs) > 0) == (params.Filter != nil) {
+		return database.GetBulkUserJobRow{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Provide either user_ids or filter",
+		}
+	}
+
+	targets := dedupeIDs(params.UserIDs)
+	if params.Filter != nil {
+		if err := validate.Struct(params.Filter); err != nil {
+			return database.GetBulkUserJobRow{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Err:     err,
+			}
+		}
+		errTooMany := errors.New("too many users")
+		err := s.eachUser(ctx, *params.Filter, func(u database.User) error {
+			if len(targets) == MaxBulkTargets {
+				return errTooMany
+			}
+			targets = append(targets, u.ID)
+			return nil
+		})
+		if errors.Is(err, errTooMany) {
+			return database.GetBulkUserJobRow{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("The filter matches more than %d users, narrow it down", MaxBulkTargets),
+			}
+		}
+		if err != nil {
+			return database.GetBulkUserJobRow{}, bulkError("Failed to resolve filter", err)
+		}
+	}
+	if len(targets) == 0 {
+		return database.GetBulkUserJobRow{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "No users match",
+		}
+	}
+
+	var job database.BulkUserJob
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		var err error
+		job, err = q.CreateBulkUserJob(ctx, database.CreateBulkUserJobParams{
+			Action:    params.Action,
+			Role:      database.NullUserRole{UserRole: database.UserRole(params.Role), Valid: params.Role != ""},
+			DryRun:    params.DryRun,
+			CreatedBy: uuid.NullUUID{UUID: params.CreatedBy, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		return q.AddBulkUserJobTargets(ctx, database.AddBulkUserJobTargetsParams{JobID: job.ID, UserIds: targets})
+	})
+	if err != nil {
+		return database.GetBulkUserJobRow{}, bulkError("Failed to create job", err)
+	}
+	return s.GetBulkUserJob(ctx, job.ID)
+}
+
+// ImportRow is one account to pre-provision from a CSV import
+type ImportRow struct {
+	Email string
+	Role  string
+	Note  string
+}
+
+// CreateImportJob queues invitations for pre-provisioned accounts. Rows are
+// validated when the job runs so every problem shows up in the item results.
+func (s *UserService) CreateImportJob(ctx context.Context, createdBy uuid.UUID, rows []ImportRow, dryRun bool) (database.GetBulkUserJobRow, *utils.AppError) {
+	if len(rows) == 0 {
+		return database.GetBulkUserJobRow{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "The file has no rows",
+		}
+	}
+	if len(rows) > MaxBulkTargets {
+		return database.GetBulkUserJobRow{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Imports are limited to %d rows", MaxBulkTargets),
+		}
+	}
+
+	var job database.BulkUserJob
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		var err error
+		job, err = q.CreateBulkUserJob(ctx, database.CreateBulkUserJobParams{
+			Action:    BulkActionImport,
+			DryRun:    dryRun,
+			CreatedBy: uuid.NullUUID{UUID: createdBy, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		for i, row := range rows {
+			role := strings.ToLower(strings.TrimSpace(row.Role))
+			if err := q.AddBulkUserJobImportRow(ctx, database.AddBulkUserJobImportRowParams{
+				JobID:    job.ID,
+				Position: int32(i + 1),
+				Email:    sql.NullString{String: strings.TrimSpace(row.Email), Valid: true},
+				Role:     database.NullUserRole{UserRole: database.UserRole(role), Valid: role != ""},
+				Note:     sql.NullString{String: row.Note, Valid: row.Note != ""},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return database.GetBulkUserJobRow{}, bulkError("Failed to create job", err)
+	}
+	return s.GetBulkUserJob(ctx, job.ID)
+}
+
+func (s *UserService) GetBulkUserJob(ctx context.Context, id uuid.UUID) (database.GetBulkUserJobRow, *utils.AppError) {
+	job, err := s.DB.GetBulkUserJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.GetBulkUserJobRow{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Job not found",
+		}
+	}
+	if err != nil {
+		return database.GetBulkUserJobRow{}, bulkError("Database error", err)
+	}
+	return job, nil
+}
+
+func (s *UserService) ListBulkUserJobs(ctx context.Context, limit, offset int32) ([]database.ListBulkUserJobsRow, *utils.AppError) {
+	jobs, err := s.DB.ListBulkUserJobs(ctx, database.ListBulkUserJobsParams{Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, bulkError("Failed to fetch jobs", err)
+	}
+	return jobs, nil
+}
+
+// ListBulkUserJobItems returns the per-user results of a job, optionally only those with status
+func (s *UserService) ListBulkUserJobItems(ctx context.Context, jobID uuid.UUID, status string, limit, offset int32) ([]database.BulkUserJobItem, *utils.AppError) {
+	if err := validate.Var(status, "omitempty,oneof=pending done skipped failed"); err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "status must be one of pending, done, skipped, failed",
+		}
+	}
+	if _, appErr := s.GetBulkUserJob(ctx, jobID); appErr != nil {
+		return nil, appErr
+	}
+	items, err := s.DB.ListBulkUserJobItems(ctx, database.ListBulkUserJobItemsParams{
+		JobID:  jobID,
+		Status: database.NullBulkItemStatus{BulkItemStatus: database.BulkItemStatus(status), Valid: status != ""},
+		Lim:    limit,
+		Off:    offset,
+	})
+	if err != nil {
+		return nil, bulkError("Failed to fetch job results", err)
+	}
+	return items, nil
+}
+
+// RunBulkUserJobs works through queued jobs until none are left. Jobs whose
+// runner died are picked up again and continue with their unprocessed items.
+func (s *UserService) RunBulkUserJobs(ctx context.Context) error {
+	for {
+		job, err := s.DB.ClaimBulkUserJob(ctx, time.Now().Add(-bulkJobStaleAfter))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logger.Info(ctx, "Bulk user job started", "job_id", job.ID, "action", job.Action, "dry_run", job.DryRun)
+		if err := s.runBulkUserJob(ctx, job); err != nil {
+			return err
+		}
+		logger.Info(ctx, "Bulk user job completed", "job_id", job.ID)
+	}
+}
+
+func (s *UserService) runBulkUserJob(ctx context.Context, job database.BulkUserJob) error {
+	for {
+		items, err := s.DB.ListPendingBulkUserJobItems(ctx, database.ListPendingBulkUserJobItemsParams{
+			JobID: job.ID,
+			Limit: bulkJobBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return s.DB.CompleteBulkUserJob(ctx, job.ID)
+		}
+
+		for _, item := range items {
+			var status database.BulkItemStatus
+			var message string
+			if job.Action == BulkActionImport {
+				status, message = s.importItem(ctx, job, item)
+			} else {
+				status, message = s.bulkItem(ctx, job, item)
+			}
+			if err := s.DB.FinishBulkUserJobItem(ctx, database.FinishBulkUserJobItemParams{
+				JobID:    job.ID,
+				Position: item.Position,
+				Status:   status,
+				Message:  sql.NullString{String: message, Valid: message != ""},
+			}); err != nil {
+				return err
+			}
+		}
+		if err := s.DB.TouchBulkUserJob(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+}
+
+// bulkItem applies the job's action to one user and describes the outcome
+func (s *UserService) bulkItem(ctx context.Context, job database.BulkUserJob, item database.BulkUserJobItem) (database.BulkItemStatus, string) {
+	if !item.UserID.Valid {
+		return database.BulkItemStatusFailed, "User not found"
+	}
+	user, err := s.DB.GetUserById(ctx, item.UserID.UUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.BulkItemStatusFailed, "User not found"
+	}
+	if err != nil {
+		return bulkItemError(ctx, job, err)
+	}
+	if user.Role == database.UserRoleOwner {
+		return database.BulkItemStatusSkipped, "The owner account can't be changed in bulk"
+	}
+	if job.CreatedBy.Valid && user.ID == job.CreatedBy.UUID {
+		return database.BulkItemStatusSkipped, "Your own account is left unchanged"
+	}
+	if user.Status == "deleted" && job.Action != BulkActionDelete {
+		return database.BulkItemStatusSkipped, "Account is deleted"
+	}
+
+	var skip, change string
+	var apply func() *utils.AppError
+	switch job.Action {
+	case BulkActionLock:
+		if user.IsLocked {
+			skip = "Already locked"
+		}
+		change = "locked and signed out"
+		apply = func() *utils.AppError {
+			_, appErr := s.SuspendUser(ctx, SuspendUserParams{
+				UserID:      user.ID,
+				SuspendedBy: job.CreatedBy.UUID,
+				ReasonCode:  "other",
+				Note:        "Bulk job " + job.ID.String(),
+			})
+			return appErr
+		}
+	case BulkActionUnlock:
+		if !user.IsLocked {
+			skip = "Not locked"
+		}
+		change = "unlocked"
+		apply = func() *utils.AppError { return s.LiftSuspension(ctx, user.ID, job.CreatedBy.UUID) }
+	case BulkActionSetRole:
+		if user.Role == job.Role.UserRole {
+			skip = "Already has role " + string(user.Role)
+		}
+		change = fmt.Sprintf("role changed from %s to %s", user.Role, job.Role.UserRole)
+		apply = func() *utils.AppError { return s.UpdateUserRole(ctx, user.ID, job.Role.UserRole) }
+	case BulkActionDelete:
+		if user.Status == "deleted" {
+			skip = "Already deleted"
+		}
+		change = "deleted and signed out"
+		apply = func() *utils.AppError {
+			err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+				if err := q.SoftDeleteUser(ctx, user.ID); err != nil {
+					return err
+				}
+				return q.DeleteAllUserSessions(ctx, user.ID)
+			})
+			if err != nil {
+				return bulkError("Failed to delete user", err)
+			}
+			return nil
+		}
+	case BulkActionLogout:
+		change = "signed out of all sessions"
+		apply = func() *utils.AppError {
+			if err := s.DB.DeleteAllUserSessions(ctx, user.ID); err != nil {
+				return bulkError("Failed to sign out user", err)
+			}
+			return nil
+		}
+	default:
+		return database.BulkItemStatusFailed, "Unknown action " + job.Action
+	}
+
+	if skip != "" {
+		return database.BulkItemStatusSkipped, skip
+	}
+	if job.DryRun {
+		return database.BulkItemStatusDone, "Would be " + change
+	}
+	if appErr := apply(); appErr != nil {
+		if appErr.Err != nil {
+			logger.Error(ctx, "Bulk user job item failed", appErr.Err, "job_id", job.ID, "user_id", user.ID)
+		}
+		return database.BulkItemStatusFailed, appErr.Message
+	}
+	return database.BulkItemStatusDone, capitalize(change)
+}
+
+// importItem invites one CSV row and describes the outcome
+func (s *UserService) importItem(ctx context.Context, job database.BulkUserJob, item database.BulkUserJobItem) (database.BulkItemStatus, string) {
+	email := utils.NormalizeEmail(item.Email.String)
+	if validate.Var(email, "required,email,max=100") != nil {
+		return database.BulkItemStatusFailed, "Invalid email address"
+	}
+	role := database.UserRoleUser
+	if item.Role.Valid {
+		role = item.Role.UserRole
+	}
+	switch role {
+	case database.UserRoleUser, database.UserRoleCustomer, database.UserRoleAdmin:
+	default:
+		return database.BulkItemStatusFailed, "Role must be one of user, customer, admin"
+	}
+	if err := s.cfg.Registration.CheckEmail(email); err != nil {
+		return database.BulkItemStatusFailed, "Email address not accepted: " + err.Error()
+	}
+
+	if _, err := s.DB.GetUserByEmail(ctx, email); err == nil {
+		return database.BulkItemStatusSkipped, "Already registered"
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return bulkItemError(ctx, job, err)
+	}
+	emailArg := sql.NullString{String: email, Valid: true}
+	if _, err := s.DB.GetUsableInvitationByEmail(ctx, emailArg); err == nil {
+		return database.BulkItemStatusSkipped, "Already invited"
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return bulkItemError(ctx, job, err)
+	}
+
+	if job.DryRun {
+		return database.BulkItemStatusDone, "Would be invited as " + string(role)
+	}
+
+	code, err := generateInvitationCode()
+	if err != nil {
+		return bulkItemError(ctx, job, err)
+	}
+	invitation, err := s.DB.CreateInvitation(ctx, database.CreateInvitationParams{
+		Code:      code,
+		CreatedBy: job.CreatedBy,
+		Email:     emailArg,
+		Note:      item.Note,
+		MaxUses:   1,
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(ImportInvitationTTL), Valid: true},
+		Role:      database.NullUserRole{UserRole: role, Valid: role != database.UserRoleUser},
+	})
+	if err != nil {
+		return bulkItemError(ctx, job, err)
+	}
+
+	if err := s.cfg.Mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "You're invited to Streamify",
+		Body: fmt.Sprintf(
+			"An account has been set up for you on Streamify. Create your password within %d days using the link below, or enter the invitation code %s when signing up.\n\n%s/register?invitation=%s",
+			int(ImportInvitationTTL.Hours()/24), code, s.cfg.FrontendURL, code,
+		),
+	}); err != nil {
+		// An invitation nobody heard of would only block a retry
+		if revokeErr := s.DB.RevokeInvitation(ctx, invitation.ID); revokeErr != nil {
+			logger.Error(ctx, "Bulk import: failed to revoke unsent invitation", revokeErr, "invitation_id", invitation.ID)
+		}
+		logger.Error(ctx, "Bulk import: failed to send invitation", err, "job_id", job.ID)
+		return database.BulkItemStatusFailed, "Failed to send invitation email"
+	}
+	return database.BulkItemStatusDone, "Invited as " + string(role)
+}
+
+func bulkItemError(ctx context.Context, job database.BulkUserJob, err error) (database.BulkItemStatus, string) {
+	logger.Error(ctx, "Bulk user job item failed", err, "job_id", job.ID)
+	return database.BulkItemStatusFailed, "Internal error"
+}
+
+func dedupeIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func bulkError(msg string, err error) *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: msg,
+		Err:     err,
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ConsentMarketing is the consent kind for marketing communication; it has no document
+const ConsentMarketing = "marketing"
+
+type ConsentService struct {
+	BaseService
+	cfg *app.AppConfig
+}
+
+func NewConsentService(db *database.Queries, cfg *app.AppConfig) *ConsentService {
+	return &ConsentService{
+		BaseService: NewBaseService(db),
+		cfg:         cfg,
+	}
+}
+
+type CreateLegalDocumentParams struct {
+	Kind        string `validate:"required,oneof=terms privacy"`
+	Version     string `validate:"required,max=32"`
+	Title       string `validate:"required,max=200"`
+	URL         string `validate:"required,url"`
+	Mandatory   bool
+	PublishedAt *time.Time
+}
+
+// CreateLegalDocument publishes a new version of the terms or privacy policy.
+// A mandatory version blocks the API for users until they accept it.
+func (s *ConsentService) CreateLegalDocument(ctx context.Context, params CreateLegalDocumentParams) (database.LegalDocument, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.LegalDocument{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	publishedAt := time.Now()
+	if params.PublishedAt != nil {
+		publishedAt = *params.PublishedAt
+	}
+
+	doc, err := s.DB.CreateLegalDocument(ctx, database.CreateLegalDocumentParams{
+		Kind:        params.Kind,
+		Version:     params.Version,
+		Title:       params.Title,
+		Url:         params.URL,
+		Mandatory:   params.Mandatory,
+		PublishedAt: publishedAt,
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.LegalDocument{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "This version already exists",
+			}
+		}
+		return database.LegalDocument{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create legal document",
+			Err:     err,
+		}
+	}
+	return doc, nil
+}
+
+// ListLegalDocuments returns the current version of each document, or every version when all is set
+func (s *ConsentService) ListLegalDocuments(ctx context.Context, all bool) ([]database.LegalDocument, *utils.AppError) {
+	var docs []database.LegalDocument
+	var err error
+	if all {
+		docs, err = s.DB.ListLegalDocuments(ctx)
+	} else {
+		docs, err = s.DB.ListCurrentLegalDocuments(ctx)
+	}
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch legal documents",
+			Err:     err,
+		}
+	}
+	return docs, nil
+}
+
+// ListConsents returns the user's consent history and the required documents they still have to accept
+func (s *ConsentService) ListConsents(ctx context.Context, userID uuid.UUID) ([]database.ListUserConsentsRow, []database.LegalDocument, *utils.AppError) {
+	consents, err := s.DB.ListUserConsents(ctx, userID)
+	if err != nil {
+		return nil, nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch consents",
+			Err:     err,
+		}
+	}
+
+	pending, err := s.DB.ListPendingLegalDocuments(ctx, userID)
+	if err != nil {
+		return nil, nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch pending documents",
+			Err:     err,
+		}
+	}
+
+	return consents, pending, nil
+}
+
+type GiveConsentParams struct {
+	UserID uuid.UUID `validate:"required"`
+	// DocumentID is the legal document accepted; leave empty with Kind "marketing"
+	DocumentID uuid.UUID
+	Kind       string
+	IP         string
+	UserAgent  string
+}
+
+// GiveConsent records acceptance of a legal document or opt-in to marketing
+func (s *ConsentService) GiveConsent(ctx context.Context, params GiveConsentParams) (database.UserConsent, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.UserConsent{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	if params.DocumentID == uuid.Nil {
+		if params.Kind != ConsentMarketing {
+			return database.UserConsent{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "document_id is required unless kind is marketing",
+			}
+		}
+
+		// Opting in twice keeps the original record
+		existing, err := s.DB.GetActiveMarketingConsent(ctx, params.UserID)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return database.UserConsent{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+	} else {
+		doc, err := s.DB.GetLegalDocumentByID(ctx, params.DocumentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return database.UserConsent{}, &utils.AppError{
+					Code:    http.StatusNotFound,
+					Message: "Legal document not found",
+				}
+			}
+			return database.UserConsent{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+		if doc.PublishedAt.After(time.Now()) {
+			return database.UserConsent{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Legal document is not published yet",
+			}
+		}
+		params.Kind = doc.Kind
+	}
+
+	consent, err := createConsent(ctx, s.DB, params)
+	if err != nil {
+		return database.UserConsent{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to record consent",
+			Err:     err,
+		}
+	}
+	return consent, nil
+}
+
+// WithdrawConsent withdraws a consent. The record is kept as proof of the period it covered.
+// Withdrawing a mandatory document blocks the API until it is accepted again.
+func (s *ConsentService) WithdrawConsent(ctx context.Context, userID, consentID uuid.UUID, ip string) *utils.AppError {
+	rows, err := s.DB.WithdrawUserConsent(ctx, database.WithdrawUserConsentParams{
+		ID:          consentID,
+		UserID:      userID,
+		WithdrawnIp: utils.ToNullString(&ip),
+	})
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to withdraw consent",
+			Err:     err,
+		}
+	}
+	if rows == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Consent not found or already withdrawn",
+		}
+	}
+	return nil
+}
+
+// checkRequiredConsents makes sure a new account accepts every required legal document
+func checkRequiredConsents(ctx context.Context, db *database.Queries, accepted []uuid.UUID) ([]database.LegalDocument, *utils.AppError) {
+	required, err := db.ListRequiredLegalDocuments(ctx)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch legal documents",
+			Err:     err,
+		}
+	}
+
+	ids := make(map[uuid.UUID]bool, len(accepted))
+	for _, id := range accepted {
+		ids[id] = true
+	}
+	for _, doc := range required {
+		if !ids[doc.ID] {
+			return nil, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "You must accept the current " + doc.Kind + " (version " + doc.Version + ")",
+			}
+		}
+	}
+	return required, nil
+}
+
+func createConsent(ctx context.Context, q *database.Queries, params GiveConsentParams) (database.UserConsent, error) {
+	return q.CreateUserConsent(ctx, database.CreateUserConsentParams{
+		UserID:     params.UserID,
+		Kind:       params.Kind,
+		DocumentID: uuid.NullUUID{UUID: params.DocumentID, Valid: params.DocumentID != uuid.Nil},
+		IpAddress:  utils.ToNullString(&params.IP),
+		UserAgent:  utils.ToNullString(&params.UserAgent),
+	})
+}
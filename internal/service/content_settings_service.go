@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/agegate"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ContentSettings describes what a user is allowed to see
+type ContentSettings struct {
+	BirthDate      *time.Time
+	Country        string
+	Adult          bool
+	FilterExplicit bool
+	// KidProfile is set when the listener profile in use is a kid profile
+	KidProfile bool
+	// ExplicitAllowed is false for minors, users without a birth date, adults who filter
+	// explicit content and kid profiles
+	ExplicitAllowed bool
+}
+
+// GetContentSettings resolves the explicit content rules for a user from their profile.
+// profileID is the selected listener profile, uuid.Nil for the default one.
+func (s *UserService) GetContentSettings(ctx context.Context, userID, profileID uuid.UUID) (ContentSettings, *utils.AppError) {
+	profile, err := s.DB.GetUserProfile(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return ContentSettings{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	now := time.Now()
+	settings := ContentSettings{
+		Country:        profile.Country.String,
+		FilterExplicit: profile.FilterExplicit,
+	}
+	if profile.BirthDate.Valid {
+		settings.BirthDate = &profile.BirthDate.Time
+		settings.Adult = s.cfg.AgeRules.IsAdult(profile.BirthDate.Time, settings.Country, now)
+	}
+	settings.ExplicitAllowed = s.cfg.AgeRules.ExplicitAllowed(settings.BirthDate, settings.Country, settings.FilterExplicit, now)
+
+	listener, appErr := s.CurrentListenerProfile(ctx, userID, profileID)
+	if appErr != nil {
+		return ContentSettings{}, appErr
+	}
+	if listener.IsKid {
+		settings.KidProfile = true
+		settings.ExplicitAllowed = false
+	}
+
+	return settings, nil
+}
+
+// ExplicitAllowed reports whether explicit content may be listed or played for the user's profile
+func (s *UserService) ExplicitAllowed(ctx context.Context, userID, profileID uuid.UUID) (bool, *utils.AppError) {
+	settings, appErr := s.GetContentSettings(ctx, userID, profileID)
+	if appErr != nil {
+		return false, appErr
+	}
+	return settings.ExplicitAllowed, nil
+}
+
+// parseBirthDate parses a YYYY-MM-DD birth date and rejects impossible values
+func parseBirthDate(value string) (time.Time, *utils.AppError) {
+	birthDate, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Birth date must be formatted as YYYY-MM-DD",
+		}
+	}
+	if birthDate.After(time.Now()) || agegate.Age(birthDate, time.Now()) > 130 {
+		return time.Time{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid birth date",
+		}
+	}
+	return birthDate, nil
+}
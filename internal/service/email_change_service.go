@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/handler/token"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/mailer"
+	"github.com/techies/streamify/internal/utils"
+)
+
+const (
+	// EmailChangeTTL is how long the confirmation link sent to the new address stays valid
+	EmailChangeTTL = 24 * time.Hour
+	// EmailChangeRevokeWindow is how long the old address can undo a change
+	EmailChangeRevokeWindow = 7 * 24 * time.Hour
+)
+
+type RequestEmailChangeParams struct {
+	UserID   uuid.UUID `validate:"required"`
+	NewEmail string    `validate:"required,email,max=100"`
+	Password string    `validate:"required"`
+}
+
+// RequestEmailChange starts a change-email flow. A confirmation link is sent to the
+// new address and a revocation link to the current one; users.email is untouched
+// until the new address is confirmed.
+func (s *UserService) RequestEmailChange(ctx context.Context, params RequestEmailChangeParams) (database.EmailChangeRequest, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	user, appErr := s.GetUser(ctx, params.UserID)
+	if appErr != nil {
+		return database.EmailChangeRequest{}, appErr
+	}
+
+	if match, _, err := s.cfg.Passwords.Verify(params.Password, user.PasswordHash); err != nil || !match {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid password",
+		}
+	}
+
+	newEmail := utils.NormalizeEmail(params.NewEmail)
+	if newEmail == utils.NormalizeEmail(user.Email) {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "New email must be different from the current one",
+		}
+	}
+
+	if appErr := s.ensureEmailAvailable(ctx, newEmail, user.ID); appErr != nil {
+		return database.EmailChangeRequest{}, appErr
+	}
+
+	confirmToken, err := token.GenerateSecureToken(32)
+	if err != nil {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to generate token",
+			Err:     err,
+		}
+	}
+	revokeToken, err := token.GenerateSecureToken(32)
+	if err != nil {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to generate token",
+			Err:     err,
+		}
+	}
+
+	// Only one pending change per user: a new request supersedes the previous one
+	var req database.EmailChangeRequest
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.CancelPendingEmailChanges(ctx, user.ID); err != nil {
+			return err
+		}
+		var err error
+		req, err = q.CreateEmailChangeRequest(ctx, database.CreateEmailChangeRequestParams{
+			UserID:       user.ID,
+			OldEmail:     user.Email,
+			NewEmail:     newEmail,
+			ConfirmToken: confirmToken,
+			RevokeToken:  revokeToken,
+			ExpiresAt:    time.Now().Add(EmailChangeTTL),
+		})
+		return err
+	})
+	if err != nil {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create email change request",
+			Err:     err,
+		}
+	}
+
+	if err := s.cfg.Mailer.Send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new Streamify email address",
+		Body: fmt.Sprintf(
+			"Confirm this address for your Streamify account (%s) by opening the link below. It expires in 24 hours.\n\n%s",
+			user.Username, s.emailChangeLink("confirm", confirmToken),
+		),
+	}); err != nil {
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to send confirmation email",
+			Err:     err,
+		}
+	}
+
+	if err := s.cfg.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your Streamify email address is being changed",
+		Body: fmt.Sprintf(
+			"A request was made to change your Streamify email address to %s. If this wasn't you, open the link below to cancel the change and sign out all devices.\n\n%s",
+			newEmail, s.emailChangeLink("revoke", revokeToken),
+		),
+	}); err != nil {
+		// The change can still be confirmed; the old address just won't hear about it
+		logger.Error(ctx, "RequestEmailChange: failed to notify old address", err, "user_id", user.ID)
+	}
+
+	return req, nil
+}
+
+// GetEmailChange returns the user's most recent email change request
+func (s *UserService) GetEmailChange(ctx context.Context, userID uuid.UUID) (database.EmailChangeRequest, *utils.AppError) {
+	req, err := s.DB.GetLatestEmailChangeByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.EmailChangeRequest{}, &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "No email change requested",
+			}
+		}
+		return database.EmailChangeRequest{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	// The expiry job runs periodically, report expiry as soon as it happens
+	if req.Status == "pending" && time.Now().After(req.ExpiresAt) {
+		req.Status = "expired"
+	}
+
+	return req, nil
+}
+
+// CancelEmailChange cancels the user's pending email change, if any
+func (s *UserService) CancelEmailChange(ctx context.Context, userID uuid.UUID) *utils.AppError {
+	if err := s.DB.CancelPendingEmailChanges(ctx, userID); err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to cancel email change",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// ConfirmEmailChange swaps users.email to the confirmed address and revokes every session
+func (s *UserService) ConfirmEmailChange(ctx context.Context, confirmToken string) (database.User, *utils.AppError) {
+	req, err := s.DB.GetEmailChangeByConfirmToken(ctx, confirmToken)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid token",
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	if req.Status != "pending" {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Email change is no longer pending",
+		}
+	}
+
+	if time.Now().After(req.ExpiresAt) {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusUnauthorized,
+			Message: "Token expired",
+		}
+	}
+
+	newEmail := utils.NormalizeEmail(req.NewEmail)
+
+	// The address may have been registered since the request was made
+	if appErr := s.ensureEmailAvailable(ctx, newEmail, req.UserID); appErr != nil {
+		return database.User{}, appErr
+	}
+
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.UpdateUserEmail(ctx, database.UpdateUserEmailParams{
+			ID:    req.UserID,
+			Email: newEmail,
+		}); err != nil {
+			return err
+		}
+		if err := q.MarkEmailChangeConfirmed(ctx, req.ID); err != nil {
+			return err
+		}
+		return q.DeleteAllUserSessions(ctx, req.UserID)
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Email already registered",
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to change email",
+			Err:     err,
+		}
+	}
+
+	return s.GetUser(ctx, req.UserID)
+}
+
+// RevokeEmailChange is triggered from the link sent to the old address. A pending
+// change is cancelled; an already confirmed change is rolled back to the old
+// address. Either way all sessions are revoked since the account may be compromised.
+func (s *UserService) RevokeEmailChange(ctx context.Context, revokeToken string) *utils.AppError {
+	req, err := s.DB.GetEmailChangeByRevokeToken(ctx, revokeToken)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &utils.AppError{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid token",
+			}
+		}
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	if req.Status != "pending" && req.Status != "confirmed" {
+		return &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Email change can no longer be revoked",
+		}
+	}
+
+	if time.Now().After(req.CreatedAt.Add(EmailChangeRevokeWindow)) {
+		return &utils.AppError{
+			Code:    http.StatusUnauthorized,
+			Message: "Token expired",
+		}
+	}
+
+	if req.Status == "confirmed" {
+		// Rolling back an older change would undo the ones confirmed after it
+		newer, err := s.DB.HasNewerConfirmedEmailChange(ctx, database.HasNewerConfirmedEmailChangeParams{
+			UserID:    req.UserID,
+			CreatedAt: req.CreatedAt,
+		})
+		if err != nil {
+			return &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+		if newer {
+			return emailChangeSupersededError()
+		}
+		if appErr := s.ensureEmailAvailable(ctx, req.OldEmail, req.UserID); appErr != nil {
+			return appErr
+		}
+	}
+
+	errSuperseded := errors.New("email changed since the request")
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if req.Status == "confirmed" {
+			n, err := q.RestoreUserEmail(ctx, database.RestoreUserEmailParams{
+				ID:       req.UserID,
+				OldEmail: req.OldEmail,
+				NewEmail: req.NewEmail,
+			})
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return errSuperseded
+			}
+			// Changes requested from the address being revoked go with it
+			if err := q.CancelPendingEmailChanges(ctx, req.UserID); err != nil {
+				return err
+			}
+		}
+		if err := q.MarkEmailChangeRevoked(ctx, req.ID); err != nil {
+			return err
+		}
+		return q.DeleteAllUserSessions(ctx, req.UserID)
+	})
+	if errors.Is(err, errSuperseded) {
+		return emailChangeSupersededError()
+	}
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Email already registered",
+			}
+		}
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to revoke email change",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func emailChangeSupersededError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusConflict,
+		Message: "The account's email has changed again since; this change can no longer be revoked",
+	}
+}
+
+// ensureEmailAvailable returns a conflict error if email belongs to a user other than userID
+func (s *UserService) ensureEmailAvailable(ctx context.Context, email string, userID uuid.UUID) *utils.AppError {
+	existing, err := s.DB.GetUserByEmail(ctx, email)
+	if err == nil && existing.ID != userID {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Email already registered",
+		}
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+func (s *UserService) emailChangeLink(action, tok string) string {
+	return fmt.Sprintf("%s/email/%s?token=%s", s.cfg.FrontendURL, action, url.QueryEscape(tok))
+}
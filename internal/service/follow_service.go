@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// FollowUser is an entry of a follower, following, request or block list
+type FollowUser struct {
+	ID       uuid.UUID
+	Username string
+	// Since is when the follow, request or block was made
+	Since time.Time
+}
+
+type FollowListResult struct {
+	Users []FollowUser
+	Next  *pagination.Cursor
+}
+
+// followTarget loads a user others can interact with; deleted and locked accounts are not found
+func (s *UserService) followTarget(ctx context.Context, userID uuid.UUID) (database.User, *utils.AppError) {
+	user, err := s.DB.GetUserById(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && (user.Status == "deleted" || user.IsLocked) {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+	if err != nil {
+		return database.User{}, followError(err)
+	}
+	return user, nil
+}
+
+// FollowUser follows targetID, or sends a follow request when the target requires approval
+func (s *UserService) FollowUser(ctx context.Context, followerID, targetID uuid.UUID) (database.UserFollow, *utils.AppError) {
+	if followerID == targetID {
+		return database.UserFollow{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "You can't follow yourself",
+		}
+	}
+	if _, appErr := s.followTarget(ctx, targetID); appErr != nil {
+		return database.UserFollow{}, appErr
+	}
+
+	block, err := s.DB.GetBlockBetween(ctx, database.GetBlockBetweenParams{UserA: followerID, UserB: targetID})
+	if err != nil {
+		return database.UserFollow{}, followError(err)
+	}
+	if block.ABlockedB {
+		return database.UserFollow{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Unblock this user before following them",
+		}
+	}
+	// Users who were blocked don't learn about it
+	if block.BBlockedA {
+		return database.UserFollow{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+
+	settings, appErr := s.GetPrivacySettings(ctx, targetID)
+	if appErr != nil {
+		return database.UserFollow{}, appErr
+	}
+	status := database.FollowStatusAccepted
+	if settings.RequireFollowApproval {
+		status = database.FollowStatusPending
+	}
+
+	follow, err := s.DB.CreateUserFollow(ctx, database.CreateUserFollowParams{
+		FollowerID: followerID,
+		FolloweeID: targetID,
+		Status:     status,
+	})
+	if err != nil {
+		return database.UserFollow{}, followError(err)
+	}
+	return follow, nil
+}
+
+// UnfollowUser removes a follow or withdraws a pending request
+func (s *UserService) UnfollowUser(ctx context.Context, followerID, targetID uuid.UUID) *utils.AppError {
+	n, err := s.DB.DeleteUserFollow(ctx, database.DeleteUserFollowParams{FollowerID: followerID, FolloweeID: targetID})
+	if err != nil {
+		return followError(err)
+	}
+	if n == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "You are not following this user",
+		}
+	}
+	return nil
+}
+
+// RemoveFollower makes followerID stop following userID
+func (s *UserService) RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) *utils.AppError {
+	n, err := s.DB.DeleteUserFollow(ctx, database.DeleteUserFollowParams{FollowerID: followerID, FolloweeID: userID})
+	if err != nil {
+		return followError(err)
+	}
+	if n == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Follower not found",
+		}
+	}
+	return nil
+}
+
+// ApproveFollowRequest accepts followerID's pending request to follow userID
+func (s *UserService) ApproveFollowRequest(ctx context.Context, userID, followerID uuid.UUID) *utils.AppError {
+	n, err := s.DB.AcceptFollowRequest(ctx, database.AcceptFollowRequestParams{FollowerID: followerID, FolloweeID: userID})
+	if err != nil {
+		return followError(err)
+	}
+	if n == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Follow request not found",
+		}
+	}
+	return nil
+}
+
+// DeclineFollowRequest deletes followerID's pending request to follow userID
+func (s *UserService) DeclineFollowRequest(ctx context.Context, userID, followerID uuid.UUID) *utils.AppError {
+	n, err := s.DB.DeleteFollowRequest(ctx, database.DeleteFollowRequestParams{FollowerID: followerID, FolloweeID: userID})
+	if err != nil {
+		return followError(err)
+	}
+	if n == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "Follow request not found",
+		}
+	}
+	return nil
+}
+
+// ListFollowers lists who follows userID, as far as viewerID may see the profile
+func (s *UserService) ListFollowers(ctx context.Context, userID, viewerID uuid.UUID, page pagination.Params) (FollowListResult, *utils.AppError) {
+	if appErr := s.checkFollowListVisible(ctx, userID, viewerID); appErr != nil {
+		return FollowListResult{}, appErr
+	}
+	return s.listFollowers(ctx, userID, database.FollowStatusAccepted, page)
+}
+
+// ListFollowRequests lists the pending requests to follow userID
+func (s *UserService) ListFollowRequests(ctx context.Context, userID uuid.UUID, page pagination.Params) (FollowListResult, *utils.AppError) {
+	return s.listFollowers(ctx, userID, database.FollowStatusPending, page)
+}
+
+func (s *UserService) listFollowers(ctx context.Context, userID uuid.UUID, status database.FollowStatus, page pagination.Params) (FollowListResult, *utils.AppError) {
+	rows, err := s.DB.ListFollowers(ctx, database.ListFollowersParams{
+		UserID:          userID,
+		Status:          status,
+		CursorCreatedAt: page.CursorCreatedAt(),
+		CursorID:        page.CursorID(),
+		Lim:             page.FetchLimit(),
+	})
+	if err != nil {
+		return FollowListResult{}, followError(err)
+	}
+	rows, more := pagination.Trim(rows, page)
+
+	result := FollowListResult{Users: make([]FollowUser, len(rows))}
+	for i, row := range rows {
+		result.Users[i] = FollowUser{ID: row.ID, Username: row.Username, Since: row.FollowedAt}
+	}
+	result.Next = nextFollowCursor(result.Users, more)
+	return result, nil
+}
+
+// ListFollowing lists who userID follows, as far as viewerID may see the profile
+func (s *UserService) ListFollowing(ctx context.Context, userID, viewerID uuid.UUID, page pagination.Params) (FollowListResult, *utils.AppError) {
+	if appErr := s.checkFollowListVisible(ctx, userID, viewerID); appErr != nil {
+		return FollowListResult{}, appErr
+	}
+
+	rows, err := s.DB.ListFollowing(ctx, database.ListFollowingParams{
+		UserID:          userID,
+		CursorCreatedAt: page.CursorCreatedAt(),
+		CursorID:        page.CursorID(),
+		Lim:             page.FetchLimit(),
+	})
+	if err != nil {
+		return FollowListResult{}, followError(err)
+	}
+	rows, more := pagination.Trim(rows, page)
+
+	result := FollowListResult{Users: make([]FollowUser, len(rows))}
+	for i, row := range rows {
+		result.Users[i] = FollowUser{ID: row.ID, Username: row.Username, Since: row.FollowedAt}
+	}
+	result.Next = nextFollowCursor(result.Users, more)
+	return result, nil
+}
+
+// checkFollowListVisible hides follow lists of profiles the viewer can't see
+func (s *UserService) checkFollowListVisible(ctx context.Context, userID, viewerID uuid.UUID) *utils.AppError {
+	if _, appErr := s.followTarget(ctx, userID); appErr != nil {
+		return appErr
+	}
+	settings, appErr := s.GetPrivacySettings(ctx, userID)
+	if appErr != nil {
+		return appErr
+	}
+	rel, err := s.relationTo(ctx, userID, viewerID)
+	if err != nil {
+		return followError(err)
+	}
+	if !rel.canSee(settings.Profile) {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "User not found",
+		}
+	}
+	return nil
+}
+
+// BlockUser blocks targetID and ends any follows between the two users
+func (s *UserService) BlockUser(ctx context.Context, blockerID, targetID uuid.UUID) *utils.AppError {
+	if blockerID == targetID {
+		return &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "You can't block yourself",
+		}
+	}
+	if _, err := s.DB.GetUserById(ctx, targetID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+			}
+		}
+		return followError(err)
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.CreateUserBlock(ctx, database.CreateUserBlockParams{BlockerID: blockerID, BlockedID: targetID}); err != nil {
+			return err
+		}
+		return q.DeleteFollowsBetween(ctx, database.DeleteFollowsBetweenParams{UserA: blockerID, UserB: targetID})
+	})
+	if err != nil {
+		return followError(err)
+	}
+	return nil
+}
+
+func (s *UserService) UnblockUser(ctx context.Context, blockerID, targetID uuid.UUID) *utils.AppError {
+	n, err := s.DB.DeleteUserBlock(ctx, database.DeleteUserBlockParams{BlockerID: blockerID, BlockedID: targetID})
+	if err != nil {
+		return followError(err)
+	}
+	if n == 0 {
+		return &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "User is not blocked",
+		}
+	}
+	return nil
+}
+
+func (s *UserService) ListBlockedUsers(ctx context.Context, userID uuid.UUID, page pagination.Params) (FollowListResult, *utils.AppError) {
+	rows, err := s.DB.ListUserBlocks(ctx, database.ListUserBlocksParams{
+		UserID:          userID,
+		CursorCreatedAt: page.CursorCreatedAt(),
+		CursorID:        page.CursorID(),
+		Lim:             page.FetchLimit(),
+	})
+	if err != nil {
+		return FollowListResult{}, followError(err)
+	}
+	rows, more := pagination.Trim(rows, page)
+
+	result := FollowListResult{Users: make([]FollowUser, len(rows))}
+	for i, row := range rows {
+		result.Users[i] = FollowUser{ID: row.ID, Username: row.Username, Since: row.BlockedAt}
+	}
+	result.Next = nextFollowCursor(result.Users, more)
+	return result, nil
+}
+
+func nextFollowCursor(users []FollowUser, more bool) *pagination.Cursor {
+	if !more || len(users) == 0 {
+		return nil
+	}
+	last := users[len(users)-1]
+	return &pagination.Cursor{CreatedAt: last.Since, ID: last.ID}
+}
+
+func followError(err error) *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: "Database error",
+		Err:     err,
+	}
+}
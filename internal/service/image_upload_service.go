@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/imaging"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/storage"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// displayRenditionSize is the rendition avatar_url and cover_url point at
+const displayRenditionSize = 640
+
+// renditionKey is where the size x size rendition of an uploaded image lives
+func renditionKey(key string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", key, size)
+}
+
+// RenditionURLs maps each rendition size to its URL, nil when key isn't set
+func RenditionURLs(cfg *app.AppConfig, key sql.NullString) map[string]string {
+	if !key.Valid {
+		return nil
+	}
+	urls := make(map[string]string, len(imaging.RenditionSizes))
+	for _, size := range imaging.RenditionSizes {
+		urls[strconv.Itoa(size)] = cfg.Storage.URL(renditionKey(key.String, size))
+	}
+	return urls
+}
+
+// storeImage validates an upload and stores its renditions under prefix.
+// It returns the key the renditions share and the URL of the display rendition.
+func storeImage(ctx context.Context, cfg *app.AppConfig, prefix string, data []byte) (string, string, *utils.AppError) {
+	renditions, err := imaging.Renditions(data, cfg.ImageLimits, imaging.RenditionSizes)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return "", "", &utils.AppError{Code: http.StatusUnsupportedMediaType, Message: err.Error()}
+		case errors.Is(err, imaging.ErrTooManyPixels):
+			return "", "", &utils.AppError{Code: http.StatusRequestEntityTooLarge, Message: err.Error()}
+		case errors.Is(err, imaging.ErrMalformed):
+			return "", "", &utils.AppError{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		return "", "", &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to process image",
+			Err:     err,
+		}
+	}
+
+	// Keys follow the content so a new upload never reuses a cached URL
+	key := storage.ContentKey(prefix, sha256.Sum256(data), "")
+	for _, r := range renditions {
+		if err := cfg.Storage.Put(ctx, renditionKey(key, r.Size), bytes.NewReader(r.Data), imaging.ContentType); err != nil {
+			deleteImage(ctx, cfg, key)
+			return "", "", &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to store image",
+				Err:     err,
+			}
+		}
+	}
+	return key, cfg.Storage.URL(renditionKey(key, displayRenditionSize)), nil
+}
+
+// deleteImage removes the renditions under key. Failures only leave orphaned files behind, so they are logged.
+func deleteImage(ctx context.Context, cfg *app.AppConfig, key string) {
+	for _, size := range imaging.RenditionSizes {
+		if err := cfg.Storage.Delete(ctx, renditionKey(key, size)); err != nil {
+			logger.Warn(ctx, "Failed to delete image rendition", "key", key, "size", size, "error", err)
+		}
+	}
+}
+
+// UploadAvatar replaces the user's avatar with renditions of the uploaded image
+func (s *UserService) UploadAvatar(ctx context.Context, userID uuid.UUID, data []byte) (database.User, *utils.AppError) {
+	current, appErr := s.GetUser(ctx, userID)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	key, url, appErr := storeImage(ctx, s.cfg, "avatars/"+userID.String(), data)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	user, err := s.DB.SetUserAvatar(ctx, database.SetUserAvatarParams{
+		ID:        userID,
+		AvatarUrl: sql.NullString{String: url, Valid: true},
+		AvatarKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		if current.AvatarKey.String != key {
+			deleteImage(ctx, s.cfg, key)
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update avatar",
+			Err:     err,
+		}
+	}
+
+	if current.AvatarKey.Valid && current.AvatarKey.String != key {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+	return user, nil
+}
+
+// DeleteAvatar removes the user's avatar and any uploaded renditions
+func (s *UserService) DeleteAvatar(ctx context.Context, userID uuid.UUID) (database.User, *utils.AppError) {
+	current, appErr := s.GetUser(ctx, userID)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	user, err := s.DB.SetUserAvatar(ctx, database.SetUserAvatarParams{ID: userID})
+	if err != nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to remove avatar",
+			Err:     err,
+		}
+	}
+
+	if current.AvatarKey.Valid {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+	return user, nil
+}
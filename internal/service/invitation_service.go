@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/registration"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// errInvitationUnavailable is returned inside registration transactions when the
+// invitation was used up, revoked or expired since it was looked up
+var errInvitationUnavailable = errors.New("invitation unavailable")
+
+type InvitationService struct {
+	BaseService
+	cfg *app.AppConfig
+}
+
+func NewInvitationService(db *database.Queries, cfg *app.AppConfig) *InvitationService {
+	return &InvitationService{
+		BaseService: NewBaseService(db),
+		cfg:         cfg,
+	}
+}
+
+type CreateInvitationParams struct {
+	CreatedBy uuid.UUID `validate:"required"`
+	Email     string    `validate:"omitempty,email"`
+	Note      string    `validate:"max=500"`
+	MaxUses   int32     `validate:"min=1,max=10000"`
+	ExpiresAt *time.Time
+}
+
+func (s *InvitationService) CreateInvitation(ctx context.Context, params CreateInvitationParams) (database.Invitation, *utils.AppError) {
+	if params.MaxUses == 0 {
+		params.MaxUses = 1
+	}
+	if err := validate.Struct(params); err != nil {
+		return database.Invitation{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	if params.ExpiresAt != nil && params.ExpiresAt.Before(time.Now()) {
+		return database.Invitation{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Expiry must be in the future",
+		}
+	}
+
+	code, err := generateInvitationCode()
+	if err != nil {
+		return database.Invitation{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to generate invitation code",
+			Err:     err,
+		}
+	}
+
+	expiresAt := sql.NullTime{}
+	if params.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *params.ExpiresAt, Valid: true}
+	}
+
+	invitation, err := s.DB.CreateInvitation(ctx, database.CreateInvitationParams{
+		Code:      code,
+		CreatedBy: uuid.NullUUID{UUID: params.CreatedBy, Valid: true},
+		Email:     sql.NullString{String: utils.NormalizeEmail(params.Email), Valid: params.Email != ""},
+		Note:      sql.NullString{String: params.Note, Valid: params.Note != ""},
+		MaxUses:   params.MaxUses,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return database.Invitation{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create invitation",
+			Err:     err,
+		}
+	}
+
+	return invitation, nil
+}
+
+func (s *InvitationService) ListInvitations(ctx context.Context, limit, offset int32) ([]database.Invitation, *utils.AppError) {
+	invitations, err := s.DB.ListInvitations(ctx, database.ListInvitationsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch invitations",
+			Err:     err,
+		}
+	}
+	return invitations, nil
+}
+
+func (s *InvitationService) GetInvitation(ctx context.Context, id uuid.UUID) (database.Invitation, *utils.AppError) {
+	invitation, err := s.DB.GetInvitationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Invitation{}, &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "Invitation not found",
+			}
+		}
+		return database.Invitation{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id uuid.UUID) *utils.AppError {
+	if _, appErr := s.GetInvitation(ctx, id); appErr != nil {
+		return appErr
+	}
+
+	if err := s.DB.RevokeInvitation(ctx, id); err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to revoke invitation",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// ListRedemptions returns the users who signed up with an invitation, for referral attribution
+func (s *InvitationService) ListRedemptions(ctx context.Context, id uuid.UUID) ([]database.ListInvitationRedemptionsRow, *utils.AppError) {
+	if _, appErr := s.GetInvitation(ctx, id); appErr != nil {
+		return nil, appErr
+	}
+
+	redemptions, err := s.DB.ListInvitationRedemptions(ctx, id)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch redemptions",
+			Err:     err,
+		}
+	}
+	return redemptions, nil
+}
+
+// checkRegistrationPolicy enforces the registration mode and email domain rules.
+// It returns the invitation to redeem, or nil when no code was given.
+func (s *AuthService) checkRegistrationPolicy(ctx context.Context, email, code string) (*database.Invitation, *utils.AppError) {
+	policy := s.cfg.Registration
+
+	if policy.Mode == registration.ModeClosed {
+		return nil, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Registration is closed",
+		}
+	}
+
+	if err := policy.CheckEmail(email); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, registration.ErrDisposableEmail) {
+			status = http.StatusBadRequest
+		}
+		return nil, &utils.AppError{
+			Code:    status,
+			Message: "Email address not accepted",
+			Err:     err,
+		}
+	}
+
+	code = normalizeInvitationCode(code)
+	if code == "" {
+		if policy.InvitationRequired() {
+			return nil, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "An invitation code is required",
+			}
+		}
+		return nil, nil
+	}
+
+	invitation, err := s.DB.GetInvitationByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "Invalid invitation code",
+			}
+		}
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	if invitation.RevokedAt.Valid ||
+		invitation.UseCount >= invitation.MaxUses ||
+		(invitation.ExpiresAt.Valid && invitation.ExpiresAt.Time.Before(time.Now())) {
+		return nil, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Invitation is no longer valid",
+		}
+	}
+
+	if invitation.Email.Valid && invitation.Email.String != email {
+		return nil, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Invitation was issued for a different email address",
+		}
+	}
+
+	return &invitation, nil
+}
+
+// consumeInvitation takes one use of an invitation inside a registration transaction
+func consumeInvitation(ctx context.Context, q *database.Queries, id uuid.UUID) error {
+	rows, err := q.ConsumeInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errInvitationUnavailable
+	}
+	return nil
+}
+
+// generateInvitationCode returns a 10 character code that is easy to type
+func generateInvitationCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)[:10], nil
+}
+
+func normalizeInvitationCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
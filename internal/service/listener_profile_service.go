@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/handler/token"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// MaxListenerProfiles is how many household profiles one account can have
+const MaxListenerProfiles = 6
+
+// createDefaultProfile adds the account holder's own profile, named after the username
+func createDefaultProfile(ctx context.Context, q *database.Queries, user database.User) error {
+	_, err := q.CreateListenerProfile(ctx, database.CreateListenerProfileParams{
+		UserID:    user.ID,
+		Name:      user.Username,
+		IsDefault: true,
+	})
+	return err
+}
+
+func (s *UserService) ListListenerProfiles(ctx context.Context, userID uuid.UUID) ([]database.ListenerProfile, *utils.AppError) {
+	profiles, err := s.DB.ListListenerProfiles(ctx, userID)
+	if err != nil {
+		return nil, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to list profiles",
+			Err:     err,
+		}
+	}
+	return profiles, nil
+}
+
+type CreateListenerProfileParams struct {
+	UserID uuid.UUID `validate:"required"`
+	// ActingProfileID is the profile the request was made from; kid profiles can't manage profiles
+	ActingProfileID uuid.UUID
+	Name            string `validate:"required,max=50"`
+	AvatarUrl       string `validate:"omitempty,url"`
+	IsKid           bool
+	PIN             string `validate:"omitempty,numeric,min=4,max=6"`
+}
+
+func (s *UserService) CreateListenerProfile(ctx context.Context, params CreateListenerProfileParams) (database.ListenerProfile, *utils.AppError) {
+	params.Name = strings.TrimSpace(params.Name)
+	if err := validate.Struct(params); err != nil {
+		return database.ListenerProfile{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	if appErr := s.checkProfileManager(ctx, params.UserID, params.ActingProfileID); appErr != nil {
+		return database.ListenerProfile{}, appErr
+	}
+
+	count, err := s.DB.CountListenerProfiles(ctx, params.UserID)
+	if err != nil {
+		return database.ListenerProfile{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if count >= MaxListenerProfiles {
+		return database.ListenerProfile{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Profile limit reached",
+		}
+	}
+
+	pinHash, appErr := s.hashPIN(params.PIN)
+	if appErr != nil {
+		return database.ListenerProfile{}, appErr
+	}
+
+	profile, err := s.DB.CreateListenerProfile(ctx, database.CreateListenerProfileParams{
+		UserID:    params.UserID,
+		Name:      params.Name,
+		AvatarUrl: sql.NullString{String: params.AvatarUrl, Valid: params.AvatarUrl != ""},
+		IsKid:     params.IsKid,
+		PinHash:   pinHash,
+	})
+	if err != nil {
+		return database.ListenerProfile{}, listenerProfileError(err)
+	}
+	return profile, nil
+}
+
+type UpdateListenerProfileParams struct {
+	UserID          uuid.UUID `validate:"required"`
+	ProfileID       uuid.UUID `validate:"required"`
+	ActingProfileID uuid.UUID
+	Name            *string `validate:"omitempty,min=1,max=50"`
+	AvatarUrl       *string `validate:"omitempty,url"`
+	IsKid           *bool
+	// PIN replaces the current PIN; an empty string removes it
+	PIN *string `validate:"omitempty,numeric,min=4,max=6"`
+}
+
+func (s *UserService) UpdateListenerProfile(ctx context.Context, params UpdateListenerProfileParams) (database.ListenerProfile, *utils.AppError) {
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		params.Name = &name
+	}
+	// Empty strings clear the field and must not hit the format rules
+	pin := params.PIN
+	if pin != nil && *pin == "" {
+		params.PIN = nil
+	}
+	avatar := params.AvatarUrl
+	if avatar != nil && *avatar == "" {
+		params.AvatarUrl = nil
+	}
+	if err := validate.Struct(params); err != nil {
+		return database.ListenerProfile{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	if appErr := s.checkProfileManager(ctx, params.UserID, params.ActingProfileID); appErr != nil {
+		return database.ListenerProfile{}, appErr
+	}
+
+	profile, appErr := s.getListenerProfile(ctx, params.UserID, params.ProfileID)
+	if appErr != nil {
+		return database.ListenerProfile{}, appErr
+	}
+
+	update := database.UpdateListenerProfileParams{
+		ID:        profile.ID,
+		UserID:    profile.UserID,
+		Name:      profile.Name,
+		AvatarUrl: profile.AvatarUrl,
+		IsKid:     profile.IsKid,
+		PinHash:   profile.PinHash,
+	}
+	if params.Name != nil {
+		update.Name = *params.Name
+	}
+	if avatar != nil {
+		update.AvatarUrl = sql.NullString{String: *avatar, Valid: *avatar != ""}
+	}
+	if params.IsKid != nil {
+		// The account holder's own profile always keeps full access
+		if *params.IsKid && profile.IsDefault {
+			return database.ListenerProfile{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "The default profile can't be a kid profile",
+			}
+		}
+		update.IsKid = *params.IsKid
+	}
+	if pin != nil {
+		if update.PinHash, appErr = s.hashPIN(*pin); appErr != nil {
+			return database.ListenerProfile{}, appErr
+		}
+	}
+
+	updated, err := s.DB.UpdateListenerProfile(ctx, update)
+	if err != nil {
+		return database.ListenerProfile{}, listenerProfileError(err)
+	}
+	return updated, nil
+}
+
+// DeleteListenerProfile removes a profile together with its streams, likes and playlists
+func (s *UserService) DeleteListenerProfile(ctx context.Context, userID, profileID, actingProfileID uuid.UUID) *utils.AppError {
+	if appErr := s.checkProfileManager(ctx, userID, actingProfileID); appErr != nil {
+		return appErr
+	}
+
+	profile, appErr := s.getListenerProfile(ctx, userID, profileID)
+	if appErr != nil {
+		return appErr
+	}
+	if profile.IsDefault {
+		return &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "The default profile can't be deleted",
+		}
+	}
+
+	if _, err := s.DB.DeleteListenerProfile(ctx, database.DeleteListenerProfileParams{
+		ID:     profileID,
+		UserID: userID,
+	}); err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to delete profile",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+type SelectListenerProfileParams struct {
+	UserID    uuid.UUID `validate:"required"`
+	SessionID uuid.UUID `validate:"required"`
+	ProfileID uuid.UUID `validate:"required"`
+	PIN       string
+}
+
+type SelectListenerProfileResult struct {
+	Profile     database.ListenerProfile
+	AccessToken string
+}
+
+// SelectListenerProfile switches the session to a profile and issues an access token scoped to it.
+// Refreshed tokens keep the profile until another one is selected.
+func (s *UserService) SelectListenerProfile(ctx context.Context, params SelectListenerProfileParams) (SelectListenerProfileResult, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return SelectListenerProfileResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	profile, appErr := s.getListenerProfile(ctx, params.UserID, params.ProfileID)
+	if appErr != nil {
+		return SelectListenerProfileResult{}, appErr
+	}
+
+	if profile.PinHash.Valid {
+		if params.PIN == "" {
+			return SelectListenerProfileResult{}, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "This profile is protected by a PIN",
+			}
+		}
+		match, _, err := s.cfg.Passwords.Verify(params.PIN, profile.PinHash.String)
+		if err != nil || !match {
+			return SelectListenerProfileResult{}, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "Incorrect PIN",
+			}
+		}
+	}
+
+	user, appErr := s.GetUser(ctx, params.UserID)
+	if appErr != nil {
+		return SelectListenerProfileResult{}, appErr
+	}
+
+	session, err := s.DB.SetSessionProfile(ctx, database.SetSessionProfileParams{
+		ID:        params.SessionID,
+		ProfileID: uuid.NullUUID{UUID: profile.ID, Valid: true},
+	})
+	if err != nil {
+		return SelectListenerProfileResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update session",
+			Err:     err,
+		}
+	}
+
+	accessToken, err := utils.GenerateToken(user.ID, params.SessionID, token.AccessTokenTTL, s.cfg.JWTSecret, user.Role, user.FirstName.String, user.LastName.String, user.PhoneNumber.String, user.Email, profile.ID, session.ArtistID.UUID)
+	if err != nil {
+		return SelectListenerProfileResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to generate access token",
+			Err:     err,
+		}
+	}
+
+	return SelectListenerProfileResult{Profile: profile, AccessToken: accessToken}, nil
+}
+
+// CurrentListenerProfile returns the selected profile, falling back to the default one
+// when no profile is selected or the selected one was deleted.
+func (s *UserService) CurrentListenerProfile(ctx context.Context, userID, profileID uuid.UUID) (database.ListenerProfile, *utils.AppError) {
+	if profileID != uuid.Nil {
+		profile, err := s.DB.GetListenerProfile(ctx, database.GetListenerProfileParams{ID: profileID, UserID: userID})
+		if err == nil {
+			return profile, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return database.ListenerProfile{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+	}
+
+	profile, err := s.DB.GetDefaultListenerProfile(ctx, userID)
+	if err != nil {
+		return database.ListenerProfile{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to load profile",
+			Err:     err,
+		}
+	}
+	return profile, nil
+}
+
+func (s *UserService) getListenerProfile(ctx context.Context, userID, profileID uuid.UUID) (database.ListenerProfile, *utils.AppError) {
+	profile, err := s.DB.GetListenerProfile(ctx, database.GetListenerProfileParams{ID: profileID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.ListenerProfile{}, &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "Profile not found",
+			}
+		}
+		return database.ListenerProfile{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return profile, nil
+}
+
+// checkProfileManager keeps kid profiles from adding profiles, lifting restrictions or changing PINs
+func (s *UserService) checkProfileManager(ctx context.Context, userID, actingProfileID uuid.UUID) *utils.AppError {
+	if actingProfileID == uuid.Nil {
+		return nil
+	}
+	acting, appErr := s.CurrentListenerProfile(ctx, userID, actingProfileID)
+	if appErr != nil {
+		return appErr
+	}
+	if acting.IsKid {
+		return &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Kid profiles can't manage profiles",
+		}
+	}
+	return nil
+}
+
+// hashPIN hashes a profile PIN like a password; an empty PIN means none
+func (s *UserService) hashPIN(pin string) (sql.NullString, *utils.AppError) {
+	if pin == "" {
+		return sql.NullString{}, nil
+	}
+	hash, err := s.cfg.Passwords.Hash(pin)
+	if err != nil {
+		return sql.NullString{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to hash PIN",
+			Err:     err,
+		}
+	}
+	return sql.NullString{String: hash, Valid: true}, nil
+}
+
+func listenerProfileError(err error) *utils.AppError {
+	if utils.IsUniqueViolation(err) {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "A profile with this name already exists",
+		}
+	}
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: "Failed to save profile",
+		Err:     err,
+	}
+}
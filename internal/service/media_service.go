@@ -0,0 +1,534 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/pagination"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/storage"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// streamURLTTL is how long a presigned stream URL stays valid
+const streamURLTTL = time.Hour
+
+// ErrExplicitBlocked is the AppError.Err of explicit media the viewer may not see
+var ErrExplicitBlocked = errors.New("explicit content is blocked for this profile")
+
+type MediaService struct {
+	BaseService
+	cfg *app.AppConfig
+}
+
+func NewMediaService(db *database.Queries, cfg *app.AppConfig) *MediaService {
+	return &MediaService{
+		BaseService: NewBaseService(db),
+		cfg:         cfg,
+	}
+}
+
+// MediaItem is a media row with the fields of its songs or videos row.
+// ListMedia selects the same columns, so its rows convert to MediaItem.
+type MediaItem = database.GetMediaRow
+
+// MediaViewer is who a media read is for
+type MediaViewer struct {
+	Actor ArtistActor
+	// ExplicitAllowed comes from the viewer's content settings
+	ExplicitAllowed bool
+}
+
+type CreateMediaParams struct {
+	Type     string `validate:"required,oneof=song video"`
+	ArtistID uuid.UUID
+	Title    string `validate:"required,max=150"`
+	// Duration is in seconds
+	Duration *int32 `validate:"omitempty,min=1,max=86400"`
+	Explicit bool
+
+	// Songs
+	AlbumID     *uuid.UUID
+	AudioUrl    *string `validate:"omitempty,http_url,max=2048"`
+	AudioKey    *string `validate:"omitempty,max=1024"`
+	Bitrate     *int32  `validate:"omitempty,min=8,max=9216"`
+	DiscNumber  *int32  `validate:"omitempty,min=1,max=99"`
+	TrackNumber *int32  `validate:"omitempty,min=1,max=999"`
+
+	// Videos
+	VideoUrl   *string `validate:"omitempty,http_url,max=2048"`
+	VideoKey   *string `validate:"omitempty,max=1024"`
+	Resolution *string `validate:"omitempty,max=20"`
+}
+
+// CreateMedia adds a song or video to the artist's catalog. The media row and
+// its songs or videos row are written in one transaction.
+func (s *MediaService) CreateMedia(ctx context.Context, actor ArtistActor, params CreateMediaParams) (MediaItem, *utils.AppError) {
+	params.Title = strings.TrimSpace(params.Title)
+	if err := validate.Struct(params); err != nil {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if fields := createMediaFieldErrors(params); len(fields) > 0 {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, params.ArtistID, ArtistManageCatalog); appErr != nil {
+		return MediaItem{}, appErr
+	}
+
+	for _, key := range []*string{params.AudioKey, params.VideoKey} {
+		if key == nil {
+			continue
+		}
+		if appErr := s.checkStorageKey(ctx, *key); appErr != nil {
+			return MediaItem{}, appErr
+		}
+	}
+	if params.AlbumID != nil {
+		album, err := s.DB.GetAlbumByID(ctx, *params.AlbumID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && album.ArtistID != params.ArtistID) {
+			return MediaItem{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Fields:  map[string]string{"album_id": "must be an album of the same artist"},
+			}
+		}
+		if err != nil {
+			return MediaItem{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+	}
+
+	var mediaID uuid.UUID
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		media, err := q.CreateMedia(ctx, database.CreateMediaParams{
+			Type:     params.Type,
+			Title:    params.Title,
+			Duration: toNullInt32(params.Duration),
+			ArtistID: uuid.NullUUID{UUID: params.ArtistID, Valid: true},
+			Explicit: params.Explicit,
+		})
+		if err != nil {
+			return err
+		}
+		mediaID = media.ID
+
+		if params.Type == "video" {
+			_, err = q.CreateVideo(ctx, database.CreateVideoParams{
+				ID:         media.ID,
+				VideoUrl:   utils.ToNullString(params.VideoUrl),
+				VideoKey:   utils.ToNullString(params.VideoKey),
+				Resolution: utils.ToNullString(params.Resolution),
+			})
+			return err
+		}
+
+		disc := int32(1)
+		if params.DiscNumber != nil {
+			disc = *params.DiscNumber
+		}
+		song := database.CreateSongParams{
+			ID:          media.ID,
+			AudioUrl:    utils.ToNullString(params.AudioUrl),
+			AudioKey:    utils.ToNullString(params.AudioKey),
+			Bitrate:     toNullInt32(params.Bitrate),
+			DiscNumber:  disc,
+			TrackNumber: toNullInt32(params.TrackNumber),
+		}
+		if params.AlbumID != nil {
+			song.AlbumID = uuid.NullUUID{UUID: *params.AlbumID, Valid: true}
+		}
+		_, err = q.CreateSong(ctx, song)
+		return err
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return MediaItem{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "The album already has a song at this disc and track number",
+			}
+		}
+		if utils.IsForeignKeyViolation(err) {
+			return MediaItem{}, &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "Artist or album not found",
+			}
+		}
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to create media",
+			Err:     err,
+		}
+	}
+	return s.getMedia(ctx, mediaID)
+}
+
+// createMediaFieldErrors checks that the subtype fields match the media type
+func createMediaFieldErrors(params CreateMediaParams) map[string]string {
+	fields := map[string]string{}
+	songOnly := map[string]bool{
+		"album_id":     params.AlbumID != nil,
+		"audio_url":    params.AudioUrl != nil,
+		"audio_key":    params.AudioKey != nil,
+		"bitrate":      params.Bitrate != nil,
+		"disc_number":  params.DiscNumber != nil,
+		"track_number": params.TrackNumber != nil,
+	}
+	videoOnly := map[string]bool{
+		"video_url":  params.VideoUrl != nil,
+		"video_key":  params.VideoKey != nil,
+		"resolution": params.Resolution != nil,
+	}
+
+	switch params.Type {
+	case "song":
+		for name, set := range videoOnly {
+			if set {
+				fields[name] = "only applies to videos"
+			}
+		}
+		if (params.AudioUrl == nil) == (params.AudioKey == nil) {
+			fields["audio_url"] = "exactly one of audio_url and audio_key is required"
+		}
+		if params.AlbumID == nil && (params.TrackNumber != nil || params.DiscNumber != nil) {
+			fields["track_number"] = "needs an album_id"
+		}
+	case "video":
+		for name, set := range songOnly {
+			if set {
+				fields[name] = "only applies to songs"
+			}
+		}
+		if (params.VideoUrl == nil) == (params.VideoKey == nil) {
+			fields["video_url"] = "exactly one of video_url and video_key is required"
+		}
+	}
+	return fields
+}
+
+// checkStorageKey makes sure an uploaded file exists before media points at it
+func (s *MediaService) checkStorageKey(ctx context.Context, key string) *utils.AppError {
+	_, err := s.cfg.Storage.Stat(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		return &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "No uploaded file under storage key " + key,
+		}
+	}
+	if err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to check uploaded file",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// GetMedia returns a song or video. Taken down media is only shown to admins
+// and the artist's members; explicit media only to viewers allowed to see it.
+func (s *MediaService) GetMedia(ctx context.Context, viewer MediaViewer, mediaID uuid.UUID) (MediaItem, *utils.AppError) {
+	item, appErr := s.getMedia(ctx, mediaID)
+	if appErr != nil {
+		return MediaItem{}, appErr
+	}
+	if item.Medium.TakenDownAt.Valid && !s.canSeeTakenDown(ctx, viewer.Actor, item.Medium) {
+		return MediaItem{}, mediaNotFoundError()
+	}
+	if item.Medium.Explicit && !viewer.ExplicitAllowed {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Explicit content is blocked for this profile",
+			Err:     ErrExplicitBlocked,
+		}
+	}
+	return item, nil
+}
+
+type ListMediaParams struct {
+	Page     pagination.Params
+	Type     string `validate:"omitempty,oneof=song video"`
+	ArtistID *uuid.UUID
+	AlbumID  *uuid.UUID
+	// IncludeTakenDown is only honored for admins
+	IncludeTakenDown bool
+}
+
+type ListMediaResult struct {
+	Media []MediaItem
+	// Next is the cursor of the last media, nil on the last page
+	Next *pagination.Cursor
+}
+
+// ListMedia returns media newest first. Explicit media is left out for viewers
+// who may not see it and taken down media unless an admin asks for it.
+func (s *MediaService) ListMedia(ctx context.Context, viewer MediaViewer, params ListMediaParams) (ListMediaResult, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return ListMediaResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "type must be song or video",
+		}
+	}
+	if params.IncludeTakenDown && !viewer.Actor.Admin {
+		return ListMediaResult{}, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Admin access required to list taken down media",
+		}
+	}
+
+	args := database.ListMediaParams{
+		IncludeExplicit:  viewer.ExplicitAllowed,
+		IncludeTakenDown: params.IncludeTakenDown,
+		CursorCreatedAt:  params.Page.CursorCreatedAt(),
+		CursorID:         params.Page.CursorID(),
+		Lim:              params.Page.FetchLimit(),
+	}
+	if params.Type != "" {
+		args.Type = sql.NullString{String: params.Type, Valid: true}
+	}
+	if params.ArtistID != nil {
+		args.ArtistID = uuid.NullUUID{UUID: *params.ArtistID, Valid: true}
+	}
+	if params.AlbumID != nil {
+		args.AlbumID = uuid.NullUUID{UUID: *params.AlbumID, Valid: true}
+	}
+
+	rows, err := s.DB.ListMedia(ctx, args)
+	if err != nil {
+		return ListMediaResult{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch media",
+			Err:     err,
+		}
+	}
+	rows, more := pagination.Trim(rows, params.Page)
+	result := ListMediaResult{Media: make([]MediaItem, len(rows))}
+	for i, row := range rows {
+		result.Media[i] = MediaItem(row)
+	}
+	if more {
+		last := rows[len(rows)-1].Medium
+		result.Next = &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return result, nil
+}
+
+// MediaPatch is a JSON merge patch of a song or video.
+// Absent fields are left alone and null clears a field; the title and explicit flag can't be cleared.
+type MediaPatch struct {
+	Title    patch.Field[string] `json:"title"`
+	Duration patch.Field[int32]  `json:"duration"`
+	Explicit patch.Field[bool]   `json:"explicit"`
+	// Bitrate only applies to songs
+	Bitrate patch.Field[int32] `json:"bitrate"`
+	// Resolution only applies to videos
+	Resolution patch.Field[string] `json:"resolution"`
+}
+
+// PatchMedia applies a merge patch to a song or video. Album and track
+// placement is managed through the album's tracklist.
+func (s *MediaService) PatchMedia(ctx context.Context, actor ArtistActor, mediaID uuid.UUID, p MediaPatch) (MediaItem, *utils.AppError) {
+	current, appErr := s.getMedia(ctx, mediaID)
+	if appErr != nil {
+		return MediaItem{}, appErr
+	}
+	if appErr := authorizeArtist(ctx, s.DB, actor, current.Medium.ArtistID.UUID, ArtistManageCatalog); appErr != nil {
+		return MediaItem{}, appErr
+	}
+	if p.Title.Present() {
+		p.Title.Value = strings.TrimSpace(p.Title.Value)
+	}
+
+	fields := map[string]string{}
+	if p.Title.Null {
+		fields["title"] = "can't be cleared"
+	} else if p.Title.Present() && validate.Var(p.Title.Value, "required,max=150") != nil {
+		fields["title"] = "must be 1 to 150 characters"
+	}
+	if p.Duration.Present() && validate.Var(p.Duration.Value, "min=1,max=86400") != nil {
+		fields["duration"] = "must be 1 to 86400 seconds"
+	}
+	if p.Explicit.Null {
+		fields["explicit"] = "can't be cleared"
+	}
+	switch {
+	case p.Bitrate.Set && current.Medium.Type != "song":
+		fields["bitrate"] = "only applies to songs"
+	case p.Bitrate.Present() && validate.Var(p.Bitrate.Value, "min=8,max=9216") != nil:
+		fields["bitrate"] = "must be 8 to 9216 kbps"
+	}
+	switch {
+	case p.Resolution.Set && current.Medium.Type != "video":
+		fields["resolution"] = "only applies to videos"
+	case p.Resolution.Present() && validate.Var(p.Resolution.Value, "max=20") != nil:
+		fields["resolution"] = "must be at most 20 characters"
+	}
+	if len(fields) > 0 {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if _, err := q.PatchMedia(ctx, database.PatchMediaParams{
+			ID:          mediaID,
+			SetTitle:    p.Title.Set,
+			Title:       p.Title.Value,
+			SetDuration: p.Duration.Set,
+			Duration:    toNullInt32(p.Duration.Ptr()),
+			SetExplicit: p.Explicit.Set,
+			Explicit:    p.Explicit.Value,
+		}); err != nil {
+			return err
+		}
+		if p.Bitrate.Set {
+			if err := q.PatchSong(ctx, database.PatchSongParams{
+				ID:         mediaID,
+				SetBitrate: true,
+				Bitrate:    toNullInt32(p.Bitrate.Ptr()),
+			}); err != nil {
+				return err
+			}
+		}
+		if p.Resolution.Set {
+			return q.PatchVideo(ctx, database.PatchVideoParams{
+				ID:            mediaID,
+				SetResolution: true,
+				Resolution:    utils.ToNullString(p.Resolution.Ptr()),
+			})
+		}
+		return nil
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return MediaItem{}, mediaNotFoundError()
+	}
+	if err != nil {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update media",
+			Err:     err,
+		}
+	}
+	return s.getMedia(ctx, mediaID)
+}
+
+// TakeDown hides media from listeners, for instance after a rights complaint.
+// The media and its files are kept so the takedown can be reversed.
+func (s *MediaService) TakeDown(ctx context.Context, mediaID uuid.UUID, reason string) (MediaItem, *utils.AppError) {
+	reason = strings.TrimSpace(reason)
+	if validate.Var(reason, "required,max=2000") != nil {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  map[string]string{"reason": "must be 1 to 2000 characters"},
+		}
+	}
+
+	_, err := s.DB.TakeDownMedia(ctx, database.TakeDownMediaParams{
+		ID:             mediaID,
+		TakedownReason: sql.NullString{String: reason, Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return MediaItem{}, mediaNotFoundError()
+	}
+	if err != nil {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to take down media",
+			Err:     err,
+		}
+	}
+	return s.getMedia(ctx, mediaID)
+}
+
+// Restore reverses a takedown
+func (s *MediaService) Restore(ctx context.Context, mediaID uuid.UUID) (MediaItem, *utils.AppError) {
+	_, err := s.DB.RestoreMedia(ctx, mediaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return MediaItem{}, mediaNotFoundError()
+	}
+	if err != nil {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to restore media",
+			Err:     err,
+		}
+	}
+	return s.getMedia(ctx, mediaID)
+}
+
+// StreamURL is where the viewer can fetch the media's audio or video: a
+// presigned URL for uploaded files, the stored URL for media hosted elsewhere.
+func StreamURL(cfg *app.AppConfig, item MediaItem) string {
+	key, url := item.AudioKey, item.AudioUrl
+	if item.Medium.Type == "video" {
+		key, url = item.VideoKey, item.VideoUrl
+	}
+	if key.Valid {
+		signed, err := cfg.Storage.Presign(key.String, streamURLTTL)
+		if err != nil {
+			return ""
+		}
+		return signed
+	}
+	return url.String
+}
+
+// canSeeTakenDown reports whether the actor is an admin or a member of the media's artist
+func (s *MediaService) canSeeTakenDown(ctx context.Context, actor ArtistActor, media database.Medium) bool {
+	if actor.Admin {
+		return true
+	}
+	if !media.ArtistID.Valid {
+		return false
+	}
+	return requireArtistMember(ctx, s.DB, actor, media.ArtistID.UUID) == nil
+}
+
+func (s *MediaService) getMedia(ctx context.Context, mediaID uuid.UUID) (MediaItem, *utils.AppError) {
+	item, err := s.DB.GetMedia(ctx, mediaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return MediaItem{}, mediaNotFoundError()
+	}
+	if err != nil {
+		return MediaItem{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return item, nil
+}
+
+func toNullInt32(v *int32) sql.NullInt32 {
+	if v == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *v, Valid: true}
+}
+
+func mediaNotFoundError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusNotFound,
+		Message: "Media not found",
+	}
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/logger"
+	"github.com/techies/streamify/internal/mailer"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// OwnershipTransferTTL is how long the receiving admin has to accept a transfer
+const OwnershipTransferTTL = 72 * time.Hour
+
+type BootstrapOwnerParams struct {
+	Email    string `validate:"required,email"`
+	Username string `validate:"omitempty,min=3,max=30"`
+	Password string `validate:"required,min=8"`
+}
+
+// BootstrapOwner creates the first owner at install time. An existing account is
+// promoted when its password matches; otherwise a verified account is created.
+// It refuses to run once an owner exists, ownership is transferred from then on.
+func (s *UserService) BootstrapOwner(ctx context.Context, params BootstrapOwnerParams) (database.User, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	if _, err := s.DB.GetOwner(ctx); err == nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "An owner already exists, transfer ownership instead",
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	email := utils.NormalizeEmail(params.Email)
+
+	existing, err := s.DB.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	if err == nil {
+		if match, _, err := s.cfg.Passwords.Verify(params.Password, existing.PasswordHash); err != nil || !match {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid password for existing account",
+			}
+		}
+		if err := s.DB.SetUserRole(ctx, database.SetUserRoleParams{ID: existing.ID, Role: database.UserRoleOwner}); err != nil {
+			return database.User{}, ownerRoleError(err)
+		}
+		return s.GetUser(ctx, existing.ID)
+	}
+
+	if params.Username == "" {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Username is required for a new account",
+		}
+	}
+	if appErr := checkUsername(ctx, s.DB, s.cfg, params.Username, uuid.Nil); appErr != nil {
+		return database.User{}, appErr
+	}
+	if err := s.cfg.PasswordPolicy.Check(params.Password); err != nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Password is not allowed: " + err.Error(),
+		}
+	}
+
+	hash, err := s.cfg.Passwords.Hash(params.Password)
+	if err != nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to hash password",
+			Err:     err,
+		}
+	}
+
+	var user database.User
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, database.CreateUserParams{
+			Username:     params.Username,
+			Email:        email,
+			PasswordHash: hash,
+		})
+		if err != nil {
+			return err
+		}
+		if err := createDefaultProfile(ctx, q, user); err != nil {
+			return err
+		}
+		// The installer owns the address, no verification mail needed
+		if err := q.VerifyUserByTokenByID(ctx, user.ID); err != nil {
+			return err
+		}
+		return q.SetUserRole(ctx, database.SetUserRoleParams{ID: user.ID, Role: database.UserRoleOwner})
+	})
+	if err != nil {
+		return database.User{}, ownerRoleError(err)
+	}
+
+	return s.GetUser(ctx, user.ID)
+}
+
+type StartOwnershipTransferParams struct {
+	OwnerID  uuid.UUID `validate:"required"`
+	ToUserID uuid.UUID `validate:"required"`
+	Password string    `validate:"required"`
+}
+
+// StartOwnershipTransfer offers ownership to an admin. The owner has to confirm
+// their password; nothing changes until the admin accepts.
+func (s *UserService) StartOwnershipTransfer(ctx context.Context, params StartOwnershipTransferParams) (database.OwnershipTransfer, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	owner, appErr := s.GetUser(ctx, params.OwnerID)
+	if appErr != nil {
+		return database.OwnershipTransfer{}, appErr
+	}
+	if owner.Role != database.UserRoleOwner {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Only the owner can transfer ownership",
+		}
+	}
+	if match, _, err := s.cfg.Passwords.Verify(params.Password, owner.PasswordHash); err != nil || !match {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid password",
+		}
+	}
+
+	if params.ToUserID == owner.ID {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "You already own this installation",
+		}
+	}
+
+	target, appErr := s.GetUser(ctx, params.ToUserID)
+	if appErr != nil {
+		return database.OwnershipTransfer{}, appErr
+	}
+	if target.Role != database.UserRoleAdmin || target.IsLocked || !target.IsVerified || target.Status == "deleted" {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Ownership can only be transferred to an active, verified admin",
+		}
+	}
+
+	// A new offer replaces any previous one
+	var transfer database.OwnershipTransfer
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.CancelPendingOwnershipTransfers(ctx); err != nil {
+			return err
+		}
+		var err error
+		transfer, err = q.CreateOwnershipTransfer(ctx, database.CreateOwnershipTransferParams{
+			FromUserID: owner.ID,
+			ToUserID:   target.ID,
+			ExpiresAt:  time.Now().Add(OwnershipTransferTTL),
+		})
+		return err
+	})
+	if err != nil {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to start ownership transfer",
+			Err:     err,
+		}
+	}
+
+	if err := s.cfg.Mailer.Send(ctx, mailer.Message{
+		To:      target.Email,
+		Subject: "You have been offered ownership of Streamify",
+		Body: fmt.Sprintf(
+			"%s wants to make you the owner of this Streamify installation. Sign in and accept the transfer within %d hours:\n\n%s/owner-transfer",
+			owner.Username, int(OwnershipTransferTTL.Hours()), s.cfg.FrontendURL,
+		),
+	}); err != nil {
+		// The admin can still find the offer through the API
+		logger.Error(ctx, "StartOwnershipTransfer: failed to notify admin", err, "user_id", target.ID)
+	}
+
+	return transfer, nil
+}
+
+// GetOwnershipTransfer returns the pending transfer to the owner or the admin it was offered to
+func (s *UserService) GetOwnershipTransfer(ctx context.Context, userID uuid.UUID) (database.OwnershipTransfer, *utils.AppError) {
+	transfer, err := s.DB.GetPendingOwnershipTransfer(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.OwnershipTransfer{}, &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "No pending ownership transfer",
+			}
+		}
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	if (transfer.FromUserID != userID && transfer.ToUserID != userID) || time.Now().After(transfer.ExpiresAt) {
+		return database.OwnershipTransfer{}, &utils.AppError{
+			Code:    http.StatusNotFound,
+			Message: "No pending ownership transfer",
+		}
+	}
+
+	return transfer, nil
+}
+
+// AcceptOwnershipTransfer makes the receiving admin the owner and the previous
+// owner an admin. Both are signed out so their tokens carry the new roles.
+func (s *UserService) AcceptOwnershipTransfer(ctx context.Context, userID uuid.UUID) *utils.AppError {
+	transfer, appErr := s.GetOwnershipTransfer(ctx, userID)
+	if appErr != nil {
+		return appErr
+	}
+	if transfer.ToUserID != userID {
+		return &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Only the receiving admin can accept the transfer",
+		}
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		rows, err := q.CompleteOwnershipTransfer(ctx, transfer.ID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+		// Demote first, the schema allows a single owner
+		if err := q.SetUserRole(ctx, database.SetUserRoleParams{ID: transfer.FromUserID, Role: database.UserRoleAdmin}); err != nil {
+			return err
+		}
+		if err := q.SetUserRole(ctx, database.SetUserRoleParams{ID: transfer.ToUserID, Role: database.UserRoleOwner}); err != nil {
+			return err
+		}
+		if err := q.DeleteAllUserSessions(ctx, transfer.FromUserID); err != nil {
+			return err
+		}
+		return q.DeleteAllUserSessions(ctx, transfer.ToUserID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Ownership transfer is no longer pending",
+			}
+		}
+		return ownerRoleError(err)
+	}
+
+	return nil
+}
+
+// CancelOwnershipTransfer withdraws (owner) or declines (receiving admin) the pending transfer
+func (s *UserService) CancelOwnershipTransfer(ctx context.Context, userID uuid.UUID) *utils.AppError {
+	if _, appErr := s.GetOwnershipTransfer(ctx, userID); appErr != nil {
+		return appErr
+	}
+
+	if err := s.DB.CancelPendingOwnershipTransfers(ctx); err != nil {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to cancel ownership transfer",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// DemoteAllAdmins turns every admin into a regular user and signs them out, e.g.
+// after a compromise. The owner is not affected.
+func (s *UserService) DemoteAllAdmins(ctx context.Context) (int64, *utils.AppError) {
+	var demoted int64
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		// Their access tokens still carry the admin role
+		if err := q.DeleteSessionsByRole(ctx, database.UserRoleAdmin); err != nil {
+			return err
+		}
+		var err error
+		demoted, err = q.DemoteAdminToUser(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to demote admins",
+			Err:     err,
+		}
+	}
+	return demoted, nil
+}
+
+func ownerRoleError(err error) *utils.AppError {
+	if utils.IsUniqueViolation(err) {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Email, username or owner already exists",
+		}
+	}
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: "Failed to update owner",
+		Err:     err,
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/otp"
+	"github.com/techies/streamify/internal/utils"
+)
+
+const (
+	PhoneCodeDigits      = 6
+	PhoneCodeTTL         = 10 * time.Minute
+	PhoneCodeMaxAttempts = 5
+	// PhoneCodeResendDelay throttles how often a new code can be requested
+	PhoneCodeResendDelay = time.Minute
+)
+
+// StartPhoneVerification sends a one-time code to the phone number on the user's profile
+func (s *UserService) StartPhoneVerification(ctx context.Context, userID uuid.UUID) (database.PhoneVerification, *utils.AppError) {
+	user, appErr := s.GetUser(ctx, userID)
+	if appErr != nil {
+		return database.PhoneVerification{}, appErr
+	}
+
+	if !user.PhoneNumber.Valid || user.PhoneNumber.String == "" {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "No phone number on profile",
+		}
+	}
+
+	if user.PhoneVerifiedAt.Valid {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Phone number already verified",
+		}
+	}
+
+	if appErr := s.ensurePhoneAvailable(ctx, user.PhoneNumber.String, user.ID); appErr != nil {
+		return database.PhoneVerification{}, appErr
+	}
+
+	last, err := s.DB.GetLatestPhoneVerification(ctx, user.ID)
+	if err == nil && time.Since(last.CreatedAt) < PhoneCodeResendDelay {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusTooManyRequests,
+			Message: "Please wait before requesting a new code",
+		}
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	code, err := otp.Generate(PhoneCodeDigits)
+	if err != nil {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to generate code",
+			Err:     err,
+		}
+	}
+
+	// A new code invalidates any previous one
+	var verification database.PhoneVerification
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.DeletePendingPhoneVerifications(ctx, user.ID); err != nil {
+			return err
+		}
+		var err error
+		verification, err = q.CreatePhoneVerification(ctx, database.CreatePhoneVerificationParams{
+			UserID:      user.ID,
+			PhoneNumber: user.PhoneNumber.String,
+			CodeHash:    otp.Hash(s.cfg.JWTSecret, code),
+			ExpiresAt:   time.Now().Add(PhoneCodeTTL),
+		})
+		return err
+	})
+	if err != nil {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to start phone verification",
+			Err:     err,
+		}
+	}
+
+	body := fmt.Sprintf("Your Streamify verification code is %s. It expires in %d minutes.", code, int(PhoneCodeTTL.Minutes()))
+	if err := s.cfg.SMS.Send(ctx, user.PhoneNumber.String, body); err != nil {
+		return database.PhoneVerification{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to send verification code",
+			Err:     err,
+		}
+	}
+
+	return verification, nil
+}
+
+// ConfirmPhoneVerification checks code against the user's pending verification and
+// marks the phone number as verified. Other accounts holding the same number
+// without having verified it are released from it.
+func (s *UserService) ConfirmPhoneVerification(ctx context.Context, userID uuid.UUID, code string) (database.User, *utils.AppError) {
+	verification, err := s.DB.GetLatestPhoneVerification(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusBadRequest,
+				Message: "No pending phone verification",
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	if time.Now().After(verification.ExpiresAt) {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Code expired, please request a new one",
+		}
+	}
+
+	if verification.Attempts >= PhoneCodeMaxAttempts {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusTooManyRequests,
+			Message: "Too many attempts, please request a new code",
+		}
+	}
+
+	// Count the attempt before checking so concurrent guesses can't bypass the limit
+	attempts, err := s.DB.IncrementPhoneVerificationAttempts(ctx, verification.ID)
+	if err != nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if attempts > PhoneCodeMaxAttempts {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusTooManyRequests,
+			Message: "Too many attempts, please request a new code",
+		}
+	}
+
+	if !otp.Verify(s.cfg.JWTSecret, code, verification.CodeHash) {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid code",
+		}
+	}
+
+	user, appErr := s.GetUser(ctx, userID)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	// The profile number changed after the code was sent
+	if user.PhoneNumber.String != verification.PhoneNumber {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Phone number changed, please request a new code",
+		}
+	}
+
+	if appErr := s.ensurePhoneAvailable(ctx, verification.PhoneNumber, userID); appErr != nil {
+		return database.User{}, appErr
+	}
+
+	phone := sql.NullString{String: verification.PhoneNumber, Valid: true}
+	err = runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.MarkPhoneVerificationUsed(ctx, verification.ID); err != nil {
+			return err
+		}
+		if err := q.MarkPhoneVerified(ctx, database.MarkPhoneVerifiedParams{
+			ID:          userID,
+			PhoneNumber: phone,
+		}); err != nil {
+			return err
+		}
+		return q.ReleaseUnverifiedPhoneNumber(ctx, database.ReleaseUnverifiedPhoneNumberParams{
+			PhoneNumber: phone,
+			ID:          userID,
+		})
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Phone number already verified by another account",
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to verify phone number",
+			Err:     err,
+		}
+	}
+
+	return s.GetUser(ctx, userID)
+}
+
+// ensurePhoneAvailable returns a conflict error if another account has verified phone
+func (s *UserService) ensurePhoneAvailable(ctx context.Context, phone string, userID uuid.UUID) *utils.AppError {
+	owner, err := s.DB.GetUserByVerifiedPhone(ctx, sql.NullString{String: phone, Valid: true})
+	if err == nil && owner.ID != userID {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Phone number already verified by another account",
+		}
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	return nil
+}
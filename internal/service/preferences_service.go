@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/preferences"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// UserPreferences is a user's preferences document and the revision it was read at
+type UserPreferences struct {
+	Preferences preferences.Preferences
+	// Revision is 0 while the user has never saved preferences
+	Revision  int64
+	UpdatedAt *time.Time
+}
+
+// GetPreferences returns the user's preferences, or the defaults when never saved
+func (s *UserService) GetPreferences(ctx context.Context, userID uuid.UUID) (UserPreferences, *utils.AppError) {
+	row, err := s.DB.GetUserPreferences(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserPreferences{Preferences: preferences.Defaults()}, nil
+	}
+	if err != nil {
+		return UserPreferences{}, preferencesError("Failed to load preferences", err)
+	}
+	return loadPreferences(row)
+}
+
+// PatchPreferences applies a JSON merge patch to the user's preferences.
+// The write only happens if the preferences are still at expectedRevision;
+// nil skips the check.
+func (s *UserService) PatchPreferences(ctx context.Context, userID uuid.UUID, expectedRevision *int64, mergePatch []byte) (UserPreferences, *utils.AppError) {
+	current, appErr := s.GetPreferences(ctx, userID)
+	if appErr != nil {
+		return UserPreferences{}, appErr
+	}
+	if expectedRevision != nil && *expectedRevision != current.Revision {
+		return UserPreferences{}, preferencesChangedError()
+	}
+
+	next, err := preferences.Apply(current.Preferences, mergePatch)
+	if err != nil {
+		return UserPreferences{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid preferences patch",
+			Err:     err,
+		}
+	}
+	if fields := preferences.Validate(next); len(fields) > 0 {
+		return UserPreferences{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+
+	paid, err := s.DB.HasActivePaidSubscription(ctx, userID)
+	if err != nil {
+		return UserPreferences{}, preferencesError("Database error", err)
+	}
+	tier := preferences.TierFree
+	if paid {
+		tier = preferences.TierPremium
+	}
+	if fields := preferences.CheckTier(current.Preferences, next, tier); len(fields) > 0 {
+		return UserPreferences{}, &utils.AppError{
+			Code:    http.StatusForbidden,
+			Message: "Not available on your plan",
+			Fields:  fields,
+		}
+	}
+
+	doc, err := json.Marshal(next)
+	if err != nil {
+		return UserPreferences{}, preferencesError("Failed to save preferences", err)
+	}
+	// The revision guard catches writes that landed since current was read
+	row, err := s.DB.SaveUserPreferences(ctx, database.SaveUserPreferencesParams{
+		UserID:           userID,
+		Version:          preferences.Version,
+		Document:         doc,
+		ExpectedRevision: current.Revision,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserPreferences{}, preferencesChangedError()
+	}
+	if err != nil {
+		return UserPreferences{}, preferencesError("Failed to save preferences", err)
+	}
+	return loadPreferences(row)
+}
+
+func loadPreferences(row database.UserPreference) (UserPreferences, *utils.AppError) {
+	p, err := preferences.Load(int(row.Version), row.Document)
+	if err != nil {
+		return UserPreferences{}, preferencesError("Failed to load preferences", err)
+	}
+	return UserPreferences{Preferences: p, Revision: row.Revision, UpdatedAt: &row.UpdatedAt}, nil
+}
+
+func preferencesChangedError() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusPreconditionFailed,
+		Message: "Preferences were changed by another device; fetch them again and retry",
+	}
+}
+
+func preferencesError(msg string, err error) *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: msg,
+		Err:     err,
+	}
+}
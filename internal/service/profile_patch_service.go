@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/utils"
+)
+
+// ProfilePatch is a JSON merge patch of the user's own profile.
+// Absent fields are left alone and null clears a field.
+type ProfilePatch struct {
+	FirstName   patch.Field[string] `json:"first_name"`
+	LastName    patch.Field[string] `json:"last_name"`
+	Bio         patch.Field[string] `json:"bio"`
+	AvatarUrl   patch.Field[string] `json:"avatar_url"`
+	PhoneNumber patch.Field[string] `json:"phone_number"`
+	// BirthDate is YYYY-MM-DD; it can be set once and never cleared
+	BirthDate      patch.Field[string] `json:"birth_date"`
+	Country        patch.Field[string] `json:"country"`
+	FilterExplicit patch.Field[bool]   `json:"filter_explicit"`
+}
+
+// profilePatchRules are the validator tags and messages for the string fields
+var profilePatchRules = []struct {
+	name, tag, message string
+	field              func(*ProfilePatch) *patch.Field[string]
+}{
+	{"first_name", "max=100", "must be at most 100 characters", func(p *ProfilePatch) *patch.Field[string] { return &p.FirstName }},
+	{"last_name", "max=100", "must be at most 100 characters", func(p *ProfilePatch) *patch.Field[string] { return &p.LastName }},
+	{"bio", "max=500", "must be at most 500 characters", func(p *ProfilePatch) *patch.Field[string] { return &p.Bio }},
+	{"avatar_url", "http_url,max=2048", "must be an http or https URL", func(p *ProfilePatch) *patch.Field[string] { return &p.AvatarUrl }},
+	{"phone_number", "e164", "must be in E.164 format, e.g. +14155552671", func(p *ProfilePatch) *patch.Field[string] { return &p.PhoneNumber }},
+	{"country", "iso3166_1_alpha2", "must be an ISO 3166-1 alpha-2 country code", func(p *ProfilePatch) *patch.Field[string] { return &p.Country }},
+}
+
+// PatchProfile applies a merge patch to the user's profile. Validation errors
+// are reported per field in AppError.Fields.
+func (s *UserService) PatchProfile(ctx context.Context, userID uuid.UUID, p ProfilePatch) (database.User, *utils.AppError) {
+	if p.Country.Present() {
+		p.Country.Value = strings.ToUpper(strings.TrimSpace(p.Country.Value))
+	}
+
+	fields := map[string]string{}
+	for _, rule := range profilePatchRules {
+		f := rule.field(&p)
+		if f.Present() && validate.Var(f.Value, rule.tag) != nil {
+			fields[rule.name] = rule.message
+		}
+	}
+
+	var birthDate time.Time
+	if p.BirthDate.Set {
+		if p.BirthDate.Null {
+			fields["birth_date"] = "can't be cleared"
+		} else if bd, appErr := parseBirthDate(p.BirthDate.Value); appErr != nil {
+			fields["birth_date"] = "must be a valid date formatted as YYYY-MM-DD"
+		} else {
+			birthDate = bd
+		}
+	}
+
+	if len(fields) > 0 {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+
+	current, appErr := s.GetUser(ctx, userID)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	// A new phone number has to be verified again, a cleared one loses its verification
+	phoneChanged := p.PhoneNumber.Set && (p.PhoneNumber.Null && current.PhoneNumber.Valid ||
+		p.PhoneNumber.Present() && p.PhoneNumber.Value != current.PhoneNumber.String)
+	if phoneChanged && p.PhoneNumber.Present() {
+		if appErr := s.ensurePhoneAvailable(ctx, p.PhoneNumber.Value, userID); appErr != nil {
+			return database.User{}, appErr
+		}
+	}
+
+	if p.BirthDate.Present() {
+		profile, err := s.DB.GetUserProfile(ctx, userID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+		if profile.BirthDate.Valid && !profile.BirthDate.Time.Equal(birthDate) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "Birth date can't be changed, please contact support",
+				Fields:  map[string]string{"birth_date": "can't be changed"},
+			}
+		}
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if err := q.PatchUserProfile(ctx, database.PatchUserProfileParams{
+			ID:             userID,
+			SetFirstName:   p.FirstName.Set,
+			FirstName:      utils.ToNullString(p.FirstName.Ptr()),
+			SetLastName:    p.LastName.Set,
+			LastName:       utils.ToNullString(p.LastName.Ptr()),
+			SetBio:         p.Bio.Set,
+			Bio:            utils.ToNullString(p.Bio.Ptr()),
+			SetAvatarUrl:   p.AvatarUrl.Set,
+			AvatarUrl:      utils.ToNullString(p.AvatarUrl.Ptr()),
+			SetPhoneNumber: p.PhoneNumber.Set,
+			PhoneNumber:    utils.ToNullString(p.PhoneNumber.Ptr()),
+		}); err != nil {
+			return err
+		}
+
+		if p.Country.Set || p.BirthDate.Set || p.FilterExplicit.Set {
+			// A null filter_explicit goes back to the default, which is off
+			if err := q.PatchUserProfileSettings(ctx, database.PatchUserProfileSettingsParams{
+				UserID:            userID,
+				SetCountry:        p.Country.Set,
+				Country:           utils.ToNullString(p.Country.Ptr()),
+				SetBirthDate:      p.BirthDate.Set,
+				BirthDate:         sql.NullTime{Time: birthDate, Valid: p.BirthDate.Present()},
+				SetFilterExplicit: p.FilterExplicit.Set,
+				FilterExplicit:    sql.NullBool{Bool: p.FilterExplicit.Value, Valid: p.FilterExplicit.Set},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if !phoneChanged {
+			return nil
+		}
+		if err := q.ResetPhoneVerification(ctx, userID); err != nil {
+			return err
+		}
+		return q.DeletePendingPhoneVerifications(ctx, userID)
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Phone number is already in use",
+				Fields:  map[string]string{"phone_number": "is already in use"},
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update profile",
+			Err:     err,
+		}
+	}
+
+	// Uploaded renditions are no longer referenced once the URL is set by hand
+	if p.AvatarUrl.Set && current.AvatarKey.Valid {
+		deleteImage(ctx, s.cfg, current.AvatarKey.String)
+	}
+
+	return s.GetUser(ctx, userID)
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/patch"
+	"github.com/techies/streamify/internal/utils"
+)
+
+const (
+	publicPlaylistLimit   = 20
+	followedArtistLimit   = 20
+	topArtistLimit        = 5
+	privacyLevelValidator = "oneof=public followers private"
+)
+
+// defaultPrivacySettings apply until the user saves their own
+func defaultPrivacySettings(userID uuid.UUID) database.UserPrivacySetting {
+	return database.UserPrivacySetting{
+		UserID:          userID,
+		Profile:         database.PrivacyLevelPublic,
+		DisplayName:     database.PrivacyLevelPublic,
+		Avatar:          database.PrivacyLevelPublic,
+		Bio:             database.PrivacyLevelPublic,
+		Country:         database.PrivacyLevelPrivate,
+		Playlists:       database.PrivacyLevelPublic,
+		FollowedArtists: database.PrivacyLevelFollowers,
+		ListeningStats:  database.PrivacyLevelPrivate,
+	}
+}
+
+// viewerRelation is how the viewer of a profile relates to its owner
+type viewerRelation int
+
+const (
+	// relationBlocked means either user blocked the other
+	relationBlocked viewerRelation = iota
+	relationStranger
+	// relationRequested is a follow request waiting for approval
+	relationRequested
+	relationFollower
+	relationSelf
+)
+
+func (r viewerRelation) canSee(level database.PrivacyLevel) bool {
+	if r == relationBlocked {
+		return false
+	}
+	switch level {
+	case database.PrivacyLevelPublic:
+		return true
+	case database.PrivacyLevelFollowers:
+		return r >= relationFollower
+	default:
+		return r == relationSelf
+	}
+}
+
+// relationTo resolves the viewer's relation to the profile owner
+func (s *UserService) relationTo(ctx context.Context, ownerID, viewerID uuid.UUID) (viewerRelation, error) {
+	if ownerID == viewerID {
+		return relationSelf, nil
+	}
+
+	block, err := s.DB.GetBlockBetween(ctx, database.GetBlockBetweenParams{UserA: ownerID, UserB: viewerID})
+	if err != nil {
+		return relationStranger, err
+	}
+	if block.ABlockedB || block.BBlockedA {
+		return relationBlocked, nil
+	}
+
+	follow, err := s.DB.GetUserFollow(ctx, database.GetUserFollowParams{FollowerID: viewerID, FolloweeID: ownerID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return relationStranger, nil
+	}
+	if err != nil {
+		return relationStranger, err
+	}
+	if follow.Status == database.FollowStatusPending {
+		return relationRequested, nil
+	}
+	return relationFollower, nil
+}
+
+// GetPrivacySettings returns the user's privacy settings, or the defaults when never saved
+func (s *UserService) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (database.UserPrivacySetting, *utils.AppError) {
+	settings, err := s.DB.GetPrivacySettings(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return defaultPrivacySettings(userID), nil
+	}
+	if err != nil {
+		return database.UserPrivacySetting{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to load privacy settings",
+			Err:     err,
+		}
+	}
+	return settings, nil
+}
+
+// PrivacyPatch is a JSON merge patch of the privacy settings; null restores a field's default
+type PrivacyPatch struct {
+	Profile         patch.Field[string] `json:"profile"`
+	DisplayName     patch.Field[string] `json:"display_name"`
+	Avatar          patch.Field[string] `json:"avatar"`
+	Bio             patch.Field[string] `json:"bio"`
+	Country         patch.Field[string] `json:"country"`
+	Playlists       patch.Field[string] `json:"playlists"`
+	FollowedArtists patch.Field[string] `json:"followed_artists"`
+	ListeningStats  patch.Field[string] `json:"listening_stats"`
+	// RequireFollowApproval holds new followers as requests; null turns it off
+	RequireFollowApproval patch.Field[bool] `json:"require_follow_approval"`
+}
+
+// PatchPrivacySettings applies a merge patch to the user's privacy settings
+func (s *UserService) PatchPrivacySettings(ctx context.Context, userID uuid.UUID, p PrivacyPatch) (database.UserPrivacySetting, *utils.AppError) {
+	current, appErr := s.GetPrivacySettings(ctx, userID)
+	if appErr != nil {
+		return database.UserPrivacySetting{}, appErr
+	}
+	defaults := defaultPrivacySettings(userID)
+
+	fields := map[string]string{}
+	apply := func(name string, f patch.Field[string], dst *database.PrivacyLevel, def database.PrivacyLevel) {
+		switch {
+		case !f.Set:
+		case f.Null:
+			*dst = def
+		case validate.Var(strings.ToLower(f.Value), privacyLevelValidator) != nil:
+			fields[name] = "must be one of public, followers, private"
+		default:
+			*dst = database.PrivacyLevel(strings.ToLower(f.Value))
+		}
+	}
+	apply("profile", p.Profile, &current.Profile, defaults.Profile)
+	apply("display_name", p.DisplayName, &current.DisplayName, defaults.DisplayName)
+	apply("avatar", p.Avatar, &current.Avatar, defaults.Avatar)
+	apply("bio", p.Bio, &current.Bio, defaults.Bio)
+	apply("country", p.Country, &current.Country, defaults.Country)
+	apply("playlists", p.Playlists, &current.Playlists, defaults.Playlists)
+	apply("followed_artists", p.FollowedArtists, &current.FollowedArtists, defaults.FollowedArtists)
+	apply("listening_stats", p.ListeningStats, &current.ListeningStats, defaults.ListeningStats)
+	if len(fields) > 0 {
+		return database.UserPrivacySetting{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Fields:  fields,
+		}
+	}
+
+	// Pending requests are accepted once approval is no longer required
+	approvalDropped := current.RequireFollowApproval && p.RequireFollowApproval.Set && !p.RequireFollowApproval.Value
+	if p.RequireFollowApproval.Set {
+		current.RequireFollowApproval = p.RequireFollowApproval.Value
+	}
+
+	var settings database.UserPrivacySetting
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		var err error
+		settings, err = q.UpsertPrivacySettings(ctx, database.UpsertPrivacySettingsParams{
+			UserID:                userID,
+			Profile:               current.Profile,
+			DisplayName:           current.DisplayName,
+			Avatar:                current.Avatar,
+			Bio:                   current.Bio,
+			Country:               current.Country,
+			Playlists:             current.Playlists,
+			FollowedArtists:       current.FollowedArtists,
+			ListeningStats:        current.ListeningStats,
+			RequireFollowApproval: current.RequireFollowApproval,
+		})
+		if err != nil || !approvalDropped {
+			return err
+		}
+		return q.AcceptAllFollowRequests(ctx, userID)
+	})
+	if err != nil {
+		return database.UserPrivacySetting{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to save privacy settings",
+			Err:     err,
+		}
+	}
+	return settings, nil
+}
+
+// ListeningStats summarises what the account holder listened to
+type ListeningStats struct {
+	StreamCount      int64
+	DistinctMedia    int64
+	ListeningSeconds int64
+	TopArtists       []database.ListTopArtistsRow
+}
+
+// PublicProfile is the projection of a user others may see. Hidden parts are nil.
+type PublicProfile struct {
+	UserID          uuid.UUID
+	Username        string
+	DisplayName     *string
+	AvatarUrl       *string
+	Bio             *string
+	Country         *string
+	Playlists       []database.ListPublicPlaylistsRow
+	FollowedArtists []database.ListFollowedArtistsRow
+	Stats           *ListeningStats
+	FollowerCount   int64
+	FollowingCount  int64
+	// FollowStatus is the viewer's follow of this user, empty when not following
+	FollowStatus database.FollowStatus
+}
+
+// profileNotFound is returned both for missing and hidden profiles so the two can't be told apart
+func profileNotFound() *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusNotFound,
+		Message: "Profile not found",
+	}
+}
+
+// LookupPublicProfile is GetPublicProfile for a current or recently given up
+// username. redirected reports an old name so callers can point to the new one;
+// hidden profiles are not found under either.
+func (s *UserService) LookupPublicProfile(ctx context.Context, username string, viewerID uuid.UUID) (profile PublicProfile, redirected bool, appErr *utils.AppError) {
+	user, redirected, appErr := s.ResolveUsername(ctx, username)
+	if appErr != nil {
+		if appErr.Code == http.StatusNotFound {
+			return PublicProfile{}, false, profileNotFound()
+		}
+		return PublicProfile{}, false, appErr
+	}
+	profile, appErr = s.GetPublicProfile(ctx, user.Username, viewerID)
+	if appErr != nil {
+		return PublicProfile{}, false, appErr
+	}
+	return profile, redirected, nil
+}
+
+// GetPublicProfileByID is GetPublicProfile for a user ID
+func (s *UserService) GetPublicProfileByID(ctx context.Context, userID, viewerID uuid.UUID) (PublicProfile, *utils.AppError) {
+	user, appErr := s.GetUser(ctx, userID)
+	if appErr != nil {
+		if appErr.Code == http.StatusNotFound {
+			return PublicProfile{}, profileNotFound()
+		}
+		return PublicProfile{}, appErr
+	}
+	return s.GetPublicProfile(ctx, user.Username, viewerID)
+}
+
+// GetPublicProfile returns what viewerID may see of username's profile
+func (s *UserService) GetPublicProfile(ctx context.Context, username string, viewerID uuid.UUID) (PublicProfile, *utils.AppError) {
+	user, err := s.DB.GetPublicProfileByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PublicProfile{}, profileNotFound()
+	}
+	if err != nil {
+		return PublicProfile{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	settings, appErr := s.GetPrivacySettings(ctx, user.ID)
+	if appErr != nil {
+		return PublicProfile{}, appErr
+	}
+	rel, err := s.relationTo(ctx, user.ID, viewerID)
+	if err != nil {
+		return PublicProfile{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+	if !rel.canSee(settings.Profile) {
+		return PublicProfile{}, profileNotFound()
+	}
+
+	counts, err := s.DB.GetFollowCounts(ctx, user.ID)
+	if err != nil {
+		return PublicProfile{}, publicProfileError(err)
+	}
+	profile := PublicProfile{
+		UserID:         user.ID,
+		Username:       user.Username,
+		FollowerCount:  counts.Followers,
+		FollowingCount: counts.Following,
+	}
+	switch rel {
+	case relationRequested:
+		profile.FollowStatus = database.FollowStatusPending
+	case relationFollower:
+		profile.FollowStatus = database.FollowStatusAccepted
+	}
+	if rel.canSee(settings.DisplayName) {
+		if name := strings.TrimSpace(user.FirstName.String + " " + user.LastName.String); name != "" {
+			profile.DisplayName = &name
+		}
+	}
+	if rel.canSee(settings.Avatar) && user.AvatarUrl.Valid {
+		profile.AvatarUrl = &user.AvatarUrl.String
+	}
+	if rel.canSee(settings.Bio) && user.Bio.Valid {
+		profile.Bio = &user.Bio.String
+	}
+	if rel.canSee(settings.Country) && user.Country.Valid {
+		profile.Country = &user.Country.String
+	}
+
+	if rel.canSee(settings.Playlists) {
+		if profile.Playlists, err = s.DB.ListPublicPlaylists(ctx, database.ListPublicPlaylistsParams{
+			UserID: user.ID,
+			Limit:  publicPlaylistLimit,
+		}); err != nil {
+			return PublicProfile{}, publicProfileError(err)
+		}
+	}
+	if rel.canSee(settings.FollowedArtists) {
+		if profile.FollowedArtists, err = s.DB.ListFollowedArtists(ctx, database.ListFollowedArtistsParams{
+			FollowerID: user.ID,
+			Limit:      followedArtistLimit,
+		}); err != nil {
+			return PublicProfile{}, publicProfileError(err)
+		}
+	}
+	if rel.canSee(settings.ListeningStats) {
+		stats, err := s.DB.GetListeningStats(ctx, user.ID)
+		if err != nil {
+			return PublicProfile{}, publicProfileError(err)
+		}
+		top, err := s.DB.ListTopArtists(ctx, database.ListTopArtistsParams{UserID: user.ID, Limit: topArtistLimit})
+		if err != nil {
+			return PublicProfile{}, publicProfileError(err)
+		}
+		profile.Stats = &ListeningStats{
+			StreamCount:      stats.StreamCount,
+			DistinctMedia:    stats.DistinctMedia,
+			ListeningSeconds: stats.ListeningSeconds,
+			TopArtists:       top,
+		}
+	}
+
+	return profile, nil
+}
+
+func publicProfileError(err error) *utils.AppError {
+	return &utils.AppError{
+		Code:    http.StatusInternalServerError,
+		Message: "Failed to load profile",
+		Err:     err,
+	}
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/techies/streamify/internal/database"
 )
@@ -18,3 +21,19 @@ type BaseService struct {
 func NewBaseService(db *database.Queries) BaseService {
 	return BaseService{DB: db}
 }
+
+// runInTx executes fn with queries bound to a single transaction.
+// The transaction is rolled back if fn returns an error.
+func runInTx(ctx context.Context, conn *sql.DB, db *database.Queries, fn func(q *database.Queries) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(db.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/techies/streamify/internal/app"
 	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/pagination"
 	"github.com/techies/streamify/internal/utils"
 )
 
@@ -31,9 +34,17 @@ type UpdateProfileParams struct {
 	Bio         *string
 	AvatarUrl   *string
 	PhoneNumber *string
+	// BirthDate is YYYY-MM-DD; once set it can't be changed by the user
+	BirthDate      *string
+	Country        *string `validate:"omitempty,iso3166_1_alpha2"`
+	FilterExplicit *bool
 }
 
 func (s *UserService) UpdateProfile(ctx context.Context, params UpdateProfileParams) (database.User, *utils.AppError) {
+	if params.Country != nil {
+		country := strings.ToUpper(strings.TrimSpace(*params.Country))
+		params.Country = &country
+	}
 	if err := validate.Struct(params); err != nil {
 		return database.User{}, &utils.AppError{
 			Code:    http.StatusBadRequest,
@@ -42,13 +53,47 @@ func (s *UserService) UpdateProfile(ctx context.Context, params UpdateProfilePar
 		}
 	}
 
-	err := s.DB.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
-		ID:          params.UserID,
-		FirstName:   utils.ToNullString(params.FirstName),
-		LastName:    utils.ToNullString(params.LastName),
-		Bio:         utils.ToNullString(params.Bio),
-		AvatarUrl:   utils.ToNullString(params.AvatarUrl),
-		PhoneNumber: utils.ToNullString(params.PhoneNumber),
+	current, appErr := s.GetUser(ctx, params.UserID)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	// A new phone number has to be verified again
+	phoneChanged := params.PhoneNumber != nil && *params.PhoneNumber != current.PhoneNumber.String
+	if phoneChanged {
+		if appErr := s.ensurePhoneAvailable(ctx, *params.PhoneNumber, params.UserID); appErr != nil {
+			return database.User{}, appErr
+		}
+	}
+
+	profile, appErr := s.profileUpdate(ctx, params)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if profile != nil {
+			if err := q.UpsertUserProfile(ctx, *profile); err != nil {
+				return err
+			}
+		}
+		if err := q.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
+			ID:          params.UserID,
+			FirstName:   utils.ToNullString(params.FirstName),
+			LastName:    utils.ToNullString(params.LastName),
+			Bio:         utils.ToNullString(params.Bio),
+			AvatarUrl:   utils.ToNullString(params.AvatarUrl),
+			PhoneNumber: utils.ToNullString(params.PhoneNumber),
+		}); err != nil {
+			return err
+		}
+		if !phoneChanged {
+			return nil
+		}
+		if err := q.ResetPhoneVerification(ctx, params.UserID); err != nil {
+			return err
+		}
+		return q.DeletePendingPhoneVerifications(ctx, params.UserID)
 	})
 
 	if err != nil {
@@ -71,6 +116,47 @@ func (s *UserService) UpdateProfile(ctx context.Context, params UpdateProfilePar
 	return user, nil
 }
 
+// profileUpdate builds the user_profiles update for the age gating fields, or nil when none are set.
+// A birth date can only be set once so minors can't unlock explicit content themselves.
+func (s *UserService) profileUpdate(ctx context.Context, params UpdateProfileParams) (*database.UpsertUserProfileParams, *utils.AppError) {
+	if params.BirthDate == nil && params.Country == nil && params.FilterExplicit == nil {
+		return nil, nil
+	}
+
+	update := &database.UpsertUserProfileParams{
+		UserID:  params.UserID,
+		Country: utils.ToNullString(params.Country),
+	}
+	if params.FilterExplicit != nil {
+		update.FilterExplicit = sql.NullBool{Bool: *params.FilterExplicit, Valid: true}
+	}
+
+	if params.BirthDate != nil {
+		birthDate, appErr := parseBirthDate(*params.BirthDate)
+		if appErr != nil {
+			return nil, appErr
+		}
+
+		current, err := s.DB.GetUserProfile(ctx, params.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Database error",
+				Err:     err,
+			}
+		}
+		if current.BirthDate.Valid && !current.BirthDate.Time.Equal(birthDate) {
+			return nil, &utils.AppError{
+				Code:    http.StatusForbidden,
+				Message: "Birth date can't be changed, please contact support",
+			}
+		}
+		update.BirthDate = sql.NullTime{Time: birthDate, Valid: true}
+	}
+
+	return update, nil
+}
+
 func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (database.User, *utils.AppError) {
 	user, err := s.DB.GetUserById(ctx, id)
 	if err != nil {
@@ -89,29 +175,75 @@ func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (database.User,
 	return user, nil
 }
 
+// User list sort orders
+const (
+	UserSortNewest    = "created_at"
+	UserSortUsername  = "username"
+	UserSortRelevance = "relevance"
+)
+
 type ListUsersParams struct {
-	Limit       int32
-	Offset      int32
-	Username    string
-	Email       string
-	PhoneNumber string
+	Page pagination.Params
+	// Query matches username, email and full name
+	Query       string `validate:"max=100"`
+	PhoneNumber string `validate:"max=20"`
+	Role        string `validate:"omitempty,oneof=user customer admin owner"`
+	Status      string `validate:"max=20"`
+	Verified    *bool
+	Locked      *bool
+	// Sort defaults to relevance when searching and newest first otherwise
+	Sort string `validate:"omitempty,oneof=created_at username relevance"`
 }
 
 type ListUsersResult struct {
 	Users []database.User
-	Total int64
+	// Total is only counted when the page asked for it
+	Total *int64
+	// Next is the cursor of the last user, nil on the last page
+	Next *pagination.Cursor
 }
 
 func (s *UserService) ListUsers(ctx context.Context, params ListUsersParams) (ListUsersResult, *utils.AppError) {
-	arg := database.GetUsersParams{
-		Limit:             params.Limit,
-		Offset:            params.Offset,
-		SearchUsername:    sql.NullString{String: params.Username, Valid: params.Username != ""},
-		SearchEmail:       sql.NullString{String: params.Email, Valid: params.Email != ""},
-		SearchPhoneNumber: sql.NullString{String: params.PhoneNumber, Valid: params.PhoneNumber != ""},
+	params.Query = strings.TrimSpace(params.Query)
+	if err := validate.Struct(params); err != nil {
+		return ListUsersResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+	if params.Sort == "" {
+		params.Sort = UserSortNewest
+		if params.Query != "" {
+			params.Sort = UserSortRelevance
+		}
+	}
+	if params.Sort == UserSortRelevance && params.Query == "" {
+		return ListUsersResult{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Sorting by relevance requires a search query",
+		}
+	}
+
+	filter := database.CountUsersParams{
+		Q:                 sql.NullString{String: escapeLike(params.Query), Valid: params.Query != ""},
+		SearchPhoneNumber: sql.NullString{String: escapeLike(params.PhoneNumber), Valid: params.PhoneNumber != ""},
+		Role:              database.NullUserRole{UserRole: database.UserRole(params.Role), Valid: params.Role != ""},
+		Status:            sql.NullString{String: params.Status, Valid: params.Status != ""},
+		IsVerified:        utils.ToNullBool(params.Verified),
+		IsLocked:          utils.ToNullBool(params.Locked),
 	}
 
-	dbUsers, err := s.DB.GetUsers(ctx, arg)
+	var result ListUsersResult
+	var err error
+	switch params.Sort {
+	case UserSortUsername:
+		result.Users, result.Next, err = s.listUsersByUsername(ctx, filter, params.Page)
+	case UserSortRelevance:
+		result.Users, result.Next, err = s.searchUsersByRelevance(ctx, filter, params.Page)
+	default:
+		result.Users, result.Next, err = s.listUsersByNewest(ctx, filter, params.Page)
+	}
 	if err != nil {
 		return ListUsersResult{}, &utils.AppError{
 			Code:    http.StatusInternalServerError,
@@ -120,23 +252,107 @@ func (s *UserService) ListUsers(ctx context.Context, params ListUsersParams) (Li
 		}
 	}
 
-	total, err := s.DB.CountUsers(ctx, database.CountUsersParams{
-		SearchUsername:    arg.SearchUsername,
-		SearchEmail:       arg.SearchEmail,
-		SearchPhoneNumber: arg.SearchPhoneNumber,
+	if params.Page.IncludeTotal {
+		total, err := s.DB.CountUsers(ctx, filter)
+		if err != nil {
+			return ListUsersResult{}, &utils.AppError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to count users",
+				Err:     err,
+			}
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+func (s *UserService) listUsersByNewest(ctx context.Context, f database.CountUsersParams, page pagination.Params) ([]database.User, *pagination.Cursor, error) {
+	rows, err := s.DB.GetUsers(ctx, database.GetUsersParams{
+		Limit:             page.FetchLimit(),
+		Q:                 f.Q,
+		SearchPhoneNumber: f.SearchPhoneNumber,
+		Role:              f.Role,
+		Status:            f.Status,
+		IsVerified:        f.IsVerified,
+		IsLocked:          f.IsLocked,
+		CursorCreatedAt:   page.CursorCreatedAt(),
+		CursorID:          page.CursorID(),
 	})
 	if err != nil {
-		return ListUsersResult{}, &utils.AppError{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to count users",
-			Err:     err,
+		return nil, nil, err
+	}
+	users, more := pagination.Trim(rows, page)
+	if !more {
+		return users, nil, nil
+	}
+	last := users[len(users)-1]
+	return users, &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+func (s *UserService) listUsersByUsername(ctx context.Context, f database.CountUsersParams, page pagination.Params) ([]database.User, *pagination.Cursor, error) {
+	rows, err := s.DB.GetUsersByUsername(ctx, database.GetUsersByUsernameParams{
+		Limit:             page.FetchLimit(),
+		Q:                 f.Q,
+		SearchPhoneNumber: f.SearchPhoneNumber,
+		Role:              f.Role,
+		Status:            f.Status,
+		IsVerified:        f.IsVerified,
+		IsLocked:          f.IsLocked,
+		CursorKey:         page.CursorKey(),
+		CursorID:          page.CursorID(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	users, more := pagination.Trim(rows, page)
+	if !more {
+		return users, nil, nil
+	}
+	last := users[len(users)-1]
+	return users, &pagination.Cursor{Key: strings.ToLower(last.Username), ID: last.ID}, nil
+}
+
+func (s *UserService) searchUsersByRelevance(ctx context.Context, f database.CountUsersParams, page pagination.Params) ([]database.User, *pagination.Cursor, error) {
+	arg := database.SearchUsersByRelevanceParams{
+		Limit:             page.FetchLimit(),
+		Q:                 f.Q.String,
+		SearchPhoneNumber: f.SearchPhoneNumber,
+		Role:              f.Role,
+		Status:            f.Status,
+		IsVerified:        f.IsVerified,
+		IsLocked:          f.IsLocked,
+		CursorID:          page.CursorID(),
+	}
+	if page.After != nil {
+		rank, err := strconv.ParseFloat(page.After.Key, 32)
+		if err != nil {
+			return nil, nil, err
 		}
+		arg.CursorRank = sql.NullFloat64{Float64: rank, Valid: true}
 	}
 
-	return ListUsersResult{
-		Users: dbUsers,
-		Total: total,
-	}, nil
+	rows, err := s.DB.SearchUsersByRelevance(ctx, arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, more := pagination.Trim(rows, page)
+	users := make([]database.User, len(rows))
+	for i, row := range rows {
+		users[i] = row.User
+	}
+	if !more {
+		return users, nil, nil
+	}
+	last := rows[len(rows)-1]
+	// Shortest text that parses back to the same float32
+	key := strconv.FormatFloat(float64(last.Rank), 'g', -1, 32)
+	return users, &pagination.Cursor{Key: key, ID: last.User.ID}, nil
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (s *UserService) UpdateUserRole(
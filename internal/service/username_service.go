@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techies/streamify/internal/app"
+	"github.com/techies/streamify/internal/database"
+	"github.com/techies/streamify/internal/utils"
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,30}$`)
+
+// checkUsername validates the format of username, rejects reserved words and makes
+// sure nobody else holds it, either currently or through a recent rename.
+// userID is the account claiming the name, uuid.Nil during registration.
+func checkUsername(ctx context.Context, db *database.Queries, cfg *app.AppConfig, username string, userID uuid.UUID) *utils.AppError {
+	if !usernamePattern.MatchString(username) {
+		return &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Username must be 3-30 characters of letters, digits, '_', '.' or '-'",
+		}
+	}
+
+	for _, reserved := range cfg.ReservedUsernames {
+		if strings.EqualFold(username, reserved) {
+			return &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Username is reserved",
+			}
+		}
+	}
+
+	existing, err := db.GetUserByUsername(ctx, username)
+	if err == nil && existing.ID != userID {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Username already taken",
+		}
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	// Recently released names stay with their previous owner for a while
+	hold, err := db.GetActiveUsernameHold(ctx, username)
+	if err == nil && hold.UserID != userID {
+		return &utils.AppError{
+			Code:    http.StatusConflict,
+			Message: "Username already taken",
+		}
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+type ChangeUsernameParams struct {
+	UserID   uuid.UUID `validate:"required"`
+	Username string    `validate:"required"`
+}
+
+// ChangeUsername renames a user. The old name keeps resolving to the user and
+// can't be claimed by anyone else until UsernameHoldPeriod has passed.
+func (s *UserService) ChangeUsername(ctx context.Context, params ChangeUsernameParams) (database.User, *utils.AppError) {
+	if err := validate.Struct(params); err != nil {
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Err:     err,
+		}
+	}
+
+	user, appErr := s.GetUser(ctx, params.UserID)
+	if appErr != nil {
+		return database.User{}, appErr
+	}
+
+	if user.Username == params.Username {
+		return user, nil
+	}
+
+	if user.UsernameChangedAt.Valid {
+		if next := user.UsernameChangedAt.Time.Add(s.cfg.UsernameChangeCooldown); time.Now().Before(next) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusTooManyRequests,
+				Message: "Username can be changed again after " + next.Format(time.RFC3339),
+			}
+		}
+	}
+
+	if appErr := checkUsername(ctx, s.DB, s.cfg, params.Username, user.ID); appErr != nil {
+		return database.User{}, appErr
+	}
+
+	err := runInTx(ctx, s.cfg.Conn, s.DB, func(q *database.Queries) error {
+		if _, err := q.CreateUsernameHistory(ctx, database.CreateUsernameHistoryParams{
+			UserID:     user.ID,
+			Username:   user.Username,
+			ReleasedAt: time.Now().Add(s.cfg.UsernameHoldPeriod),
+		}); err != nil {
+			return err
+		}
+		return q.UpdateUsername(ctx, database.UpdateUsernameParams{
+			ID:       user.ID,
+			Username: params.Username,
+		})
+	})
+	if err != nil {
+		if utils.IsUniqueViolation(err) {
+			return database.User{}, &utils.AppError{
+				Code:    http.StatusConflict,
+				Message: "Username already taken",
+			}
+		}
+		return database.User{}, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to change username",
+			Err:     err,
+		}
+	}
+
+	return s.GetUser(ctx, user.ID)
+}
+
+// ResolveUsername finds the user currently holding username. When the name was
+// recently given up, the previous owner is returned with redirected set so
+// callers can point clients to the new name.
+func (s *UserService) ResolveUsername(ctx context.Context, username string) (user database.User, redirected bool, appErr *utils.AppError) {
+	user, err := s.DB.GetUserByUsername(ctx, username)
+	if err == nil {
+		return user, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, false, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	hold, err := s.DB.GetActiveUsernameHold(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.User{}, false, &utils.AppError{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+			}
+		}
+		return database.User{}, false, &utils.AppError{
+			Code:    http.StatusInternalServerError,
+			Message: "Database error",
+			Err:     err,
+		}
+	}
+
+	user, appErr = s.GetUser(ctx, hold.UserID)
+	if appErr != nil {
+		return database.User{}, false, appErr
+	}
+	return user, true, nil
+}
@@ -0,0 +1,61 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/techies/streamify/internal/logger"
+)
+
+// SMSSender delivers text messages to a phone number
+type SMSSender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// NewSender returns a FileSender when outboxPath is set, otherwise a LogSender.
+// Swap in a provider-backed implementation (Twilio, SNS, ...) here.
+func NewSender(outboxPath string) SMSSender {
+	if outboxPath != "" {
+		return NewFileSender(outboxPath)
+	}
+	return NewLogSender()
+}
+
+// LogSender writes messages to the application log instead of sending them
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(ctx context.Context, to, body string) error {
+	logger.Info(ctx, "SMS sent (log sender)", "to", to, "body", body)
+	return nil
+}
+
+// FileSender appends messages to a local outbox file, handy for tests and local QA
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (s *FileSender) Send(ctx context.Context, to, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().UTC().Format(time.RFC3339), to, body)
+	return err
+}
@@ -0,0 +1,81 @@
+-- name: GetAlbumByID :one
+SELECT * FROM albums WHERE id = $1;
+
+-- name: SetAlbumCover :one
+UPDATE albums
+SET cover_url = $2, cover_key = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateAlbum :one
+INSERT INTO albums (
+    artist_id, title, release_date, release_type, upc, label, copyright_line, phonographic_line
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetAlbumWithArtist :one
+SELECT sqlc.embed(al), ar.name AS artist_name
+FROM albums al
+JOIN artists ar ON ar.id = al.artist_id
+WHERE al.id = $1;
+
+-- name: ListArtistAlbums :many
+-- Newest first, optionally of one release type
+SELECT sqlc.embed(al),
+    (SELECT COUNT(*) FROM songs s WHERE s.album_id = al.id) AS track_count
+FROM albums al
+WHERE al.artist_id = sqlc.arg(artist_id)
+  AND (sqlc.narg('release_type')::album_release_type IS NULL OR al.release_type = sqlc.narg('release_type'))
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (al.created_at, al.id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY al.created_at DESC, al.id DESC
+LIMIT sqlc.arg(lim);
+
+-- name: PatchAlbum :one
+-- Merge patch: set_* picks the fields to write, a NULL value clears the field
+UPDATE albums
+SET
+  title = CASE WHEN sqlc.arg('set_title')::boolean THEN sqlc.arg('title') ELSE title END,
+  release_date = CASE WHEN sqlc.arg('set_release_date')::boolean THEN sqlc.narg('release_date') ELSE release_date END,
+  release_type = CASE WHEN sqlc.arg('set_release_type')::boolean THEN sqlc.arg('release_type') ELSE release_type END,
+  upc = CASE WHEN sqlc.arg('set_upc')::boolean THEN sqlc.narg('upc') ELSE upc END,
+  label = CASE WHEN sqlc.arg('set_label')::boolean THEN sqlc.narg('label') ELSE label END,
+  copyright_line = CASE WHEN sqlc.arg('set_copyright_line')::boolean THEN sqlc.narg('copyright_line') ELSE copyright_line END,
+  phonographic_line = CASE WHEN sqlc.arg('set_phonographic_line')::boolean THEN sqlc.narg('phonographic_line') ELSE phonographic_line END,
+  updated_at = NOW()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteAlbum :execrows
+DELETE FROM albums WHERE id = $1;
+
+-- name: ListAlbumTracks :many
+-- Tracklist order; songs without a track number come last and taken down songs are left out
+SELECT s.id, m.title, m.duration, m.explicit, s.disc_number, s.track_number
+FROM songs s
+JOIN media m ON m.id = s.id
+WHERE s.album_id = $1
+  AND m.taken_down_at IS NULL
+ORDER BY s.disc_number, s.track_number NULLS LAST, m.title, s.id;
+
+-- name: CountAlbumTracks :one
+SELECT COUNT(*) FROM songs WHERE album_id = $1;
+
+-- name: ListArtistSongIDs :many
+-- Which of the given songs are credited to the artist
+SELECT s.id
+FROM songs s
+JOIN media m ON m.id = s.id
+WHERE s.id = ANY(sqlc.arg(ids)::uuid[])
+  AND m.artist_id = sqlc.arg(artist_id);
+
+-- name: DetachAlbumTracks :exec
+UPDATE songs
+SET album_id = NULL, disc_number = 1, track_number = NULL
+WHERE album_id = $1;
+
+-- name: SetSongTrack :exec
+UPDATE songs
+SET album_id = $2, disc_number = $3, track_number = $4
+WHERE id = $1;
@@ -0,0 +1,109 @@
+-- name: GetArtistMember :one
+SELECT * FROM artist_members
+WHERE artist_id = $1 AND user_id = $2;
+
+-- name: ListArtistMembers :many
+SELECT sqlc.embed(m), u.username
+FROM artist_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.artist_id = $1
+ORDER BY m.role, m.created_at, m.user_id;
+
+-- name: ListUserArtistMemberships :many
+SELECT sqlc.embed(m), sqlc.embed(a)
+FROM artist_members m
+JOIN artists a ON a.id = m.artist_id
+WHERE m.user_id = $1
+ORDER BY LOWER(a.name), a.id;
+
+-- name: AddArtistMember :one
+INSERT INTO artist_members (artist_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (artist_id, user_id) DO UPDATE SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: UpdateArtistMemberRole :one
+UPDATE artist_members SET role = $3
+WHERE artist_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteArtistMember :execrows
+DELETE FROM artist_members
+WHERE artist_id = $1 AND user_id = $2;
+
+-- name: LockArtistOwners :many
+-- Serializes changes that could leave the artist without an owner
+SELECT user_id FROM artist_members
+WHERE artist_id = $1 AND role = 'owner'
+FOR UPDATE;
+
+-- name: ArtistHasOwner :one
+SELECT EXISTS (
+    SELECT 1 FROM artist_members WHERE artist_id = $1 AND role = 'owner'
+);
+
+-- name: CreateArtistMemberInvite :one
+-- Inviting the same user again replaces the earlier invite
+INSERT INTO artist_member_invites (artist_id, user_id, role, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (artist_id, user_id) DO UPDATE
+SET role = EXCLUDED.role,
+    invited_by = EXCLUDED.invited_by,
+    created_at = NOW(),
+    expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: GetArtistMemberInvite :one
+SELECT * FROM artist_member_invites WHERE id = $1;
+
+-- name: ListArtistMemberInvites :many
+SELECT sqlc.embed(i), u.username
+FROM artist_member_invites i
+JOIN users u ON u.id = i.user_id
+WHERE i.artist_id = $1 AND i.expires_at > NOW()
+ORDER BY i.created_at, i.id;
+
+-- name: ListUserArtistInvites :many
+SELECT sqlc.embed(i), sqlc.embed(a)
+FROM artist_member_invites i
+JOIN artists a ON a.id = i.artist_id
+WHERE i.user_id = $1 AND i.expires_at > NOW()
+ORDER BY i.created_at, i.id;
+
+-- name: DeleteArtistMemberInvite :execrows
+DELETE FROM artist_member_invites WHERE id = $1;
+
+-- name: DeleteExpiredArtistMemberInvites :exec
+DELETE FROM artist_member_invites WHERE expires_at <= NOW();
+
+-- name: CreateArtistClaim :one
+INSERT INTO artist_claims (artist_id, user_id, message)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetArtistClaim :one
+SELECT * FROM artist_claims WHERE id = $1;
+
+-- name: ListPendingArtistClaims :many
+-- Review queue, oldest first
+SELECT sqlc.embed(c), a.name AS artist_name, u.username
+FROM artist_claims c
+JOIN artists a ON a.id = c.artist_id
+JOIN users u ON u.id = c.user_id
+WHERE c.status = 'pending'
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (c.created_at, c.id) > (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg(lim);
+
+-- name: ReviewArtistClaim :one
+UPDATE artist_claims
+SET status = $2, reviewed_by = $3, review_note = $4, reviewed_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: RejectOtherArtistClaims :exec
+-- Once an artist has an owner the remaining claims on it are moot
+UPDATE artist_claims
+SET status = 'rejected', reviewed_by = $3, review_note = 'Another claim on this artist was approved', reviewed_at = NOW()
+WHERE artist_id = $1 AND id <> $2 AND status = 'pending';
@@ -0,0 +1,49 @@
+-- name: CreateVerificationRequest :one
+INSERT INTO artist_verification_requests (artist_id, submitted_by, evidence_links, note)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetVerificationRequest :one
+SELECT * FROM artist_verification_requests WHERE id = $1;
+
+-- name: GetVerificationRequestForUpdate :one
+SELECT * FROM artist_verification_requests WHERE id = $1
+FOR UPDATE;
+
+-- name: ListArtistVerificationRequests :many
+SELECT * FROM artist_verification_requests
+WHERE artist_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: ListVerificationQueue :many
+-- Least recently touched first, so answered requests queue up behind older ones
+SELECT sqlc.embed(r), a.name AS artist_name
+FROM artist_verification_requests r
+JOIN artists a ON a.id = r.artist_id
+WHERE r.status = sqlc.arg(status)
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (r.updated_at, r.id) > (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY r.updated_at, r.id
+LIMIT sqlc.arg(lim);
+
+-- name: UpdateVerificationRequestStatus :one
+-- NULL evidence_links and note keep the submitted ones
+UPDATE artist_verification_requests
+SET status = sqlc.arg(status),
+    evidence_links = COALESCE(sqlc.narg('evidence_links')::text[], evidence_links),
+    note = COALESCE(sqlc.narg('note'), note),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddVerificationEvent :one
+INSERT INTO artist_verification_events (request_id, actor_id, from_status, to_status, comment)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListVerificationEvents :many
+SELECT sqlc.embed(e), u.username AS actor_username
+FROM artist_verification_events e
+LEFT JOIN users u ON u.id = e.actor_id
+WHERE e.request_id = $1
+ORDER BY e.created_at, e.id;
@@ -0,0 +1,86 @@
+-- name: CreateArtist :one
+INSERT INTO artists (name, bio, avatar_url)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetArtistByID :one
+SELECT * FROM artists WHERE id = $1;
+
+-- Follower and play counts are computed the same way in every query below
+
+-- name: GetArtistWithStats :one
+SELECT sqlc.embed(a),
+    (SELECT COUNT(*) FROM follows f WHERE f.artist_id = a.id) AS follower_count,
+    (SELECT COALESCE(SUM(ms.play_count), 0) FROM media m JOIN media_stats ms ON ms.media_id = m.id WHERE m.artist_id = a.id)::bigint AS play_count,
+    EXISTS (SELECT 1 FROM follows f WHERE f.artist_id = a.id AND f.follower_id = sqlc.arg(viewer_id)) AS following
+FROM artists a
+WHERE a.id = sqlc.arg(id);
+
+-- name: ListArtistsByName :many
+-- Alphabetical, case-insensitive; q is matched through the pg_trgm index and LIKE wildcards in it are escaped by the caller
+SELECT sqlc.embed(a),
+    (SELECT COUNT(*) FROM follows f WHERE f.artist_id = a.id) AS follower_count,
+    (SELECT COALESCE(SUM(ms.play_count), 0) FROM media m JOIN media_stats ms ON ms.media_id = m.id WHERE m.artist_id = a.id)::bigint AS play_count
+FROM artists a
+WHERE (sqlc.narg('q')::text IS NULL OR a.name ILIKE '%' || sqlc.narg('q') || '%')
+  AND (sqlc.narg('verified')::boolean IS NULL OR a.verified = sqlc.narg('verified'))
+  AND (sqlc.narg('cursor_key')::text IS NULL
+    OR (LOWER(a.name), a.id) > (sqlc.narg('cursor_key')::text, sqlc.narg('cursor_id')::uuid))
+ORDER BY LOWER(a.name), a.id
+LIMIT sqlc.arg(lim);
+
+-- name: SearchArtistsByRelevance :many
+-- Best match first; the cursor key is the rank of the last row
+SELECT sqlc.embed(a),
+    (SELECT COUNT(*) FROM follows f WHERE f.artist_id = a.id) AS follower_count,
+    (SELECT COALESCE(SUM(ms.play_count), 0) FROM media m JOIN media_stats ms ON ms.media_id = m.id WHERE m.artist_id = a.id)::bigint AS play_count,
+    word_similarity(sqlc.arg('q')::text, a.name)::real AS rank
+FROM artists a
+WHERE a.name ILIKE '%' || sqlc.arg('q') || '%'
+  AND (sqlc.narg('verified')::boolean IS NULL OR a.verified = sqlc.narg('verified'))
+  AND (sqlc.narg('cursor_rank')::real IS NULL
+    OR (word_similarity(sqlc.arg('q')::text, a.name)::real, a.id) < (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_id')::uuid))
+ORDER BY rank DESC, a.id DESC
+LIMIT sqlc.arg(lim);
+
+-- name: PatchArtist :one
+-- Merge patch: set_* picks the fields to write, a NULL value clears the field
+UPDATE artists
+SET
+  name = CASE WHEN sqlc.arg('set_name')::boolean THEN sqlc.arg('name') ELSE name END,
+  bio = CASE WHEN sqlc.arg('set_bio')::boolean THEN sqlc.narg('bio') ELSE bio END,
+  avatar_url = CASE WHEN sqlc.arg('set_avatar_url')::boolean THEN sqlc.narg('avatar_url') ELSE avatar_url END,
+  -- An avatar URL set by hand no longer points at uploaded renditions
+  avatar_key = CASE WHEN sqlc.arg('set_avatar_url')::boolean THEN NULL ELSE avatar_key END,
+  updated_at = NOW()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: SetArtistAvatar :one
+UPDATE artists
+SET avatar_url = $2, avatar_key = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetArtistVerified :one
+UPDATE artists
+SET verified = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CountArtistCatalog :one
+-- What would go with the artist on delete: albums cascade and media lose their artist
+SELECT
+    (SELECT COUNT(*) FROM albums al WHERE al.artist_id = $1) AS albums,
+    (SELECT COUNT(*) FROM media m WHERE m.artist_id = $1) AS media;
+
+-- name: DeleteArtist :execrows
+DELETE FROM artists WHERE id = $1;
+
+-- name: FollowArtist :exec
+INSERT INTO follows (follower_id, artist_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnfollowArtist :execrows
+DELETE FROM follows WHERE follower_id = $1 AND artist_id = $2;
@@ -0,0 +1,79 @@
+-- name: CreateBulkUserJob :one
+INSERT INTO bulk_user_jobs (action, role, dry_run, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: AddBulkUserJobTargets :exec
+-- Positions follow the order of user_ids
+INSERT INTO bulk_user_job_items (job_id, position, user_id)
+SELECT sqlc.arg(job_id), t.ord, t.id
+FROM unnest(sqlc.arg(user_ids)::uuid[]) WITH ORDINALITY AS t(id, ord);
+
+-- name: AddBulkUserJobImportRow :exec
+INSERT INTO bulk_user_job_items (job_id, position, email, role, note)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetBulkUserJob :one
+SELECT sqlc.embed(j),
+    COUNT(i.position) AS total,
+    COUNT(i.position) FILTER (WHERE i.status = 'done') AS done,
+    COUNT(i.position) FILTER (WHERE i.status = 'skipped') AS skipped,
+    COUNT(i.position) FILTER (WHERE i.status = 'failed') AS failed
+FROM bulk_user_jobs j
+LEFT JOIN bulk_user_job_items i ON i.job_id = j.id
+WHERE j.id = $1
+GROUP BY j.id;
+
+-- name: ListBulkUserJobs :many
+SELECT sqlc.embed(j),
+    COUNT(i.position) AS total,
+    COUNT(i.position) FILTER (WHERE i.status = 'done') AS done,
+    COUNT(i.position) FILTER (WHERE i.status = 'skipped') AS skipped,
+    COUNT(i.position) FILTER (WHERE i.status = 'failed') AS failed
+FROM bulk_user_jobs j
+LEFT JOIN bulk_user_job_items i ON i.job_id = j.id
+GROUP BY j.id
+ORDER BY j.created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: ClaimBulkUserJob :one
+-- Takes the oldest job that is waiting, or whose runner stopped sending heartbeats
+UPDATE bulk_user_jobs
+SET status = 'running',
+    started_at = COALESCE(started_at, now()),
+    heartbeat_at = now()
+WHERE id = (
+    SELECT id FROM bulk_user_jobs
+    WHERE status = 'pending'
+       OR (status = 'running' AND heartbeat_at < sqlc.arg(stale_before)::timestamptz)
+    ORDER BY created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: ListPendingBulkUserJobItems :many
+SELECT * FROM bulk_user_job_items
+WHERE job_id = $1 AND status = 'pending'
+ORDER BY position
+LIMIT $2;
+
+-- name: FinishBulkUserJobItem :exec
+UPDATE bulk_user_job_items
+SET status = $3, message = $4, processed_at = now()
+WHERE job_id = $1 AND position = $2;
+
+-- name: TouchBulkUserJob :exec
+UPDATE bulk_user_jobs SET heartbeat_at = now() WHERE id = $1;
+
+-- name: CompleteBulkUserJob :exec
+UPDATE bulk_user_jobs
+SET status = 'completed', finished_at = now()
+WHERE id = $1;
+
+-- name: ListBulkUserJobItems :many
+SELECT * FROM bulk_user_job_items
+WHERE job_id = sqlc.arg(job_id)
+  AND (sqlc.narg('status')::bulk_item_status IS NULL OR status = sqlc.narg('status'))
+ORDER BY position
+LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
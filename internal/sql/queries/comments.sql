@@ -0,0 +1,14 @@
+-- name: ListMediaComments :many
+-- Oldest first; comments between users where either blocked the other are left out
+SELECT c.*
+FROM comments c
+WHERE c.media_id = sqlc.arg(media_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM user_blocks b
+    WHERE (b.blocker_id = sqlc.arg(viewer_id) AND b.blocked_id = c.user_id)
+       OR (b.blocker_id = c.user_id AND b.blocked_id = sqlc.arg(viewer_id))
+  )
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (c.created_at, c.id) > (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg(lim);
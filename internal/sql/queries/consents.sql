@@ -0,0 +1,68 @@
+-- name: CreateLegalDocument :one
+INSERT INTO legal_documents (
+    kind, version, title, url, mandatory, published_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetLegalDocumentByID :one
+SELECT * FROM legal_documents WHERE id = $1 LIMIT 1;
+
+-- name: ListCurrentLegalDocuments :many
+-- The latest published version of each kind
+SELECT DISTINCT ON (kind) * FROM legal_documents
+WHERE published_at <= NOW()
+ORDER BY kind, published_at DESC;
+
+-- name: ListLegalDocuments :many
+SELECT * FROM legal_documents
+ORDER BY kind, published_at DESC;
+
+-- name: ListRequiredLegalDocuments :many
+-- The latest published mandatory version of each kind; these must be accepted
+SELECT DISTINCT ON (kind) * FROM legal_documents
+WHERE mandatory AND published_at <= NOW()
+ORDER BY kind, published_at DESC;
+
+-- name: ListPendingLegalDocuments :many
+-- Required documents the user hasn't accepted, or has withdrawn consent from
+SELECT d.* FROM (
+    SELECT DISTINCT ON (kind) * FROM legal_documents
+    WHERE mandatory AND published_at <= NOW()
+    ORDER BY kind, published_at DESC
+) d
+WHERE NOT EXISTS (
+    SELECT 1 FROM user_consents c
+    WHERE c.user_id = $1
+      AND c.document_id = d.id
+      AND c.withdrawn_at IS NULL
+);
+
+-- name: CreateUserConsent :one
+INSERT INTO user_consents (
+    user_id, kind, document_id, ip_address, user_agent
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetActiveMarketingConsent :one
+SELECT * FROM user_consents
+WHERE user_id = $1
+  AND kind = 'marketing'
+  AND withdrawn_at IS NULL
+LIMIT 1;
+
+-- name: ListUserConsents :many
+SELECT c.*, d.version AS document_version
+FROM user_consents c
+LEFT JOIN legal_documents d ON d.id = c.document_id
+WHERE c.user_id = $1
+ORDER BY c.accepted_at DESC;
+
+-- name: WithdrawUserConsent :execrows
+UPDATE user_consents
+SET withdrawn_at = NOW(),
+    withdrawn_ip = $3
+WHERE id = $1
+  AND user_id = $2
+  AND withdrawn_at IS NULL;
@@ -0,0 +1,61 @@
+-- name: CreateEmailChangeRequest :one
+INSERT INTO email_change_requests (
+    user_id, old_email, new_email, confirm_token, revoke_token, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetLatestEmailChangeByUser :one
+SELECT * FROM email_change_requests
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetEmailChangeByConfirmToken :one
+SELECT * FROM email_change_requests WHERE confirm_token = $1 LIMIT 1;
+
+-- name: GetEmailChangeByRevokeToken :one
+SELECT * FROM email_change_requests WHERE revoke_token = $1 LIMIT 1;
+
+-- name: CancelPendingEmailChanges :exec
+UPDATE email_change_requests
+SET status = 'cancelled',
+    completed_at = NOW()
+WHERE user_id = $1
+  AND status = 'pending';
+
+-- name: MarkEmailChangeConfirmed :exec
+UPDATE email_change_requests
+SET status = 'confirmed',
+    completed_at = NOW()
+WHERE id = $1
+  AND status = 'pending';
+
+-- name: MarkEmailChangeRevoked :exec
+UPDATE email_change_requests
+SET status = 'revoked',
+    completed_at = NOW()
+WHERE id = $1;
+
+-- name: ExpireEmailChangeRequests :exec
+UPDATE email_change_requests
+SET status = 'expired'
+WHERE status = 'pending'
+  AND expires_at <= NOW();
+
+-- name: HasNewerConfirmedEmailChange :one
+SELECT EXISTS (
+    SELECT 1 FROM email_change_requests
+    WHERE user_id = $1
+      AND created_at > $2
+      AND status = 'confirmed'
+);
+
+-- name: RestoreUserEmail :execrows
+-- Only undoes the change while the account still has the address it changed to
+UPDATE users
+SET
+    email = sqlc.arg(old_email),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND email = sqlc.arg(new_email);
@@ -0,0 +1,56 @@
+-- name: CreateInvitation :one
+INSERT INTO invitations (
+    code, created_by, email, note, max_uses, expires_at, role
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetInvitationByID :one
+SELECT * FROM invitations WHERE id = $1 LIMIT 1;
+
+-- name: GetInvitationByCode :one
+SELECT * FROM invitations WHERE code = $1 LIMIT 1;
+
+-- name: GetUsableInvitationByEmail :one
+-- An invitation bound to the email that can still be redeemed
+SELECT * FROM invitations
+WHERE email = $1
+  AND revoked_at IS NULL
+  AND use_count < max_uses
+  AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListInvitations :many
+SELECT * FROM invitations
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: RevokeInvitation :exec
+UPDATE invitations
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL;
+
+-- name: ConsumeInvitation :execrows
+-- Atomically takes one use of an invitation; affects no rows when it can't be used
+UPDATE invitations
+SET use_count = use_count + 1
+WHERE id = $1
+  AND revoked_at IS NULL
+  AND use_count < max_uses
+  AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: CreateInvitationRedemption :exec
+INSERT INTO invitation_redemptions (
+    invitation_id, user_id
+) VALUES (
+    $1, $2
+);
+
+-- name: ListInvitationRedemptions :many
+SELECT r.invitation_id, r.redeemed_at, u.id AS user_id, u.username, u.email
+FROM invitation_redemptions r
+JOIN users u ON u.id = r.user_id
+WHERE r.invitation_id = $1
+ORDER BY r.redeemed_at DESC;
@@ -0,0 +1,37 @@
+-- name: CreateListenerProfile :one
+INSERT INTO listener_profiles (
+    user_id, name, avatar_url, is_kid, pin_hash, is_default
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListListenerProfiles :many
+SELECT * FROM listener_profiles
+WHERE user_id = $1
+ORDER BY is_default DESC, created_at;
+
+-- name: GetListenerProfile :one
+SELECT * FROM listener_profiles
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: GetDefaultListenerProfile :one
+SELECT * FROM listener_profiles
+WHERE user_id = $1 AND is_default LIMIT 1;
+
+-- name: CountListenerProfiles :one
+SELECT COUNT(*) FROM listener_profiles WHERE user_id = $1;
+
+-- name: UpdateListenerProfile :one
+UPDATE listener_profiles
+SET name = $3,
+    avatar_url = $4,
+    is_kid = $5,
+    pin_hash = $6,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteListenerProfile :execrows
+-- The default profile holds account-level activity and cannot be removed
+DELETE FROM listener_profiles
+WHERE id = $1 AND user_id = $2 AND NOT is_default;
@@ -0,0 +1,77 @@
+-- name: CreateMedia :one
+INSERT INTO media (type, title, duration, artist_id, explicit)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateSong :one
+INSERT INTO songs (id, album_id, audio_url, audio_key, bitrate, disc_number, track_number)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: CreateVideo :one
+INSERT INTO videos (id, video_url, video_key, resolution)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- GetMedia and ListMedia select the same columns: the media row and the
+-- fields of its songs or videos row, NULL for the other subtype
+
+-- name: GetMedia :one
+SELECT sqlc.embed(m),
+    s.album_id, s.audio_url, s.audio_key, s.bitrate, s.disc_number, s.track_number,
+    v.video_url, v.video_key, v.resolution
+FROM media m
+LEFT JOIN songs s ON s.id = m.id
+LEFT JOIN videos v ON v.id = m.id
+WHERE m.id = $1;
+
+-- name: ListMedia :many
+-- Newest first; explicit and taken down media are left out unless asked for
+SELECT sqlc.embed(m),
+    s.album_id, s.audio_url, s.audio_key, s.bitrate, s.disc_number, s.track_number,
+    v.video_url, v.video_key, v.resolution
+FROM media m
+LEFT JOIN songs s ON s.id = m.id
+LEFT JOIN videos v ON v.id = m.id
+WHERE (sqlc.narg('type')::text IS NULL OR m.type = sqlc.narg('type'))
+  AND (sqlc.narg('artist_id')::uuid IS NULL OR m.artist_id = sqlc.narg('artist_id'))
+  AND (sqlc.narg('album_id')::uuid IS NULL OR s.album_id = sqlc.narg('album_id'))
+  AND (sqlc.arg('include_explicit')::boolean OR NOT m.explicit)
+  AND (sqlc.arg('include_taken_down')::boolean OR m.taken_down_at IS NULL)
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (m.created_at, m.id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT sqlc.arg(lim);
+
+-- name: PatchMedia :one
+-- Merge patch: set_* picks the fields to write, a NULL value clears the field
+UPDATE media
+SET
+  title = CASE WHEN sqlc.arg('set_title')::boolean THEN sqlc.arg('title') ELSE title END,
+  duration = CASE WHEN sqlc.arg('set_duration')::boolean THEN sqlc.narg('duration') ELSE duration END,
+  explicit = CASE WHEN sqlc.arg('set_explicit')::boolean THEN sqlc.arg('explicit') ELSE explicit END,
+  updated_at = NOW()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: PatchSong :exec
+UPDATE songs
+SET bitrate = CASE WHEN sqlc.arg('set_bitrate')::boolean THEN sqlc.narg('bitrate') ELSE bitrate END
+WHERE id = sqlc.arg('id');
+
+-- name: PatchVideo :exec
+UPDATE videos
+SET resolution = CASE WHEN sqlc.arg('set_resolution')::boolean THEN sqlc.narg('resolution') ELSE resolution END
+WHERE id = sqlc.arg('id');
+
+-- name: TakeDownMedia :one
+UPDATE media
+SET taken_down_at = NOW(), takedown_reason = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: RestoreMedia :one
+UPDATE media
+SET taken_down_at = NULL, takedown_reason = NULL, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
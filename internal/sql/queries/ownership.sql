@@ -0,0 +1,37 @@
+-- name: GetOwner :one
+SELECT * FROM users
+WHERE role = 'owner'
+LIMIT 1;
+
+-- name: SetUserRole :exec
+-- Unlike UpdateUserRole this can grant and revoke owner; only ownership flows use it
+UPDATE users
+SET role = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreateOwnershipTransfer :one
+INSERT INTO ownership_transfers (
+    from_user_id, to_user_id, expires_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetPendingOwnershipTransfer :one
+SELECT * FROM ownership_transfers
+WHERE status = 'pending'
+LIMIT 1;
+
+-- name: CancelPendingOwnershipTransfers :exec
+UPDATE ownership_transfers
+SET status = 'cancelled',
+    completed_at = NOW()
+WHERE status = 'pending';
+
+-- name: CompleteOwnershipTransfer :execrows
+UPDATE ownership_transfers
+SET status = 'accepted',
+    completed_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+  AND expires_at > NOW();
@@ -0,0 +1,34 @@
+-- name: CreatePhoneVerification :one
+INSERT INTO phone_verifications (
+    user_id, phone_number, code_hash, expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetLatestPhoneVerification :one
+SELECT * FROM phone_verifications
+WHERE user_id = $1
+  AND verified_at IS NULL
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: IncrementPhoneVerificationAttempts :one
+UPDATE phone_verifications
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING attempts;
+
+-- name: MarkPhoneVerificationUsed :exec
+UPDATE phone_verifications
+SET verified_at = NOW()
+WHERE id = $1;
+
+-- name: DeletePendingPhoneVerifications :exec
+DELETE FROM phone_verifications
+WHERE user_id = $1
+  AND verified_at IS NULL;
+
+-- name: DeleteExpiredPhoneVerifications :exec
+DELETE FROM phone_verifications
+WHERE verified_at IS NULL
+  AND expires_at <= NOW() - INTERVAL '1 day';
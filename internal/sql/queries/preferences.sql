@@ -0,0 +1,15 @@
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences WHERE user_id = $1 LIMIT 1;
+
+-- name: SaveUserPreferences :one
+-- Only writes when the stored revision is still expected_revision (0 when nothing is stored yet);
+-- no row is returned otherwise
+INSERT INTO user_preferences (user_id, version, document, revision)
+VALUES (sqlc.arg(user_id), sqlc.arg(version), sqlc.arg(document), 1)
+ON CONFLICT (user_id) DO UPDATE
+SET version = EXCLUDED.version,
+    document = EXCLUDED.document,
+    revision = user_preferences.revision + 1,
+    updated_at = now()
+WHERE user_preferences.revision = sqlc.arg(expected_revision)::bigint
+RETURNING *;
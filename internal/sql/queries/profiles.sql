@@ -0,0 +1,34 @@
+-- name: GetUserProfile :one
+SELECT * FROM user_profiles WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertUserProfile :exec
+-- Creates the profile row on first write; NULL arguments keep the current value
+INSERT INTO user_profiles (
+    user_id, country, birth_date, filter_explicit
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.narg(country),
+    sqlc.narg(birth_date),
+    COALESCE(sqlc.narg(filter_explicit)::boolean, FALSE)
+)
+ON CONFLICT (user_id) DO UPDATE
+SET country = COALESCE(sqlc.narg(country), user_profiles.country),
+    birth_date = COALESCE(sqlc.narg(birth_date), user_profiles.birth_date),
+    filter_explicit = COALESCE(sqlc.narg(filter_explicit)::boolean, user_profiles.filter_explicit),
+    updated_at = NOW();
+
+-- name: PatchUserProfileSettings :exec
+-- Merge patch of the age gating fields; set_* picks the fields to write, a NULL country clears it
+INSERT INTO user_profiles (
+    user_id, country, birth_date, filter_explicit
+) VALUES (
+    sqlc.arg(user_id),
+    sqlc.narg(country),
+    sqlc.narg(birth_date),
+    COALESCE(sqlc.narg(filter_explicit)::boolean, FALSE)
+)
+ON CONFLICT (user_id) DO UPDATE
+SET country = CASE WHEN sqlc.arg(set_country)::boolean THEN EXCLUDED.country ELSE user_profiles.country END,
+    birth_date = CASE WHEN sqlc.arg(set_birth_date)::boolean THEN EXCLUDED.birth_date ELSE user_profiles.birth_date END,
+    filter_explicit = CASE WHEN sqlc.arg(set_filter_explicit)::boolean THEN EXCLUDED.filter_explicit ELSE user_profiles.filter_explicit END,
+    updated_at = NOW();
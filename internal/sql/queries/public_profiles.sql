@@ -0,0 +1,74 @@
+-- name: GetPrivacySettings :one
+SELECT * FROM user_privacy_settings WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertPrivacySettings :one
+INSERT INTO user_privacy_settings (
+    user_id, profile, display_name, avatar, bio, country, playlists, followed_artists, listening_stats,
+    require_follow_approval
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (user_id) DO UPDATE
+SET profile = EXCLUDED.profile,
+    display_name = EXCLUDED.display_name,
+    avatar = EXCLUDED.avatar,
+    bio = EXCLUDED.bio,
+    country = EXCLUDED.country,
+    playlists = EXCLUDED.playlists,
+    followed_artists = EXCLUDED.followed_artists,
+    listening_stats = EXCLUDED.listening_stats,
+    require_follow_approval = EXCLUDED.require_follow_approval,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetPublicProfileByUsername :one
+-- Deleted and locked accounts have no public profile
+SELECT
+    u.id, u.username, u.first_name, u.last_name, u.bio, u.avatar_url, u.created_at,
+    p.country
+FROM users u
+LEFT JOIN user_profiles p ON p.user_id = u.id
+WHERE LOWER(u.username) = LOWER(sqlc.arg(username))
+  AND u.status != 'deleted'
+  AND NOT u.is_locked
+LIMIT 1;
+
+-- name: ListPublicPlaylists :many
+-- Only playlists of the account holder's own listener profile are shown
+SELECT pl.id, pl.name, pl.created_at, COUNT(pi.media_id) AS item_count
+FROM playlists pl
+JOIN listener_profiles lp ON lp.id = pl.profile_id AND lp.is_default
+LEFT JOIN playlist_items pi ON pi.playlist_id = pl.id
+WHERE pl.user_id = $1 AND pl.is_public
+GROUP BY pl.id
+ORDER BY pl.created_at DESC
+LIMIT $2;
+
+-- name: ListFollowedArtists :many
+SELECT a.id, a.name, a.avatar_url, a.verified
+FROM follows f
+JOIN artists a ON a.id = f.artist_id
+WHERE f.follower_id = $1
+ORDER BY f.created_at DESC
+LIMIT $2;
+
+-- name: GetListeningStats :one
+SELECT
+    COUNT(*) AS stream_count,
+    COUNT(DISTINCT s.media_id) AS distinct_media,
+    COALESCE(SUM(EXTRACT(EPOCH FROM (s.ended_at - s.started_at))), 0)::bigint AS listening_seconds
+FROM streams s
+JOIN listener_profiles lp ON lp.id = s.profile_id AND lp.is_default
+WHERE s.user_id = $1;
+
+-- name: ListTopArtists :many
+SELECT a.id, a.name, COUNT(*) AS stream_count
+FROM streams s
+JOIN listener_profiles lp ON lp.id = s.profile_id AND lp.is_default
+JOIN media m ON m.id = s.media_id
+JOIN artists a ON a.id = m.artist_id
+WHERE s.user_id = $1
+  AND s.started_at > NOW() - INTERVAL '90 days'
+GROUP BY a.id
+ORDER BY stream_count DESC, a.name
+LIMIT $2;
@@ -1,8 +1,8 @@
 -- name: CreateSession :one
 INSERT INTO user_sessions (
-    user_id, refresh_token, ip_address, user_agent, expires_at
+    user_id, refresh_token, ip_address, user_agent, expires_at, profile_id, artist_id
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: GetSessionByToken :one
@@ -21,4 +21,19 @@ SELECT * FROM user_sessions WHERE id = $1 LIMIT 1;
 -- name: DeleteSessionByID :exec
 DELETE FROM user_sessions WHERE id = $1;
 
+-- name: DeleteSessionsByRole :exec
+DELETE FROM user_sessions
+WHERE user_id IN (SELECT id FROM users WHERE role = $1);
 
+-- name: SetSessionProfile :one
+UPDATE user_sessions SET profile_id = $2 WHERE id = $1
+RETURNING *;
+
+-- name: SetSessionArtist :one
+UPDATE user_sessions SET artist_id = $2 WHERE id = $1
+RETURNING *;
+
+-- name: ClearArtistFromUserSessions :exec
+-- Drops the artist context of a removed member so refreshed tokens lose it
+UPDATE user_sessions SET artist_id = NULL
+WHERE user_id = $1 AND artist_id = $2;
@@ -0,0 +1,10 @@
+-- name: HasActivePaidSubscription :one
+SELECT EXISTS (
+    SELECT 1
+    FROM subscriptions s
+    JOIN plans p ON p.id = s.plan_id
+    WHERE s.user_id = $1
+      AND s.status = 'active'
+      AND p.price_cents > 0
+      AND (s.ends_at IS NULL OR s.ends_at > now())
+) AS has_paid_subscription;
@@ -0,0 +1,46 @@
+-- name: CreateSuspension :one
+INSERT INTO user_suspensions (
+    user_id, reason_code, note, message, suspended_by, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetActiveSuspension :one
+SELECT * FROM user_suspensions
+WHERE user_id = $1
+  AND lifted_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY expires_at DESC NULLS FIRST
+LIMIT 1;
+
+-- name: ListUserSuspensions :many
+SELECT * FROM user_suspensions
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountUnliftedSuspensions :one
+SELECT COUNT(*) FROM user_suspensions
+WHERE user_id = $1 AND lifted_at IS NULL;
+
+-- name: LiftSuspensions :exec
+UPDATE user_suspensions
+SET lifted_at = NOW(),
+    lifted_by = $2
+WHERE user_id = $1
+  AND lifted_at IS NULL;
+
+-- name: UnlockUsersWithExpiredSuspensions :execrows
+-- Unlocks accounts whose suspensions have all run out
+UPDATE users u
+SET is_locked = FALSE,
+    updated_at = NOW()
+WHERE u.is_locked
+  AND EXISTS (
+    SELECT 1 FROM user_suspensions s
+    WHERE s.user_id = u.id AND s.lifted_at IS NULL AND s.expires_at <= NOW()
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM user_suspensions s
+    WHERE s.user_id = u.id AND s.lifted_at IS NULL AND (s.expires_at IS NULL OR s.expires_at > NOW())
+  );
@@ -0,0 +1,89 @@
+-- name: GetUserFollow :one
+SELECT * FROM user_follows WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: CreateUserFollow :one
+-- Following again keeps the existing follow and its status
+INSERT INTO user_follows (follower_id, followee_id, status, accepted_at)
+VALUES (
+    sqlc.arg(follower_id), sqlc.arg(followee_id), sqlc.arg(status)::follow_status,
+    CASE WHEN sqlc.arg(status)::follow_status = 'accepted' THEN NOW() END
+)
+ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = user_follows.status
+RETURNING *;
+
+-- name: DeleteUserFollow :execrows
+DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: AcceptFollowRequest :execrows
+UPDATE user_follows
+SET status = 'accepted', accepted_at = NOW()
+WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending';
+
+-- name: DeleteFollowRequest :execrows
+DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending';
+
+-- name: AcceptAllFollowRequests :exec
+UPDATE user_follows
+SET status = 'accepted', accepted_at = NOW()
+WHERE followee_id = $1 AND status = 'pending';
+
+-- name: ListFollowers :many
+-- Newest first; status 'pending' lists follow requests
+SELECT u.id, u.username, f.created_at AS followed_at
+FROM user_follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.followee_id = sqlc.arg(user_id)
+  AND f.status = sqlc.arg(status)::follow_status
+  AND u.status != 'deleted'
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (f.created_at, f.follower_id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY f.created_at DESC, f.follower_id DESC
+LIMIT sqlc.arg(lim);
+
+-- name: ListFollowing :many
+SELECT u.id, u.username, f.created_at AS followed_at
+FROM user_follows f
+JOIN users u ON u.id = f.followee_id
+WHERE f.follower_id = sqlc.arg(user_id)
+  AND f.status = 'accepted'
+  AND u.status != 'deleted'
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (f.created_at, f.followee_id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY f.created_at DESC, f.followee_id DESC
+LIMIT sqlc.arg(lim);
+
+-- name: GetFollowCounts :one
+SELECT
+    (SELECT COUNT(*) FROM user_follows f JOIN users u ON u.id = f.follower_id
+     WHERE f.followee_id = $1 AND f.status = 'accepted' AND u.status != 'deleted') AS followers,
+    (SELECT COUNT(*) FROM user_follows f JOIN users u ON u.id = f.followee_id
+     WHERE f.follower_id = $1 AND f.status = 'accepted' AND u.status != 'deleted') AS following;
+
+-- name: DeleteFollowsBetween :exec
+DELETE FROM user_follows
+WHERE (follower_id = sqlc.arg(user_a) AND followee_id = sqlc.arg(user_b))
+   OR (follower_id = sqlc.arg(user_b) AND followee_id = sqlc.arg(user_a));
+
+-- name: CreateUserBlock :exec
+INSERT INTO user_blocks (blocker_id, blocked_id)
+VALUES ($1, $2)
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+
+-- name: DeleteUserBlock :execrows
+DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: GetBlockBetween :one
+-- Which of the two users blocked the other, if any
+SELECT
+    EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker_id = sqlc.arg(user_a) AND b.blocked_id = sqlc.arg(user_b)) AS a_blocked_b,
+    EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker_id = sqlc.arg(user_b) AND b.blocked_id = sqlc.arg(user_a)) AS b_blocked_a;
+
+-- name: ListUserBlocks :many
+SELECT u.id, u.username, b.created_at AS blocked_at
+FROM user_blocks b
+JOIN users u ON u.id = b.blocked_id
+WHERE b.blocker_id = sqlc.arg(user_id)
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (b.created_at, b.blocked_id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY b.created_at DESC, b.blocked_id DESC
+LIMIT sqlc.arg(lim);
@@ -0,0 +1,14 @@
+-- name: CreateUsernameHistory :one
+INSERT INTO username_history (
+    user_id, username, released_at
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetActiveUsernameHold :one
+-- Returns the most recent owner of a username that has not been released yet
+SELECT * FROM username_history
+WHERE LOWER(username) = LOWER(sqlc.arg(username))
+  AND released_at > NOW()
+ORDER BY changed_at DESC
+LIMIT 1;
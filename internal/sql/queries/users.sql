@@ -9,6 +9,26 @@ SET
   updated_at = NOW()
 WHERE id = $1;
 
+-- name: PatchUserProfile :exec
+-- Merge patch: set_* picks the fields to write, a NULL value clears the field
+UPDATE users
+SET
+  first_name = CASE WHEN sqlc.arg('set_first_name')::boolean THEN sqlc.narg('first_name') ELSE first_name END,
+  last_name = CASE WHEN sqlc.arg('set_last_name')::boolean THEN sqlc.narg('last_name') ELSE last_name END,
+  bio = CASE WHEN sqlc.arg('set_bio')::boolean THEN sqlc.narg('bio') ELSE bio END,
+  avatar_url = CASE WHEN sqlc.arg('set_avatar_url')::boolean THEN sqlc.narg('avatar_url') ELSE avatar_url END,
+  -- An avatar URL set by hand no longer points at uploaded renditions
+  avatar_key = CASE WHEN sqlc.arg('set_avatar_url')::boolean THEN NULL ELSE avatar_key END,
+  phone_number = CASE WHEN sqlc.arg('set_phone_number')::boolean THEN sqlc.narg('phone_number') ELSE phone_number END,
+  updated_at = NOW()
+WHERE id = sqlc.arg('id');
+
+-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_url = $2, avatar_key = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: CreateUser :one
 INSERT INTO users (
   username, email, password_hash, verification_token, verification_expires_at, first_name, last_name, bio, phone_number, avatar_url
@@ -68,32 +88,92 @@ UPDATE users SET is_locked = TRUE WHERE id = $1;
 UPDATE users SET is_locked = FALSE WHERE id = $1;
 
 -- name: GetUserByUsername :one
-SELECT * FROM users WHERE username = $1 LIMIT 1;
+SELECT * FROM users WHERE LOWER(username) = LOWER(sqlc.arg(username)) LIMIT 1;
+
+-- The user search filters below are repeated in every list query and CountUsers.
+-- q is matched through the pg_trgm indexes; LIKE wildcards in it are escaped by the caller.
 
 -- name: GetUsers :many
+-- Newest first
+SELECT * FROM users
+WHERE
+  (sqlc.narg('q')::text IS NULL
+    OR username ILIKE '%' || sqlc.narg('q') || '%'
+    OR email ILIKE '%' || sqlc.narg('q') || '%'
+    OR (COALESCE(first_name, '') || ' ' || COALESCE(last_name, '')) ILIKE '%' || sqlc.narg('q') || '%')
+  AND (sqlc.narg('search_phone_number')::text IS NULL OR phone_number ILIKE '%' || sqlc.narg('search_phone_number') || '%')
+  AND (sqlc.narg('role')::user_role IS NULL OR role = sqlc.narg('role'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('is_verified')::boolean IS NULL OR is_verified = sqlc.narg('is_verified'))
+  AND (sqlc.narg('is_locked')::boolean IS NULL OR is_locked = sqlc.narg('is_locked'))
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $1;
+
+-- name: GetUsersByUsername :many
+-- Alphabetical, case-insensitive
 SELECT * FROM users
 WHERE
-  (sqlc.narg('search_username')::text IS NULL OR username ILIKE '%' || sqlc.narg('search_username') || '%')
-  AND (sqlc.narg('search_email')::text IS NULL OR email ILIKE '%' || sqlc.narg('search_email') || '%')
+  (sqlc.narg('q')::text IS NULL
+    OR username ILIKE '%' || sqlc.narg('q') || '%'
+    OR email ILIKE '%' || sqlc.narg('q') || '%'
+    OR (COALESCE(first_name, '') || ' ' || COALESCE(last_name, '')) ILIKE '%' || sqlc.narg('q') || '%')
   AND (sqlc.narg('search_phone_number')::text IS NULL OR phone_number ILIKE '%' || sqlc.narg('search_phone_number') || '%')
-ORDER BY id
-LIMIT $1 OFFSET $2;
+  AND (sqlc.narg('role')::user_role IS NULL OR role = sqlc.narg('role'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('is_verified')::boolean IS NULL OR is_verified = sqlc.narg('is_verified'))
+  AND (sqlc.narg('is_locked')::boolean IS NULL OR is_locked = sqlc.narg('is_locked'))
+  AND (sqlc.narg('cursor_key')::text IS NULL
+    OR (LOWER(username), id) > (sqlc.narg('cursor_key')::text, sqlc.narg('cursor_id')::uuid))
+ORDER BY LOWER(username), id
+LIMIT $1;
+
+-- name: SearchUsersByRelevance :many
+-- Best match first; the cursor key is the rank of the last row
+SELECT sqlc.embed(users), GREATEST(
+    word_similarity(sqlc.arg('q')::text, username),
+    word_similarity(sqlc.arg('q')::text, email),
+    word_similarity(sqlc.arg('q')::text, COALESCE(first_name, '') || ' ' || COALESCE(last_name, ''))
+  )::real AS rank
+FROM users
+WHERE
+  (username ILIKE '%' || sqlc.arg('q') || '%'
+    OR email ILIKE '%' || sqlc.arg('q') || '%'
+    OR (COALESCE(first_name, '') || ' ' || COALESCE(last_name, '')) ILIKE '%' || sqlc.arg('q') || '%')
+  AND (sqlc.narg('search_phone_number')::text IS NULL OR phone_number ILIKE '%' || sqlc.narg('search_phone_number') || '%')
+  AND (sqlc.narg('role')::user_role IS NULL OR role = sqlc.narg('role'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('is_verified')::boolean IS NULL OR is_verified = sqlc.narg('is_verified'))
+  AND (sqlc.narg('is_locked')::boolean IS NULL OR is_locked = sqlc.narg('is_locked'))
+  AND (sqlc.narg('cursor_rank')::real IS NULL
+    OR (GREATEST(
+          word_similarity(sqlc.arg('q')::text, username),
+          word_similarity(sqlc.arg('q')::text, email),
+          word_similarity(sqlc.arg('q')::text, COALESCE(first_name, '') || ' ' || COALESCE(last_name, ''))
+        )::real, id) < (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_id')::uuid))
+ORDER BY rank DESC, id DESC
+LIMIT $1;
 
 -- name: CountUsers :one
 SELECT COUNT(*) FROM users
 WHERE
-  (sqlc.narg('search_username')::text IS NULL OR username ILIKE '%' || sqlc.narg('search_username') || '%')
-  AND (sqlc.narg('search_email')::text IS NULL OR email ILIKE '%' || sqlc.narg('search_email') || '%')
-  AND (sqlc.narg('search_phone_number')::text IS NULL OR phone_number ILIKE '%' || sqlc.narg('search_phone_number') || '%');
-
-
+  (sqlc.narg('q')::text IS NULL
+    OR username ILIKE '%' || sqlc.narg('q') || '%'
+    OR email ILIKE '%' || sqlc.narg('q') || '%'
+    OR (COALESCE(first_name, '') || ' ' || COALESCE(last_name, '')) ILIKE '%' || sqlc.narg('q') || '%')
+  AND (sqlc.narg('search_phone_number')::text IS NULL OR phone_number ILIKE '%' || sqlc.narg('search_phone_number') || '%')
+  AND (sqlc.narg('role')::user_role IS NULL OR role = sqlc.narg('role'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('is_verified')::boolean IS NULL OR is_verified = sqlc.narg('is_verified'))
+  AND (sqlc.narg('is_locked')::boolean IS NULL OR is_locked = sqlc.narg('is_locked'));
 
 -- name: PermanentlyDeleteOldSoftDeletedUsers :exec
 DELETE FROM users
 WHERE status = 'deleted'
   AND deleted_at <= NOW() - INTERVAL '40 days';
 
--- name: DemoteAdminToUser :exec
+-- name: DemoteAdminToUser :execrows
 UPDATE users
 SET
     role = 'user',
@@ -126,3 +206,47 @@ DELETE FROM users
 WHERE status = 'deleted'
   AND role NOT IN ('admin', 'owner')
   AND deleted_at <= NOW() - INTERVAL '40 days';
+
+
+-- name: UpdateUserEmail :exec
+UPDATE users
+SET
+    email = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetUserByVerifiedPhone :one
+SELECT * FROM users
+WHERE phone_number = $1
+  AND phone_verified_at IS NOT NULL
+LIMIT 1;
+
+-- name: MarkPhoneVerified :exec
+UPDATE users
+SET
+    phone_verified_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1
+  AND phone_number = $2;
+
+-- name: ResetPhoneVerification :exec
+UPDATE users
+SET phone_verified_at = NULL
+WHERE id = $1;
+
+-- name: ReleaseUnverifiedPhoneNumber :exec
+UPDATE users
+SET
+    phone_number = NULL,
+    updated_at = NOW()
+WHERE phone_number = $1
+  AND id != $2
+  AND phone_verified_at IS NULL;
+
+-- name: UpdateUsername :exec
+UPDATE users
+SET
+    username = $2,
+    username_changed_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1;
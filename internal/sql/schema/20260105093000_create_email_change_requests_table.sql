@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE email_change_requests (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	old_email VARCHAR(100) NOT NULL,
+	new_email VARCHAR(100) NOT NULL,
+	confirm_token TEXT NOT NULL UNIQUE,
+	revoke_token TEXT NOT NULL UNIQUE,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'revoked', 'cancelled', 'expired')),
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_email_change_requests_user_id ON email_change_requests(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_change_requests;
+-- +goose StatementEnd
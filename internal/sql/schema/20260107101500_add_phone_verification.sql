@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN phone_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Unverified numbers must not block their real owner, so uniqueness only
+-- applies once a number has been verified.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_phone_number_key;
+CREATE UNIQUE INDEX users_verified_phone_number_key ON users(phone_number) WHERE phone_verified_at IS NOT NULL;
+CREATE INDEX idx_users_phone_number ON users(phone_number);
+
+CREATE TABLE phone_verifications (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	phone_number VARCHAR(32) NOT NULL,
+	code_hash TEXT NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	verified_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_phone_verifications_user_id ON phone_verifications(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS phone_verifications;
+DROP INDEX IF EXISTS idx_users_phone_number;
+DROP INDEX IF EXISTS users_verified_phone_number_key;
+ALTER TABLE users ADD CONSTRAINT users_phone_number_key UNIQUE (phone_number);
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified_at;
+-- +goose StatementEnd
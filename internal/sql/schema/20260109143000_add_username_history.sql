@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN username_changed_at TIMESTAMP WITH TIME ZONE;
+
+-- Usernames are unique regardless of case
+CREATE UNIQUE INDEX users_username_lower_key ON users (LOWER(username));
+
+CREATE TABLE username_history (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	username VARCHAR(50) NOT NULL,
+	changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	released_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_username_history_username ON username_history (LOWER(username));
+CREATE INDEX idx_username_history_user_id ON username_history(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS username_history;
+DROP INDEX IF EXISTS users_username_lower_key;
+ALTER TABLE users DROP COLUMN IF EXISTS username_changed_at;
+-- +goose StatementEnd
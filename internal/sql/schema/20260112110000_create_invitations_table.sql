@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE invitations (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	code VARCHAR(32) NOT NULL UNIQUE,
+	created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	email VARCHAR(100),
+	note TEXT,
+	max_uses INT NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+	use_count INT NOT NULL DEFAULT 0,
+	expires_at TIMESTAMP WITH TIME ZONE,
+	revoked_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE invitation_redemptions (
+	invitation_id UUID NOT NULL REFERENCES invitations(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+	redeemed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	PRIMARY KEY (invitation_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invitation_redemptions;
+DROP TABLE IF EXISTS invitations;
+-- +goose StatementEnd
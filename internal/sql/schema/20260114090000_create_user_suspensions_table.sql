@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_suspensions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	reason_code VARCHAR(32) NOT NULL,
+	note TEXT,
+	message TEXT,
+	suspended_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	expires_at TIMESTAMP WITH TIME ZONE,
+	lifted_at TIMESTAMP WITH TIME ZONE,
+	lifted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_suspensions_user_id ON user_suspensions (user_id, created_at DESC);
+CREATE INDEX idx_user_suspensions_expires_at ON user_suspensions (expires_at)
+	WHERE lifted_at IS NULL AND expires_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_suspensions;
+-- +goose StatementEnd
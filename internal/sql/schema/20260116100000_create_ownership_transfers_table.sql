@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- There can only be one owner
+CREATE UNIQUE INDEX idx_users_single_owner ON users (role) WHERE role = 'owner';
+
+CREATE TABLE ownership_transfers (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	from_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	to_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	completed_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- At most one pending transfer at a time
+CREATE UNIQUE INDEX idx_ownership_transfers_pending ON ownership_transfers (status) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ownership_transfers;
+DROP INDEX IF EXISTS idx_users_single_owner;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE legal_documents (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	kind VARCHAR(32) NOT NULL CHECK (kind IN ('terms', 'privacy')),
+	version VARCHAR(32) NOT NULL,
+	title VARCHAR(200) NOT NULL,
+	url TEXT NOT NULL,
+	-- A mandatory version must be accepted before the API can be used again
+	mandatory BOOLEAN NOT NULL DEFAULT TRUE,
+	published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	UNIQUE (kind, version)
+);
+
+CREATE TABLE user_consents (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	kind VARCHAR(32) NOT NULL CHECK (kind IN ('terms', 'privacy', 'marketing')),
+	document_id UUID REFERENCES legal_documents(id) ON DELETE RESTRICT,
+	ip_address VARCHAR(45),
+	user_agent TEXT,
+	accepted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	withdrawn_at TIMESTAMP WITH TIME ZONE,
+	withdrawn_ip VARCHAR(45)
+);
+
+CREATE INDEX idx_user_consents_user_id ON user_consents (user_id, accepted_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_consents;
+DROP TABLE IF EXISTS legal_documents;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_profiles
+	ADD COLUMN filter_explicit BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE media
+	ADD COLUMN explicit BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE media DROP COLUMN IF EXISTS explicit;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS filter_explicit;
+-- +goose StatementEnd
@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE listener_profiles (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name VARCHAR(50) NOT NULL,
+	avatar_url TEXT,
+	is_kid BOOLEAN NOT NULL DEFAULT FALSE,
+	pin_hash TEXT,
+	is_default BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	UNIQUE (user_id, name)
+);
+
+-- Every account has exactly one default profile, used when no profile is selected
+CREATE UNIQUE INDEX idx_listener_profiles_default ON listener_profiles (user_id) WHERE is_default;
+
+INSERT INTO listener_profiles (user_id, name, is_default)
+SELECT id, username, TRUE FROM users;
+
+-- Listening activity belongs to a profile; existing rows move to the default profile
+ALTER TABLE streams ADD COLUMN profile_id UUID REFERENCES listener_profiles(id) ON DELETE CASCADE;
+ALTER TABLE likes ADD COLUMN profile_id UUID REFERENCES listener_profiles(id) ON DELETE CASCADE;
+ALTER TABLE playlists ADD COLUMN profile_id UUID REFERENCES listener_profiles(id) ON DELETE CASCADE;
+
+UPDATE streams s SET profile_id = p.id
+FROM listener_profiles p WHERE p.user_id = s.user_id AND p.is_default;
+UPDATE likes l SET profile_id = p.id
+FROM listener_profiles p WHERE p.user_id = l.user_id AND p.is_default;
+UPDATE playlists pl SET profile_id = p.id
+FROM listener_profiles p WHERE p.user_id = pl.user_id AND p.is_default;
+
+ALTER TABLE streams ALTER COLUMN profile_id SET NOT NULL;
+ALTER TABLE likes ALTER COLUMN profile_id SET NOT NULL;
+ALTER TABLE playlists ALTER COLUMN profile_id SET NOT NULL;
+
+ALTER TABLE likes DROP CONSTRAINT likes_pkey;
+ALTER TABLE likes ADD PRIMARY KEY (profile_id, media_id);
+
+CREATE INDEX idx_streams_profile_started ON streams (profile_id, started_at DESC);
+CREATE INDEX idx_playlists_profile ON playlists (profile_id);
+
+-- The profile picked on this device; refreshed access tokens keep it
+ALTER TABLE user_sessions
+	ADD COLUMN profile_id UUID REFERENCES listener_profiles(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS profile_id;
+
+DROP INDEX IF EXISTS idx_playlists_profile;
+DROP INDEX IF EXISTS idx_streams_profile_started;
+
+-- Likes of several profiles collapse onto the account
+DELETE FROM likes a USING likes b
+WHERE a.user_id = b.user_id AND a.media_id = b.media_id AND a.profile_id > b.profile_id;
+ALTER TABLE likes DROP CONSTRAINT likes_pkey;
+ALTER TABLE likes ADD PRIMARY KEY (user_id, media_id);
+
+ALTER TABLE playlists DROP COLUMN IF EXISTS profile_id;
+ALTER TABLE likes DROP COLUMN IF EXISTS profile_id;
+ALTER TABLE streams DROP COLUMN IF EXISTS profile_id;
+
+DROP TABLE IF EXISTS listener_profiles;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE privacy_level AS ENUM ('public', 'followers', 'private');
+
+-- Who can see each part of the public profile; no row means the defaults below
+CREATE TABLE user_privacy_settings (
+	user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	profile privacy_level NOT NULL DEFAULT 'public',
+	display_name privacy_level NOT NULL DEFAULT 'public',
+	avatar privacy_level NOT NULL DEFAULT 'public',
+	bio privacy_level NOT NULL DEFAULT 'public',
+	country privacy_level NOT NULL DEFAULT 'private',
+	playlists privacy_level NOT NULL DEFAULT 'public',
+	followed_artists privacy_level NOT NULL DEFAULT 'followers',
+	listening_stats privacy_level NOT NULL DEFAULT 'private',
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- Only public playlists show up on profiles
+ALTER TABLE playlists ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE playlists DROP COLUMN IF EXISTS is_public;
+DROP TABLE IF EXISTS user_privacy_settings;
+DROP TYPE IF EXISTS privacy_level;
+-- +goose StatementEnd
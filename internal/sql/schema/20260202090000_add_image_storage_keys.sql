@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Storage key prefix of uploaded renditions, NULL when the URL points elsewhere
+ALTER TABLE users ADD COLUMN avatar_key TEXT;
+ALTER TABLE albums ADD COLUMN cover_key TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE albums DROP COLUMN IF EXISTS cover_key;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
+-- +goose StatementEnd
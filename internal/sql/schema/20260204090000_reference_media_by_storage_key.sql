@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Uploaded media is referenced by storage key and served through presigned
+-- URLs; the URL columns remain for media hosted elsewhere
+ALTER TABLE songs ADD COLUMN audio_key TEXT;
+ALTER TABLE songs ALTER COLUMN audio_url DROP NOT NULL;
+ALTER TABLE songs ADD CONSTRAINT songs_audio_source_check
+	CHECK (audio_key IS NOT NULL OR audio_url IS NOT NULL);
+
+ALTER TABLE videos ADD COLUMN video_key TEXT;
+ALTER TABLE videos ALTER COLUMN video_url DROP NOT NULL;
+ALTER TABLE videos ADD CONSTRAINT videos_video_source_check
+	CHECK (video_key IS NOT NULL OR video_url IS NOT NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE videos DROP CONSTRAINT IF EXISTS videos_video_source_check;
+UPDATE videos SET video_url = video_key WHERE video_url IS NULL;
+ALTER TABLE videos ALTER COLUMN video_url SET NOT NULL;
+ALTER TABLE videos DROP COLUMN IF EXISTS video_key;
+
+ALTER TABLE songs DROP CONSTRAINT IF EXISTS songs_audio_source_check;
+UPDATE songs SET audio_url = audio_key WHERE audio_url IS NULL;
+ALTER TABLE songs ALTER COLUMN audio_url SET NOT NULL;
+ALTER TABLE songs DROP COLUMN IF EXISTS audio_key;
+-- +goose StatementEnd
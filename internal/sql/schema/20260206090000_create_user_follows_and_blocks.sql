@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE follow_status AS ENUM ('pending', 'accepted');
+
+-- Users following other users; artist follows stay in follows
+CREATE TABLE user_follows (
+	follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	status follow_status NOT NULL DEFAULT 'accepted',
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	accepted_at TIMESTAMP WITH TIME ZONE,
+	PRIMARY KEY (follower_id, followee_id),
+	CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX idx_user_follows_followee ON user_follows (followee_id, status, created_at DESC, follower_id DESC);
+CREATE INDEX idx_user_follows_follower ON user_follows (follower_id, status, created_at DESC, followee_id DESC);
+
+CREATE TABLE user_blocks (
+	blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	PRIMARY KEY (blocker_id, blocked_id),
+	CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked ON user_blocks (blocked_id);
+
+-- New followers wait for approval instead of being accepted straight away
+ALTER TABLE user_privacy_settings ADD COLUMN require_follow_approval BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_privacy_settings DROP COLUMN IF EXISTS require_follow_approval;
+DROP TABLE IF EXISTS user_blocks;
+DROP TABLE IF EXISTS user_follows;
+DROP TYPE IF EXISTS follow_status;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The preferences document of each user; no row means the defaults.
+-- revision increases on every write and backs the ETag.
+CREATE TABLE user_preferences (
+	user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	version INT NOT NULL,
+	document JSONB NOT NULL,
+	revision BIGINT NOT NULL DEFAULT 1,
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_subscriptions_user_id ON subscriptions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscriptions_user_id;
+DROP TABLE IF EXISTS user_preferences;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE bulk_job_status AS ENUM ('pending', 'running', 'completed');
+CREATE TYPE bulk_item_status AS ENUM ('pending', 'done', 'skipped', 'failed');
+
+-- Admin operations over many users, run in the background by the bulk job runner
+CREATE TABLE bulk_user_jobs (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	-- lock, unlock, set_role, delete, logout or import
+	action VARCHAR(20) NOT NULL,
+	-- The role set by set_role
+	role user_role,
+	-- Dry runs only report what would change
+	dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+	status bulk_job_status NOT NULL DEFAULT 'pending',
+	created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	started_at TIMESTAMP WITH TIME ZONE,
+	-- Bumped while the job runs; a stale heartbeat means the runner died and the job can be resumed
+	heartbeat_at TIMESTAMP WITH TIME ZONE,
+	finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_bulk_user_jobs_created_at ON bulk_user_jobs (created_at DESC);
+CREATE INDEX idx_bulk_user_jobs_unfinished ON bulk_user_jobs (created_at) WHERE status <> 'completed';
+
+-- One row per targeted user, or per CSV row for imports
+CREATE TABLE bulk_user_job_items (
+	job_id UUID NOT NULL REFERENCES bulk_user_jobs(id) ON DELETE CASCADE,
+	position INT NOT NULL,
+	user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+	-- Import rows
+	email VARCHAR(255),
+	role user_role,
+	note TEXT,
+	status bulk_item_status NOT NULL DEFAULT 'pending',
+	message TEXT,
+	processed_at TIMESTAMP WITH TIME ZONE,
+	PRIMARY KEY (job_id, position)
+);
+
+-- Role granted when an invitation is redeemed, for pre-provisioned accounts
+ALTER TABLE invitations ADD COLUMN role user_role;
+CREATE INDEX idx_invitations_email ON invitations (email) WHERE email IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_invitations_email;
+ALTER TABLE invitations DROP COLUMN IF EXISTS role;
+DROP TABLE IF EXISTS bulk_user_job_items;
+DROP TABLE IF EXISTS bulk_user_jobs;
+DROP TYPE IF EXISTS bulk_item_status;
+DROP TYPE IF EXISTS bulk_job_status;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Storage key prefix of uploaded avatar renditions, NULL when avatar_url points elsewhere
+ALTER TABLE artists ADD COLUMN avatar_key TEXT;
+
+-- Artist search and the alphabetical list
+CREATE INDEX idx_artists_name_trgm ON artists USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_artists_lower_name ON artists (LOWER(name), id);
+
+-- Follower and play counts are aggregated per artist
+CREATE INDEX idx_follows_artist_id ON follows (artist_id);
+CREATE INDEX idx_media_artist_id ON media (artist_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_media_artist_id;
+DROP INDEX IF EXISTS idx_follows_artist_id;
+DROP INDEX IF EXISTS idx_artists_lower_name;
+DROP INDEX IF EXISTS idx_artists_name_trgm;
+ALTER TABLE artists DROP COLUMN IF EXISTS avatar_key;
+-- +goose StatementEnd
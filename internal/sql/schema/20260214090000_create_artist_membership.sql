@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE artist_member_role AS ENUM ('owner', 'manager', 'editor');
+
+-- Free-form roles from before become editors, the least privileged role
+UPDATE artist_members SET role = 'editor'
+WHERE role IS NULL OR role NOT IN ('owner', 'manager', 'editor');
+ALTER TABLE artist_members
+	ALTER COLUMN role TYPE artist_member_role USING role::artist_member_role,
+	ALTER COLUMN role SET NOT NULL;
+
+CREATE INDEX idx_artist_members_user ON artist_members (user_id);
+
+-- Invitations of existing users into an artist, sent by its owners
+CREATE TABLE artist_member_invites (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	role artist_member_role NOT NULL,
+	invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	UNIQUE (artist_id, user_id)
+);
+
+CREATE INDEX idx_artist_member_invites_user ON artist_member_invites (user_id);
+
+CREATE TYPE artist_claim_status AS ENUM ('pending', 'approved', 'rejected');
+
+-- Users claiming an artist nobody owns yet; an approved claim makes the user its owner
+CREATE TABLE artist_claims (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	message TEXT,
+	status artist_claim_status NOT NULL DEFAULT 'pending',
+	reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	review_note TEXT,
+	reviewed_at TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_artist_claims_pending_user ON artist_claims (artist_id, user_id) WHERE status = 'pending';
+CREATE INDEX idx_artist_claims_queue ON artist_claims (created_at, id) WHERE status = 'pending';
+
+-- The artist a session acts for; refreshed access tokens keep it
+ALTER TABLE user_sessions ADD COLUMN artist_id UUID REFERENCES artists(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS artist_id;
+DROP TABLE IF EXISTS artist_claims;
+DROP TYPE IF EXISTS artist_claim_status;
+DROP TABLE IF EXISTS artist_member_invites;
+DROP INDEX IF EXISTS idx_artist_members_user;
+ALTER TABLE artist_members ALTER COLUMN role DROP NOT NULL;
+ALTER TABLE artist_members ALTER COLUMN role TYPE VARCHAR(50) USING role::text;
+DROP TYPE IF EXISTS artist_member_role;
+-- +goose StatementEnd
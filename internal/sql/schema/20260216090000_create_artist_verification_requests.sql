@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE verification_status AS ENUM ('pending', 'needs_info', 'approved', 'rejected');
+
+-- Requests by artist members for the verified badge, reviewed by admins
+CREATE TABLE artist_verification_requests (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+	submitted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	evidence_links TEXT[] NOT NULL,
+	note TEXT,
+	status verification_status NOT NULL DEFAULT 'pending',
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- An artist has at most one open request
+CREATE UNIQUE INDEX idx_artist_verification_open ON artist_verification_requests (artist_id)
+	WHERE status IN ('pending', 'needs_info');
+CREATE INDEX idx_artist_verification_queue ON artist_verification_requests (status, updated_at, id);
+
+-- Status changes and comments on a request, oldest first
+CREATE TABLE artist_verification_events (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	request_id UUID NOT NULL REFERENCES artist_verification_requests(id) ON DELETE CASCADE,
+	actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+	-- from_status is NULL on submission, both are NULL on plain comments
+	from_status verification_status,
+	to_status verification_status,
+	comment TEXT,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_artist_verification_events_request ON artist_verification_events (request_id, created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS artist_verification_events;
+DROP TABLE IF EXISTS artist_verification_requests;
+DROP TYPE IF EXISTS verification_status;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE album_release_type AS ENUM ('album', 'ep', 'single', 'compilation');
+
+ALTER TABLE albums
+	ADD COLUMN release_type album_release_type NOT NULL DEFAULT 'album',
+	-- UPC-A or EAN-13 barcode of the release
+	ADD COLUMN upc VARCHAR(13) CHECK (upc ~ '^[0-9]{12,13}$'),
+	ADD COLUMN label VARCHAR(200),
+	-- The © line covers the artwork and text, the ℗ line the sound recordings
+	ADD COLUMN copyright_line VARCHAR(300),
+	ADD COLUMN phonographic_line VARCHAR(300),
+	ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+
+CREATE UNIQUE INDEX idx_albums_upc ON albums (upc) WHERE upc IS NOT NULL;
+CREATE INDEX idx_albums_artist_created_at ON albums (artist_id, created_at DESC, id DESC);
+
+ALTER TABLE songs
+	ADD COLUMN disc_number INT NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+	ADD COLUMN track_number INT CHECK (track_number > 0),
+	ADD CONSTRAINT songs_track_needs_album_check CHECK (album_id IS NOT NULL OR track_number IS NULL);
+
+CREATE UNIQUE INDEX idx_songs_album_track ON songs (album_id, disc_number, track_number)
+	WHERE track_number IS NOT NULL;
+
+-- Deleting an album used to leave its songs behind without a word;
+-- the tracks now have to be detached explicitly first
+ALTER TABLE songs DROP CONSTRAINT songs_album_id_fkey;
+ALTER TABLE songs ADD CONSTRAINT songs_album_id_fkey
+	FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP CONSTRAINT songs_album_id_fkey;
+ALTER TABLE songs ADD CONSTRAINT songs_album_id_fkey
+	FOREIGN KEY (album_id) REFERENCES albums(id) ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS idx_songs_album_track;
+ALTER TABLE songs DROP CONSTRAINT IF EXISTS songs_track_needs_album_check;
+ALTER TABLE songs DROP COLUMN IF EXISTS track_number;
+ALTER TABLE songs DROP COLUMN IF EXISTS disc_number;
+
+DROP INDEX IF EXISTS idx_albums_artist_created_at;
+DROP INDEX IF EXISTS idx_albums_upc;
+ALTER TABLE albums
+	DROP COLUMN IF EXISTS updated_at,
+	DROP COLUMN IF EXISTS phonographic_line,
+	DROP COLUMN IF EXISTS copyright_line,
+	DROP COLUMN IF EXISTS label,
+	DROP COLUMN IF EXISTS upc,
+	DROP COLUMN IF EXISTS release_type;
+
+DROP TYPE IF EXISTS album_release_type;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE media
+	ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	-- Taken down media is hidden from everyone but admins and the artist's members
+	ADD COLUMN taken_down_at TIMESTAMP WITH TIME ZONE,
+	ADD COLUMN takedown_reason TEXT;
+
+CREATE INDEX idx_media_created_at ON media (created_at DESC, id DESC);
+CREATE INDEX idx_songs_album_id ON songs (album_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_songs_album_id;
+DROP INDEX IF EXISTS idx_media_created_at;
+ALTER TABLE media
+	DROP COLUMN IF EXISTS takedown_reason,
+	DROP COLUMN IF EXISTS taken_down_at,
+	DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newLocal(t *testing.T) *Local {
+	t.Helper()
+	l, err := NewLocal(Config{Dir: t.TempDir(), BaseURL: "http://media.test/media/", Secret: "secret", PublicPrefixes: []string{"avatars/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLocalRoundTrip(t *testing.T) {
+	l := newLocal(t)
+	ctx := context.Background()
+
+	obj, err := PutContent(ctx, l, "audio", ".mp3", strings.NewReader("song"), "audio/mpeg")
+	if err != nil {
+		t.Fatalf("PutContent: %v", err)
+	}
+	again, err := PutContent(ctx, l, "audio", ".mp3", strings.NewReader("song"), "audio/mpeg")
+	if err != nil || again.Key != obj.Key {
+		t.Fatalf("same content got key %q, want %q (%v)", again.Key, obj.Key, err)
+	}
+	if !strings.HasPrefix(obj.Key, "audio/") || !strings.HasSuffix(obj.Key, ".mp3") || obj.Size != 4 {
+		t.Errorf("unexpected object %+v", obj)
+	}
+
+	stat, err := l.Stat(ctx, obj.Key)
+	if err != nil || stat.Size != 4 || stat.ContentType != "audio/mpeg" {
+		t.Errorf("Stat = %+v, %v", stat, err)
+	}
+	if objects, err := l.List(ctx, "audio/"); err != nil || len(objects) != 1 || objects[0].Key != obj.Key {
+		t.Errorf("List = %+v, %v", objects, err)
+	}
+
+	if err := l.Delete(ctx, obj.Key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, _, err := l.Get(ctx, obj.Key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	l := newLocal(t)
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", `a\b`} {
+		if err := l.Put(context.Background(), key, strings.NewReader("x"), ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestLocalHandlerNeedsSignatureOutsidePublicPrefixes(t *testing.T) {
+	l := newLocal(t)
+	ctx := context.Background()
+	for _, key := range []string{"avatars/a.jpg", "audio/b.mp3"} {
+		if err := l.Put(ctx, key, strings.NewReader("x"), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := http.StripPrefix("/media", l.Handler())
+	get := func(target string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		return rec.Code
+	}
+
+	if code := get("/media/avatars/a.jpg"); code != http.StatusOK {
+		t.Errorf("public blob: %d", code)
+	}
+	if code := get("/media/audio/b.mp3"); code != http.StatusForbidden {
+		t.Errorf("unsigned private blob: %d", code)
+	}
+
+	signed, err := l.Presign("audio/b.mp3", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(signed)
+	if code := get(u.RequestURI()); code != http.StatusOK {
+		t.Errorf("signed private blob: %d", code)
+	}
+
+	l.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if code := get(u.RequestURI()); code != http.StatusForbidden {
+		t.Errorf("expired signature: %d", code)
+	}
+}
@@ -0,0 +1,147 @@
+// Package storage keeps uploaded files somewhere the API can hand out URLs for.
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidKey is returned for keys that are empty, absolute or escape the storage root
+	ErrInvalidKey = errors.New("invalid storage key")
+	// ErrNotFound is returned when no blob is stored under a key
+	ErrNotFound = errors.New("blob not found")
+)
+
+// Object describes a stored blob
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ETag        string
+	ModTime     time.Time
+}
+
+// Storage stores blobs under slash-separated keys such as "avatars/<id>/256.jpg"
+type Storage interface {
+	// Put stores body under key, replacing any existing blob
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	// Get opens the blob under key; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Stat describes the blob under key without reading it
+	Stat(ctx context.Context, key string) (Object, error)
+	// Delete removes the blob under key; missing blobs are not an error
+	Delete(ctx context.Context, key string) error
+	// List returns the blobs whose keys start with prefix, sorted by key
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// Presign returns a URL that grants read access to key until ttl elapses
+	Presign(key string, ttl time.Duration) (string, error)
+	// URL is where clients can fetch the blob under key when it is public
+	URL(key string) string
+}
+
+// Config selects and configures a backend
+type Config struct {
+	// Backend is "local" (default) or "s3"
+	Backend string
+	// BaseURL is the public URL of the blob root
+	BaseURL string
+	// PublicPrefixes are served without a presigned URL by the local backend.
+	// S3 buckets need a matching bucket policy.
+	PublicPrefixes []string
+
+	// Dir is the local backend's root directory
+	Dir string
+	// Secret signs the local backend's presigned URLs
+	Secret string
+
+	// S3 settings; any S3-compatible endpoint such as MinIO works
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket as endpoint/bucket instead of bucket.endpoint
+	PathStyle bool
+}
+
+// New returns the backend selected by cfg.Backend
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocal(cfg)
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// ContentKey derives a key from the content's SHA-256, so identical files share
+// a key and a key never points at different content
+func ContentKey(prefix string, sum [sha256.Size]byte, ext string) string {
+	h := hex.EncodeToString(sum[:])
+	return path.Join(prefix, h[:2], h) + ext
+}
+
+// PutContent stores body under its content key and returns the stored object.
+// The body is spooled to a temporary file to hash it before upload; content
+// that is already stored is not uploaded again.
+func PutContent(ctx context.Context, s Storage, prefix, ext string, body io.Reader, contentType string) (Object, error) {
+	tmp, err := os.CreateTemp("", "streamify-upload-*")
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), body)
+	if err != nil {
+		return Object{}, err
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	key := ContentKey(prefix, sum, ext)
+
+	if obj, err := s.Stat(ctx, key); err == nil && obj.Size == size {
+		return obj, nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return Object{}, err
+	}
+	if err := s.Put(ctx, key, tmp, contentType); err != nil {
+		return Object{}, err
+	}
+	return Object{Key: key, Size: size, ContentType: contentType}, nil
+}
+
+// cleanKey rejects keys that could reach outside the storage root
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+func isPublic(prefixes []string, key string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,17 +20,23 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/techies/streamify/internal/database"
 )
 
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// Code is a machine-readable reason for errors clients need to act on
+	Code string `json:"code,omitempty"`
+	// Fields maps request fields to what is wrong with them
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 type AppError struct {
-	Code    int    // HTTP Code
-	Message string // Client-facing message
-	Err     error  // The actual internal error (for logging)
+	Code    int               // HTTP Code
+	Message string            // Client-facing message
+	Err     error             // The actual internal error (for logging)
+	Fields  map[string]string // Client-facing per-field errors
 }
 
 func (e *AppError) Error() string {
@@ -64,6 +71,16 @@ func RespondWithError(w http.ResponseWriter, code int, msg string, err ...error)
 	RespondWithJSON(w, code, ErrorResponse{Error: fullMsg})
 }
 
+// RespondWithErrorCode is RespondWithError for errors that carry a machine-readable code
+func RespondWithErrorCode(w http.ResponseWriter, status int, code, msg string) {
+	RespondWithJSON(w, status, ErrorResponse{Error: msg, Code: code})
+}
+
+// RespondWithFieldErrors is RespondWithError for validation errors that name the offending fields
+func RespondWithFieldErrors(w http.ResponseWriter, status int, msg string, fields map[string]string) {
+	RespondWithJSON(w, status, ErrorResponse{Error: msg, Fields: fields})
+}
+
 func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -105,6 +122,35 @@ func GetEnvString(key, defaultValue string) string {
 	return value
 }
 
+// GetEnvInt parses an integer from the environment
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvDuration parses a duration such as "720h" from the environment
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvList splits a comma-separated environment variable, dropping empty items
+func GetEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // ParseJSON decodes the request body into the provided data structure.
 // It limits the body size to 1MB to prevent memory exhaustion attacks.
 // ... existing code ...
@@ -129,6 +175,95 @@ func ParseJSON(w http.ResponseWriter, r *http.Request, data interface{}) error {
 	return nil
 }
 
+var (
+	// ErrUploadTooLarge is returned by ReadUpload when the file exceeds its size limit
+	ErrUploadTooLarge = errors.New("uploaded file is too large")
+	// ErrNotMultipart is returned by ReadUpload for requests that aren't multipart/form-data
+	ErrNotMultipart = errors.New("request must be multipart/form-data")
+	// ErrMissingUpload is returned by ReadUpload when the form has no such file field
+	ErrMissingUpload = errors.New("no file was uploaded")
+)
+
+// multipartOverhead leaves room for part headers and boundaries around the file
+const multipartOverhead = 64 * 1024
+
+// ReadUpload reads the file in form field `field` of a multipart/form-data request.
+// Uploads have their own size limit, ParseJSON's 1MB is meant for JSON bodies.
+func ReadUpload(w http.ResponseWriter, r *http.Request, field string, maxBytes int64) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrMissingUpload
+		}
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				return nil, ErrUploadTooLarge
+			}
+			return nil, err
+		}
+		if part.FormName() != field || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+		part.Close()
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) || int64(len(data)) > maxBytes {
+			return nil, ErrUploadTooLarge
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, ErrMissingUpload
+		}
+		return data, nil
+	}
+}
+
+// RespondWithUploadError maps ReadUpload errors to their HTTP status
+func RespondWithUploadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUploadTooLarge):
+		RespondWithError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, ErrNotMultipart):
+		RespondWithError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, ErrMissingUpload):
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Invalid upload", err)
+	}
+}
+
+// ETagMatches reports whether an If-Match or If-None-Match header value lists etag.
+// If-Match needs the strong comparison, If-None-Match the weak one (RFC 9110, 8.8.3.2).
+func ETagMatches(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
 // WriteJSON is a helper to send JSON responses back to the client
 func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -139,7 +274,9 @@ func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
-// Internal helper for token generation
+// Internal helper for token generation.
+// A non-nil profileID scopes the token to a listener profile through the "pid" claim,
+// a non-nil artistID lets it act for an artist the user is a member of through "aid".
 func GenerateToken(
 	userID uuid.UUID,
 	sessionID uuid.UUID,
@@ -150,6 +287,8 @@ func GenerateToken(
 	lastName string,
 	phoneNumber string,
 	email string,
+	profileID uuid.UUID,
+	artistID uuid.UUID,
 ) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":          userID.String(),
@@ -162,6 +301,12 @@ func GenerateToken(
 		"phone_number": phoneNumber,
 		"email":        email,
 	}
+	if profileID != uuid.Nil {
+		claims["pid"] = profileID.String()
+	}
+	if artistID != uuid.Nil {
+		claims["aid"] = artistID.String()
+	}
 	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(JWTSecret))
 }
 
@@ -170,6 +315,18 @@ func NormalizeEmail(email string) string {
 	return strings.ToLower(strings.TrimSpace(email))
 }
 
+// IsUniqueViolation reports whether err is a Postgres unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// IsForeignKeyViolation reports whether err is a Postgres foreign key violation
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
 // toNullString converts a string to sql.NullString
 func ToNullString(s *string) sql.NullString {
 	if s == nil {
@@ -183,6 +340,14 @@ func ToNullString(s *string) sql.NullString {
 	}
 }
 
+// ToNullBool converts an optional bool to sql.NullBool
+func ToNullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{Valid: false}
+	}
+	return sql.NullBool{Bool: *b, Valid: true}
+}
+
 // normalizeIP strips port if present
 func NormalizeIP(addr string) string {
 	host, _, err := net.SplitHostPort(addr)